@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,22 +11,76 @@ import (
 	"tender_service/internal/handlers/bids/bid_feedback"
 	"tender_service/internal/handlers/bids/bid_submit_decision"
 	"tender_service/internal/handlers/bids/bids_rollback"
+	"tender_service/internal/handlers/bids/delete_bid"
+	"tender_service/internal/handlers/bids/get_bid_messages"
 	"tender_service/internal/handlers/bids/get_bid_status"
+	"tender_service/internal/handlers/bids/get_bid_versions"
 	"tender_service/internal/handlers/bids/get_bids"
+	"tender_service/internal/handlers/bids/get_deleted_bids"
 	"tender_service/internal/handlers/bids/get_my_bids"
 	"tender_service/internal/handlers/bids/get_reviews"
 	"tender_service/internal/handlers/bids/new"
+	"tender_service/internal/handlers/bids/new_bid_message"
+	"tender_service/internal/handlers/bids/new_offer"
 	"tender_service/internal/handlers/bids/patch_bid"
+	"tender_service/internal/handlers/bids/patch_bid_message"
 	"tender_service/internal/handlers/bids/put_bid_status"
+	"tender_service/internal/handlers/bids/restore_bid"
+	"tender_service/internal/handlers/bids/resubmit_bid"
+	"tender_service/internal/handlers/bids/withdraw_bid"
+	"tender_service/internal/handlers/categories/delete_category"
+	"tender_service/internal/handlers/categories/get_categories"
+	"tender_service/internal/handlers/categories/new_category"
+	"tender_service/internal/handlers/categories/patch_category"
+	"tender_service/internal/handlers/employees/get_my_organizations"
+	"tender_service/internal/handlers/employees/get_notification_settings"
+	"tender_service/internal/handlers/employees/put_notification_settings"
+	"tender_service/internal/handlers/events/stream_events"
+	"tender_service/internal/handlers/organizations/get_members"
+	"tender_service/internal/handlers/organizations/get_reputation"
+	"tender_service/internal/handlers/organizations/put_member_role"
 	"tender_service/internal/handlers/ping"
+	"tender_service/internal/handlers/tenders/answer_question"
+	"tender_service/internal/handlers/tenders/clone_tender"
+	"tender_service/internal/handlers/tenders/delete_invitation"
+	"tender_service/internal/handlers/tenders/delete_template"
+	"tender_service/internal/handlers/tenders/delete_tender"
+	"tender_service/internal/handlers/tenders/get_auction"
+	"tender_service/internal/handlers/tenders/get_deleted_tenders"
+	"tender_service/internal/handlers/tenders/get_invitations"
+	"tender_service/internal/handlers/tenders/get_lots"
 	"tender_service/internal/handlers/tenders/get_my_tenders"
+	"tender_service/internal/handlers/tenders/get_questions"
+	"tender_service/internal/handlers/tenders/get_templates"
 	"tender_service/internal/handlers/tenders/get_tender_status"
+	"tender_service/internal/handlers/tenders/get_tender_versions"
 	"tender_service/internal/handlers/tenders/get_tenders"
+	"tender_service/internal/handlers/tenders/import_tenders"
+	"tender_service/internal/handlers/tenders/new_invitation"
+	"tender_service/internal/handlers/tenders/new_lot"
+	"tender_service/internal/handlers/tenders/new_question"
+	"tender_service/internal/handlers/tenders/new_template"
 	"tender_service/internal/handlers/tenders/new_tender"
+	"tender_service/internal/handlers/tenders/new_tender_from_template"
 	"tender_service/internal/handlers/tenders/patch_tender_status"
+	"tender_service/internal/handlers/tenders/put_auction"
+	"tender_service/internal/handlers/tenders/put_lot_status"
 	"tender_service/internal/handlers/tenders/put_tender_status"
+	"tender_service/internal/handlers/tenders/restore_tender"
 	"tender_service/internal/handlers/tenders/tenders_rollback"
+	"tender_service/internal/lib/cache"
+	"tender_service/internal/lib/notify"
+	"tender_service/internal/lib/ratelimit"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/mail"
+	"tender_service/internal/middleware/etag"
+	"tender_service/internal/middleware/idempotency"
+	mwratelimit "tender_service/internal/middleware/ratelimit"
+	"tender_service/internal/middleware/timeout"
+	"tender_service/internal/rpc"
+	"tender_service/internal/service"
 	psq "tender_service/internal/storage"
+	"tender_service/internal/storage/cached"
 	"time"
 
 	chi "github.com/go-chi/chi/v5"
@@ -33,17 +88,49 @@ import (
 	"tender_service/internal/config"
 )
 
+// httpTimeout is the read and write timeout of the HTTP server, a route
+// living past it still gets as much to send the response after its deadline.
+const httpTimeout = 15 * time.Second
+
 func main() {
 	cfg := config.Load()
 	storage := &psq.Storage{}
+	hub := notify.NewHub()
+
+	var repo service.Repository = storage
+	if cfg.Cache.Size > 0 {
+		repo = cached.New(storage, cache.NewLRU(cfg.Cache.Size), cfg.Cache.TTL, cfg.Cache.LookupTTL)
+	}
+	svc := service.New(repo, hub, cfg)
 
+	// ctx is canceled once the storage is ready, workersCtx once the
+	// server shuts down
 	ctx, cancel := context.WithCancel(context.Background())
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
 	log := setuplogger()
+
+	templates, err := mail.LoadTemplates(cfg.Mail.Locale)
+	if err != nil {
+		panic(err.Error())
+	}
+
 	go func() {
 		err := psq.New(cancel, storage, cfg)
 		if err != nil {
 			log.Error("failed to init storage", slog.String("error", err.Error()))
+			return
+		}
+		go purgeIdempotencyKeys(log, storage)
+		go listenEvents(workersCtx, log, cfg.DB.DSN(), hub)
+		go purgeEvents(log, storage, cfg.EventRetention)
+		go notifyClosingTenders(log, svc, cfg.Mail.ClosingWindow)
+		go purgeNotifications(log, storage, cfg.Mail.Retention)
+		if cfg.Mail.Host != "" {
+			go mail.NewWorker(storage, mail.NewSMTP(cfg.Mail), templates, cfg.Mail, log).Run(workersCtx)
+		} else {
+			log.Warn("SMTP_HOST is not set, notifications are only queued")
 		}
+		purgeDeleted(log, storage, cfg.DeletedRetention)
 	}()
 
 	router := chi.NewRouter()
@@ -53,29 +140,144 @@ func main() {
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		response.Render(w, r, response.ErrRouteNotExists)
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		response.Render(w, r, response.ErrMethodNotAllowed)
+	})
+
+	deadline := func(route string) func(http.Handler) http.Handler {
+		return timeout.New(cfg.Timeouts.For(route))
+	}
+
+	limiter := ratelimit.NewMemory()
+	// the keys are resolved before the limit is checked, for every request
+	var resolver mwratelimit.Resolver = svc
+	if cfg.Cache.Size > 0 {
+		resolver = mwratelimit.Cached(svc, cache.NewLRU(cfg.Cache.Size), cfg.Cache.LookupTTL)
+	}
+	limit := func(group string) func(http.Handler) http.Handler {
+		rl, ok := cfg.RateLimits[group]
+		if !ok || rl.Burst <= 0 {
+			return func(next http.Handler) http.Handler { return next }
+		}
+		byKey := mwratelimit.New(limiter, group, ratelimit.Limit{Rate: rl.Rate, Burst: rl.Burst}, mwratelimit.Key(rl.Key, resolver))
+		if rl.Key == mwratelimit.KeyIP || rl.IPBurst <= 0 {
+			return byKey
+		}
+
+		// the user and organization buckets are shared by all clients, the
+		// IP one keeps a single client from spending them alone
+		byIP := mwratelimit.New(limiter, group+".ip", ratelimit.Limit{Rate: rl.IPRate, Burst: rl.IPBurst}, mwratelimit.ByIP())
+		return func(next http.Handler) http.Handler {
+			return byIP(byKey(next))
+		}
+	}
+
+	// idempotent goes after the rate limit of each group, so that a rejected
+	// request is not replayed as the outcome of its key
+	idempotent := idempotency.New(storage, cfg.IdempotencyTTL)
+
 	router.Route("/api", func(r chi.Router) {
 		r.Route("/tenders", func(r chi.Router) {
-			r.Post("/new", new_tender.New(storage))
-			r.Get("/{tenderId}/status", gettenderstatus.New(storage))
-			r.Put("/{tenderId}/status", puttenderstatus.New(storage))
-			r.Patch("/{tenderId}/edit", patchtenderstatus.New(storage))
-			r.Get("/", gettenders.New(storage))
-			r.Get("/my", getmytenders.New(storage))
-			r.Put("/{tenderId}/rollback/{version}", tendersrollback.New(storage))
+			// a large import outlives the server's timeouts, its connection
+			// gets the route deadline before idempotent reads the body
+			r.With(deadline("tenders.import"), timeout.Connection(httpTimeout), limit("tenders"), idempotent).
+				Post("/import", importtenders.New(svc))
+
+			r.Group(func(r chi.Router) {
+				r.Use(limit("tenders"), idempotent)
 
+				r.With(deadline("tenders.new")).Post("/new", new_tender.New(svc))
+				r.With(deadline("tenders.new")).Post("/{tenderId}/clone", clonetender.New(svc))
+				r.With(deadline("tenders.new")).Post("/from-template/{templateId}", tenderfromtemplate.New(svc))
+				r.With(deadline("tenders.templates")).Get("/templates", gettemplates.New(svc))
+				r.With(deadline("tenders.templates")).Post("/templates", newtemplate.New(svc))
+				r.With(deadline("tenders.templates")).Delete("/templates/{templateId}", deletetemplate.New(svc))
+				r.With(deadline("tenders.status"), etag.New).Get("/{tenderId}/status", gettenderstatus.New(svc))
+				r.With(deadline("tenders.status_put")).Put("/{tenderId}/status", puttenderstatus.New(svc))
+				r.With(deadline("tenders.edit")).Patch("/{tenderId}/edit", patchtenderstatus.New(svc))
+				r.With(deadline("tenders.list"), etag.New).Get("/", gettenders.New(svc))
+				r.With(deadline("tenders.my")).Get("/my", getmytenders.New(svc))
+				r.With(deadline("tenders.rollback")).Put("/{tenderId}/rollback/{version}", tendersrollback.New(svc))
+				r.With(deadline("tenders.versions")).Get("/{tenderId}/versions", gettenderversions.New(svc))
+				r.With(deadline("tenders.invitations")).Get("/{tenderId}/invitations", getinvitations.New(svc))
+				r.With(deadline("tenders.invitations")).Post("/{tenderId}/invitations", newinvitation.New(svc))
+				r.With(deadline("tenders.invitations")).Delete("/{tenderId}/invitations/{invitationId}", deleteinvitation.New(svc))
+				r.With(deadline("tenders.lots")).Get("/{tenderId}/lots", getlots.New(svc))
+				r.With(deadline("tenders.lots")).Post("/{tenderId}/lots", newlot.New(svc))
+				r.With(deadline("tenders.lots")).Put("/{tenderId}/lots/{lotId}/status", putlotstatus.New(svc))
+				r.With(deadline("tenders.auction")).Get("/{tenderId}/auction", getauction.New(svc))
+				r.With(deadline("tenders.auction")).Put("/{tenderId}/auction", putauction.New(svc))
+				r.With(deadline("tenders.questions")).Get("/{tenderId}/questions", getquestions.New(svc))
+				r.With(deadline("tenders.questions")).Post("/{tenderId}/questions", newquestion.New(svc))
+				r.With(deadline("tenders.questions")).Put("/{tenderId}/questions/{questionId}/answer", answerquestion.New(svc))
+				r.With(deadline("tenders.delete")).Delete("/{tenderId}", deletetender.New(svc))
+				r.With(deadline("tenders.restore")).Post("/{tenderId}/restore", restoretender.New(svc))
+				r.With(deadline("tenders.deleted")).Get("/deleted", getdeletedtenders.New(svc))
+			})
 		})
 
 		r.Route("/bids", func(r chi.Router) {
-			r.Post("/new", newbid.New(storage))
-			r.Get("/{bidId}/status", getbidstatus.New(storage))
-			r.Put("/{bidId}/status", putbidstatus.New(storage))
-			r.Patch("/{bidId}/edit", patchbid.New(storage))
-			r.Get("/my", getmybids.New(storage))
-			r.Get("/{tenderId}/list", getbids.New(storage))
-			r.Put("/{bidId}/submit_decision", bidsubmitdecision.New(storage))
-			r.Put("/{bidId}/feedback", bidfeedback.New(storage))
-			r.Get("/{tenderId}/reviews", getreviews.New(storage))
-			r.Put("/{bidId}/rollback/{version}", bidsrollback.New(storage))
+			r.Use(limit("bids"), idempotent)
+
+			r.With(deadline("bids.new")).Post("/new", newbid.New(svc))
+			r.With(deadline("bids.status")).Get("/{bidId}/status", getbidstatus.New(svc))
+			r.With(deadline("bids.status_put")).Put("/{bidId}/status", putbidstatus.New(svc))
+			r.With(deadline("bids.withdraw")).Post("/{bidId}/withdraw", withdrawbid.New(svc))
+			r.With(deadline("bids.resubmit")).Post("/{bidId}/resubmit", resubmitbid.New(svc))
+			r.With(deadline("bids.offers")).Post("/{bidId}/offers", newoffer.New(svc))
+			r.With(deadline("bids.edit")).Patch("/{bidId}/edit", patchbid.New(svc))
+			r.With(deadline("bids.my")).Get("/my", getmybids.New(svc))
+			r.With(deadline("bids.list")).Get("/{tenderId}/list", getbids.New(svc))
+			r.With(deadline("bids.submit_decision")).Put("/{bidId}/submit_decision", bidsubmitdecision.New(svc))
+			r.With(deadline("bids.feedback")).Put("/{bidId}/feedback", bidfeedback.New(svc))
+			r.With(deadline("bids.reviews")).Get("/{tenderId}/reviews", getreviews.New(svc))
+			r.With(deadline("bids.rollback")).Put("/{bidId}/rollback/{version}", bidsrollback.New(svc))
+			r.With(deadline("bids.versions")).Get("/{bidId}/versions", getbidversions.New(svc))
+			r.With(deadline("bids.messages")).Get("/{bidId}/messages", getbidmessages.New(svc))
+			r.With(deadline("bids.messages")).Post("/{bidId}/messages", newbidmessage.New(svc))
+			r.With(deadline("bids.messages")).Patch("/{bidId}/messages/{messageId}", patchbidmessage.New(svc))
+			r.With(deadline("bids.delete")).Delete("/{bidId}", deletebid.New(svc))
+			r.With(deadline("bids.restore")).Post("/{bidId}/restore", restorebid.New(svc))
+			r.With(deadline("bids.deleted")).Get("/deleted", getdeletedbids.New(svc))
+
+		})
+
+		r.Route("/categories", func(r chi.Router) {
+			r.Use(limit("categories"), idempotent)
+
+			r.With(deadline("categories.list")).Get("/", getcategories.New(svc))
+			r.With(deadline("categories.new")).Post("/new", newcategory.New(svc))
+			r.With(deadline("categories.edit")).Patch("/{categoryId}/edit", patchcategory.New(svc))
+			r.With(deadline("categories.delete")).Delete("/{categoryId}", deletecategory.New(svc))
+
+		})
+
+		r.Route("/employees", func(r chi.Router) {
+			r.Use(limit("employees"), idempotent)
+
+			r.With(deadline("employees.organizations")).Get("/me/organizations", getmyorganizations.New(svc))
+			r.With(deadline("employees.notifications")).Get("/me/notifications", getnotificationsettings.New(svc))
+			r.With(deadline("employees.notifications")).Put("/me/notifications", putnotificationsettings.New(svc))
+
+		})
+
+		r.Route("/organizations", func(r chi.Router) {
+			r.Use(limit("organizations"), idempotent)
+
+			r.With(deadline("organizations.members")).Get("/{organizationId}/members", getmembers.New(svc))
+			r.With(deadline("organizations.reputation")).Get("/{organizationId}/reputation", getreputation.New(svc))
+			r.With(deadline("organizations.members")).Put("/{organizationId}/members/{userId}/role", putmemberrole.New(svc))
+
+		})
+
+		r.Route("/events", func(r chi.Router) {
+			r.Use(limit("events"))
+
+			// The stream stays open, so it gets no deadline.
+			r.Get("/stream", streamevents.New(svc))
 
 		})
 
@@ -85,11 +287,23 @@ func main() {
 	srv := &http.Server{
 		Addr:         ":8080",
 		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
+		ReadTimeout:  httpTimeout,
+		WriteTimeout: httpTimeout,
 		IdleTimeout:  time.Minute,
 	}
 
+	grpcSrv := rpc.New(svc, cfg.Timeouts)
+	go func() {
+		lis, err := net.Listen("tcp", cfg.GRPCAddress)
+		if err != nil {
+			panic(err.Error())
+		}
+		err = grpcSrv.Serve(lis)
+		if err != nil {
+			log.Error("grpc server failed", slog.String("error", err.Error()))
+		}
+	}()
+
 	serverCtx, serverStopCtx := context.WithCancel(context.Background())
 
 	sig := make(chan os.Signal, 1)
@@ -108,6 +322,12 @@ func main() {
 		}()
 
 		log.Info("server shut down")
+		stopWorkers()
+		go func() {
+			<-shutdownCtx.Done()
+			grpcSrv.Stop()
+		}()
+		grpcSrv.GracefulStop()
 		err := srv.Shutdown(shutdownCtx)
 		if err != nil {
 			panic(err.Error())
@@ -115,7 +335,7 @@ func main() {
 		serverStopCtx()
 	}()
 
-	err := srv.ListenAndServe()
+	err = srv.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		panic(err.Error())
 	}
@@ -126,6 +346,76 @@ func main() {
 
 }
 
+func purgeIdempotencyKeys(log *slog.Logger, storage *psq.Storage) {
+	for range time.Tick(time.Hour) {
+		n, err := storage.PurgeIdempotencyKeys(context.Background())
+		if err != nil {
+			log.Error("failed to purge idempotency keys", slog.String("error", err.Error()))
+			continue
+		}
+		log.Debug("purged idempotency keys", slog.Int64("count", n))
+	}
+}
+
+// listenEvents keeps the hub told about the events saved on any replica,
+// listening again after the connection fails.
+func listenEvents(ctx context.Context, log *slog.Logger, dsn string, hub *notify.Hub) {
+	for {
+		err := psq.ListenEvents(ctx, dsn, hub.Notify)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error("failed to listen for events", slog.String("error", err.Error()))
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func purgeEvents(log *slog.Logger, storage *psq.Storage, retention time.Duration) {
+	for range time.Tick(time.Hour) {
+		n, err := storage.PurgeEvents(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Error("failed to purge events", slog.String("error", err.Error()))
+			continue
+		}
+		log.Debug("purged events", slog.Int64("count", n))
+	}
+}
+
+// notifyClosingTenders reminds the bidders of the tenders closing within
+// window, each of them once.
+func notifyClosingTenders(log *slog.Logger, svc *service.Service, window time.Duration) {
+	for range time.Tick(15 * time.Minute) {
+		n, err := svc.NotifyClosingTenders(context.Background(), window)
+		if err != nil {
+			log.Error("failed to notify about closing tenders", slog.String("error", err.Error()))
+			continue
+		}
+		log.Debug("notified about closing tenders", slog.Int("count", n))
+	}
+}
+
+func purgeNotifications(log *slog.Logger, storage *psq.Storage, retention time.Duration) {
+	for range time.Tick(time.Hour) {
+		n, err := storage.PurgeNotifications(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Error("failed to purge notifications", slog.String("error", err.Error()))
+			continue
+		}
+		log.Debug("purged notifications", slog.Int64("count", n))
+	}
+}
+
+func purgeDeleted(log *slog.Logger, storage *psq.Storage, retention time.Duration) {
+	for range time.Tick(time.Hour) {
+		n, err := storage.PurgeDeleted(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Error("failed to purge deleted tenders and bids", slog.String("error", err.Error()))
+			continue
+		}
+		log.Debug("purged deleted tenders and bids", slog.Int64("count", n))
+	}
+}
+
 func setuplogger() *slog.Logger {
 	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	return log
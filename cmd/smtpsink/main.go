@@ -0,0 +1,34 @@
+// Command smtpsink runs a local SMTP server that prints the emails sent to
+// it instead of delivering them, so the notifications can be checked
+// without a real mail server: SMTP_HOST=localhost SMTP_PORT=2525.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"tender_service/internal/mail"
+)
+
+func main() {
+	addr := flag.String("addr", ":2525", "address to listen on")
+	flag.Parse()
+
+	sink, err := mail.NewSink(*addr, func(msg mail.Received) {
+		fmt.Printf("--- from %s to %s\n%s\n", msg.From, strings.Join(msg.To, ", "), msg.Data)
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start the sink:", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "smtp sink listening on", sink.Addr())
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+
+	sink.Close()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"tender_service/internal/config"
+	importtenders "tender_service/internal/handlers/tenders/import_tenders"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	psq "tender_service/internal/storage"
+)
+
+// importTenders imports a CSV or NDJSON file the same way
+// POST /api/tenders/import does and prints the result.
+func importTenders(ctx context.Context, storage *psq.Storage, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "csv or ndjson, by default taken from the file extension")
+	dryRun := fs.Bool("dry-run", false, "check the rows and roll the import back")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("import: expected one file")
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if *format == "jsonl" {
+			*format = string(importtenders.NDJSON)
+		}
+	}
+	if *format != string(importtenders.CSV) && *format != string(importtenders.NDJSON) {
+		return errors.New("import: unknown format, use -format csv or -format ndjson")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := importtenders.Parse(f, importtenders.Format(*format))
+	if err != nil {
+		return err
+	}
+
+	// the import checks the creators' rights but needs none of the
+	// settings for admins and feedback
+	svc := service.New(storage, nil, &config.Config{})
+
+	result, err := svc.ImportTenders(ctx, importtenders.Input(importtenders.Request{Rows: rows, DryRun: *dryRun}))
+	if err != nil {
+		return err
+	}
+
+	res := importtenders.ResponseOf(response.DefaultLanguage, result)
+	err = printJSON(res)
+	if err != nil {
+		return err
+	}
+	if len(res.Errors) > 0 {
+		return errFailed
+	}
+	return nil
+}
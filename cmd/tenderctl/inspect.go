@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"tender_service/internal/lib/time_converter"
+	psq "tender_service/internal/storage"
+	"tender_service/internal/storage/models"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type versionView struct {
+	Version     uint   `json:"version"`
+	CreatedAt   string `json:"createdAt"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+}
+
+type auditView struct {
+	CreatedAt string `json:"createdAt"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Actor     string `json:"actor"`
+	Reason    string `json:"reason"`
+}
+
+type tenderView struct {
+	ID                 uuid.UUID     `json:"id"`
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	ServiceType        string        `json:"serviceType"`
+	Status             string        `json:"status"`
+	Visibility         string        `json:"visibility"`
+	SealedBids         bool          `json:"sealedBids"`
+	SubmissionDeadline string        `json:"submissionDeadline,omitempty"`
+	OrganizationID     uuid.UUID     `json:"organizationId"`
+	Creator            string        `json:"creatorUsername"`
+	Version            uint          `json:"version"`
+	CreatedAt          string        `json:"createdAt"`
+	DeletedAt          string        `json:"deletedAt,omitempty"`
+	Versions           []versionView `json:"versions"`
+	Audit              []auditView   `json:"audit"`
+}
+
+type bidView struct {
+	ID             uuid.UUID     `json:"id"`
+	TenderID       uuid.UUID     `json:"tenderId"`
+	Name           string        `json:"name"`
+	Description    string        `json:"description"`
+	Status         string        `json:"status"`
+	AuthorType     string        `json:"authorType"`
+	Author         string        `json:"authorUsername"`
+	OrganizationID uuid.UUID     `json:"organizationId"`
+	Version        uint32        `json:"version"`
+	CreatedAt      string        `json:"createdAt"`
+	SubmittedAt    string        `json:"submittedAt,omitempty"`
+	DeletedAt      string        `json:"deletedAt,omitempty"`
+	Versions       []versionView `json:"versions"`
+	Audit          []auditView   `json:"audit"`
+}
+
+func auditViews(audit []models.StatusAudit) []auditView {
+	views := make([]auditView, 0, len(audit))
+	for _, el := range audit {
+		views = append(views, auditView{
+			CreatedAt: time_converter.Time(el.CreatedAt),
+			From:      el.FromStatus,
+			To:        el.ToStatus,
+			Actor:     el.Actor,
+			Reason:    el.Reason,
+		})
+	}
+	return views
+}
+
+func deletedAt(at gorm.DeletedAt) string {
+	if !at.Valid {
+		return ""
+	}
+	return time_converter.Time(at.Time)
+}
+
+func listFlags(fs *flag.FlagSet, filter *psq.ListFilter) (org *string) {
+	org = fs.String("org", "", "organization id")
+	fs.StringVar(&filter.Username, "user", "", "creator username")
+	fs.StringVar(&filter.Status, "status", "", "status")
+	fs.BoolVar(&filter.Deleted, "deleted", false, "include deleted")
+	fs.IntVar(&filter.Limit, "limit", 50, "maximum number of rows, 0 for all")
+	return org
+}
+
+func parseID(name string, value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
+
+// listTenders prints the tenders, newest first.
+func listTenders(ctx context.Context, storage *psq.Storage, args []string) error {
+	var filter psq.ListFilter
+	fs := flag.NewFlagSet("tenders", flag.ContinueOnError)
+	org := listFlags(fs, &filter)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	filter.OrganizationID, err = parseID("-org", *org)
+	if err != nil {
+		return err
+	}
+
+	tenders, err := storage.ListTenders(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tVERSION\tORGANIZATION\tCREATOR\tCREATED\tNAME")
+	for _, el := range tenders {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", el.ID, el.Status, el.Version, el.OrganizationID,
+			el.EmployeeUsername, time_converter.Time(el.CreatedAt), el.Name)
+	}
+	return tw.Flush()
+}
+
+// listBids prints the bids, newest first.
+func listBids(ctx context.Context, storage *psq.Storage, args []string) error {
+	var filter psq.ListFilter
+	fs := flag.NewFlagSet("bids", flag.ContinueOnError)
+	org := listFlags(fs, &filter)
+	tender := fs.String("tender", "", "tender id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	filter.OrganizationID, err = parseID("-org", *org)
+	if err != nil {
+		return err
+	}
+	filter.TenderID, err = parseID("-tender", *tender)
+	if err != nil {
+		return err
+	}
+
+	bids, err := storage.ListBids(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTENDER\tSTATUS\tVERSION\tORGANIZATION\tAUTHOR\tCREATED\tNAME")
+	for _, el := range bids {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", el.ID, el.TenderID, el.Status, el.Version, el.OrganizationID,
+			el.EmployeeUsername, time_converter.Time(el.CreatedAt), el.Name)
+	}
+	return tw.Flush()
+}
+
+// inspectTender prints a tender with its versions and forced status
+// changes.
+func inspectTender(ctx context.Context, storage *psq.Storage, args []string) error {
+	if len(args) != 1 {
+		return errors.New("tender: expected one tender id")
+	}
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.New("tender: invalid tender id")
+	}
+
+	details, err := storage.InspectTender(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	el := details.Tender
+	view := tenderView{
+		ID:                 el.ID,
+		Name:               el.Name,
+		Description:        el.Description,
+		ServiceType:        string(el.ServiceType),
+		Status:             string(el.Status),
+		Visibility:         string(el.Visibility),
+		SealedBids:         el.SealedBids,
+		SubmissionDeadline: time_converter.OptionalTime(el.SubmissionDeadline),
+		OrganizationID:     el.OrganizationID,
+		Creator:            el.EmployeeUsername,
+		Version:            el.Version,
+		CreatedAt:          time_converter.Time(el.CreatedAt),
+		DeletedAt:          deletedAt(el.DeletedAt),
+		Versions:           make([]versionView, 0, len(details.Versions)),
+		Audit:              auditViews(details.Audit),
+	}
+	for _, version := range details.Versions {
+		view.Versions = append(view.Versions, versionView{
+			Version:     version.Version,
+			CreatedAt:   time_converter.Time(version.CreatedAt),
+			Name:        version.Name,
+			Description: version.Description,
+			Status:      string(version.Status),
+		})
+	}
+	return printJSON(view)
+}
+
+// inspectBid prints a bid with its versions and forced status changes.
+func inspectBid(ctx context.Context, storage *psq.Storage, args []string) error {
+	if len(args) != 1 {
+		return errors.New("bid: expected one bid id")
+	}
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.New("bid: invalid bid id")
+	}
+
+	details, err := storage.InspectBid(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	el := details.Bid
+	view := bidView{
+		ID:             el.ID,
+		TenderID:       el.TenderID,
+		Name:           el.Name,
+		Description:    el.Description,
+		Status:         string(el.Status),
+		AuthorType:     string(el.AuthorType),
+		Author:         el.EmployeeUsername,
+		OrganizationID: el.OrganizationID,
+		Version:        el.Version,
+		CreatedAt:      time_converter.Time(el.CreatedAt),
+		SubmittedAt:    time_converter.OptionalTime(el.SubmittedAt),
+		DeletedAt:      deletedAt(el.DeletedAt),
+		Versions:       make([]versionView, 0, len(details.Versions)),
+		Audit:          auditViews(details.Audit),
+	}
+	for _, version := range details.Versions {
+		view.Versions = append(view.Versions, versionView{
+			Version:     uint(version.Version),
+			CreatedAt:   time_converter.Time(version.CreatedAt),
+			Name:        version.Name,
+			Description: version.Description,
+			Status:      string(version.Status),
+		})
+	}
+	return printJSON(view)
+}
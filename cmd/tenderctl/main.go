@@ -0,0 +1,92 @@
+// Command tenderctl operates the tender service against the configured
+// database, the same one the server works with.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"tender_service/internal/config"
+	psq "tender_service/internal/storage"
+
+	"gorm.io/gorm/logger"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, storage *psq.Storage, args []string) error
+}
+
+var commands = map[string]command{
+	"import":       {"import [-format csv|ndjson] [-dry-run] FILE — import tenders", importTenders},
+	"seed":         {"seed FILE — create or update employees and organizations from a JSON file", seed},
+	"assign":       {"assign -org ID -user USERNAME [-role ROLE] — make an employee a responsible", assign},
+	"tenders":      {"tenders [-org ID] [-user USERNAME] [-status STATUS] [-deleted] [-limit N] — list tenders", listTenders},
+	"tender":       {"tender ID — show a tender with its versions", inspectTender},
+	"bids":         {"bids [-tender ID] [-org ID] [-user USERNAME] [-status STATUS] [-deleted] [-limit N] — list bids", listBids},
+	"bid":          {"bid ID — show a bid with its versions", inspectBid},
+	"force-status": {"force-status (-tender ID | -bid ID) -status STATUS -reason TEXT [-actor NAME] — force a status", forceStatus},
+	"purge":        {"purge -prefix PREFIX [-yes] — remove test organizations, employees and their data", purgeTestData},
+}
+
+// errFailed is returned by commands that have reported their failure on
+// their own.
+var errFailed = errors.New("failed")
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	storage := &psq.Storage{}
+	err := psq.New(func() {}, storage, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to init storage:", err)
+		os.Exit(1)
+	}
+	storage.SetLogLevel(logger.Silent)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err = cmd.run(ctx, storage, os.Args[2:])
+	if err != nil {
+		if !errors.Is(err, errFailed) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		stop()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: tenderctl COMMAND [ARGS]")
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  tenderctl", commands[name].usage)
+	}
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
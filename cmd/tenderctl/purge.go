@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	psq "tender_service/internal/storage"
+	"text/tabwriter"
+)
+
+// purgeTestData removes the organizations and employees named with a
+// prefix and everything they created. Without -yes it only counts what
+// would be removed.
+func purgeTestData(ctx context.Context, storage *psq.Storage, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	prefix := fs.String("prefix", "", "name prefix of the test organizations and employees")
+	yes := fs.Bool("yes", false, "remove the data instead of counting it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *prefix == "" {
+		return errors.New("purge: -prefix must be set")
+	}
+
+	purged, err := storage.PurgeTestData(ctx, *prefix, !*yes)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tROWS")
+	for _, table := range []string{"organization", "employee", "organization_responsible", "tenders", "tender_versions",
+		"bids", "bid_versions", "bid_feedbacks", "reputation_stats", "status_audit"} {
+		fmt.Fprintf(tw, "%s\t%d\n", table, purged[table])
+	}
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+
+	if !*yes {
+		fmt.Println("dry run, nothing removed; run again with -yes to remove")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	psq "tender_service/internal/storage"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+)
+
+type seedFile struct {
+	Employees []struct {
+		Username  string `json:"username"`
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	} `json:"employees"`
+	Organizations []struct {
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		Type         string `json:"type"`
+		Responsibles []struct {
+			Username string `json:"username"`
+			Role     string `json:"role"`
+		} `json:"responsibles"`
+	} `json:"organizations"`
+}
+
+// seed creates or updates the employees and organizations of a JSON file
+// and assigns the responsibles, all in one transaction.
+func seed(ctx context.Context, storage *psq.Storage, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("seed: expected one file")
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var file seedFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	return storage.Transaction(ctx, func(tx *psq.Storage) error {
+		for _, el := range file.Employees {
+			employee := models.Employee{Username: el.Username, FirstName: el.FirstName, LastName: el.LastName}
+			if err := tx.SeedEmployee(ctx, &employee); err != nil {
+				return fmt.Errorf("employee %s: %w", el.Username, err)
+			}
+			fmt.Printf("employee\t%s\t%s\n", employee.ID, employee.Username)
+		}
+
+		for _, el := range file.Organizations {
+			orgType := models.OrganizationType(el.Type)
+			if orgType == "" {
+				orgType = models.LLCOrganization
+			}
+			org := models.Organization{Name: el.Name, Description: el.Description, Type: orgType}
+			if err := tx.SeedOrganization(ctx, &org); err != nil {
+				return fmt.Errorf("organization %s: %w", el.Name, err)
+			}
+			fmt.Printf("organization\t%s\t%s\n", org.ID, org.Name)
+
+			for _, member := range el.Responsibles {
+				role := models.OrganizationRole(member.Role)
+				if role == "" {
+					role = models.AdminRole
+				}
+				if err := tx.AssignResponsible(ctx, org.ID, member.Username, role); err != nil {
+					return fmt.Errorf("organization %s, responsible %s: %w", el.Name, member.Username, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// assign makes an employee a responsible of an organization.
+func assign(ctx context.Context, storage *psq.Storage, args []string) error {
+	fs := flag.NewFlagSet("assign", flag.ContinueOnError)
+	org := fs.String("org", "", "organization id")
+	user := fs.String("user", "", "employee username")
+	role := fs.String("role", string(models.AdminRole), "viewer, editor, approver or admin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	orgID, err := uuid.Parse(*org)
+	if err != nil {
+		return errors.New("assign: invalid -org")
+	}
+	if *user == "" {
+		return errors.New("assign: -user must be set")
+	}
+
+	return storage.AssignResponsible(ctx, orgID, *user, models.OrganizationRole(*role))
+}
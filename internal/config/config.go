@@ -1,13 +1,89 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Address string
-	DB      DB
+	Address  string
+	DB       DB
+	Timeouts Timeouts
+
+	// GRPCAddress is where the gRPC API listens, next to the REST one
+	GRPCAddress string
+
+	IdempotencyTTL time.Duration
+
+	// DeletedRetention is how long soft-deleted tenders and bids are kept
+	DeletedRetention time.Duration
+
+	// FeedbackEditWindow is how long a feedback message may be edited
+	FeedbackEditWindow time.Duration
+
+	// EventRetention is how long events are kept for the streams to resume
+	// from
+	EventRetention time.Duration
+
+	RateLimits map[string]RateLimit
+
+	Cache Cache
+
+	Mail Mail
+
+	Admins []string
+}
+
+// Mail is how the email notifications are sent. Without Host they are only
+// queued, to be sent once an SMTP server is configured.
+type Mail struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+
+	// Locale is the language of the emails to the users who have not
+	// picked one
+	Locale string
+	// MaxAttempts is how many times an email is tried before giving up
+	MaxAttempts int
+	// ClosingWindow is how long before the submission deadline the bidders
+	// are reminded of it
+	ClosingWindow time.Duration
+	// Retention is how long sent and failed emails are kept
+	Retention time.Duration
+}
+
+// Addr is the address of the SMTP server.
+func (m Mail) Addr() string {
+	return m.Host + ":" + m.Port
+}
+
+// Cache is how many user, organization and public tender list lookups are
+// kept in memory and for how long. A zero Size turns caching off.
+type Cache struct {
+	Size int
+	TTL  time.Duration
+	// LookupTTL bounds how long the users, organizations and memberships
+	// changed by tenderctl stay stale
+	LookupTTL time.Duration
+}
+
+// RateLimit is a token bucket of Burst requests refilled at Rate per second,
+// counted per Key: "ip", "user" or "organization".
+type RateLimit struct {
+	Rate  float64
+	Burst int
+	Key   string
+	// IPRate and IPBurst make a bucket per client IP checked before that of
+	// the user or organization, a zero IPBurst turns it off
+	IPRate  float64
+	IPBurst int
 }
 
 type DB struct {
@@ -20,6 +96,53 @@ type DB struct {
 	JDBC     string
 }
 
+// DSN is the connection string of the database.
+func (db DB) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s", db.Host, db.User, db.Password, db.Name, db.Port)
+}
+
+type Timeouts struct {
+	Default time.Duration
+	Routes  map[string]time.Duration
+}
+
+const (
+	queryTimeoutDefault       = 10 * time.Second
+	idempotencyTTLDefault     = 24 * time.Hour
+	deletedRetentionDefault   = 30 * 24 * time.Hour
+	feedbackEditWindowDefault = 15 * time.Minute
+	eventRetentionDefault     = 7 * 24 * time.Hour
+
+	cacheSizeDefault      = 10000
+	cacheTTLDefault       = 30 * time.Second
+	cacheLookupTTLDefault = 5 * time.Second
+
+	smtpPortDefault          = "25"
+	mailFromDefault          = "tenders@localhost"
+	mailLocaleDefault        = "ru"
+	mailMaxAttemptsDefault   = 5
+	mailClosingWindowDefault = 24 * time.Hour
+	mailRetentionDefault     = 7 * 24 * time.Hour
+
+	grpcAddressDefault = ":9090"
+)
+
+// For returns the query deadline for a route name like "tenders.list",
+// falling back to its group ("tenders") and then to the default one.
+func (t Timeouts) For(route string) time.Duration {
+	for name := route; name != ""; {
+		if d, ok := t.Routes[name]; ok {
+			return d
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return t.Default
+}
+
 func Load() *Config {
 	var cfg Config
 	readEnv(&cfg)
@@ -48,4 +171,154 @@ func readEnv(cfg *Config) {
 	if !exists {
 		slog.Error(`can't find "POSTGRES_DATABASE" env`)
 	}
+
+	readTimeouts(cfg)
+
+	cfg.GRPCAddress, exists = os.LookupEnv("GRPC_ADDRESS")
+	if !exists || cfg.GRPCAddress == "" {
+		cfg.GRPCAddress = grpcAddressDefault
+	}
+
+	cfg.IdempotencyTTL = readDuration("IDEMPOTENCY_TTL", idempotencyTTLDefault)
+
+	cfg.DeletedRetention = readDuration("DELETED_RETENTION", deletedRetentionDefault)
+
+	cfg.FeedbackEditWindow = readDuration("FEEDBACK_EDIT_WINDOW", feedbackEditWindowDefault)
+
+	cfg.EventRetention = readDuration("EVENT_RETENTION", eventRetentionDefault)
+
+	readRateLimits(cfg)
+
+	cfg.Cache.Size = readInt("CACHE_SIZE", cacheSizeDefault)
+	cfg.Cache.TTL = readDuration("CACHE_TTL", cacheTTLDefault)
+	cfg.Cache.LookupTTL = readDuration("CACHE_LOOKUP_TTL", cacheLookupTTLDefault)
+
+	readMail(cfg)
+
+	if value, exists := os.LookupEnv("ADMIN_USERNAMES"); exists && value != "" {
+		for _, el := range strings.Split(value, ",") {
+			cfg.Admins = append(cfg.Admins, strings.TrimSpace(el))
+		}
+	}
+}
+
+func readRateLimits(cfg *Config) {
+	cfg.RateLimits = map[string]RateLimit{
+		"tenders": {Rate: 20, Burst: 40, Key: "user", IPRate: 50, IPBurst: 100},
+		"bids":    {Rate: 20, Burst: 40, Key: "user", IPRate: 50, IPBurst: 100},
+	}
+
+	// RATE_LIMITS="tenders=10:20:organization:50:100,bids=5:10:user"
+	value, exists := os.LookupEnv("RATE_LIMITS")
+	if !exists || value == "" {
+		return
+	}
+	for _, el := range strings.Split(value, ",") {
+		group, spec, found := strings.Cut(strings.TrimSpace(el), "=")
+		parts := strings.Split(spec, ":")
+		if !found || len(parts) < 2 || len(parts) == 4 || len(parts) > 5 {
+			slog.Error(`can't parse "RATE_LIMITS" env`, slog.String("value", el))
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			slog.Error(`can't parse "RATE_LIMITS" env`, slog.String("error", err.Error()))
+			continue
+		}
+		burst, err := strconv.Atoi(parts[1])
+		if err != nil {
+			slog.Error(`can't parse "RATE_LIMITS" env`, slog.String("error", err.Error()))
+			continue
+		}
+
+		limit := RateLimit{Rate: rate, Burst: burst, Key: "ip"}
+		if len(parts) >= 3 {
+			limit.Key = parts[2]
+		}
+		if len(parts) == 5 {
+			limit.IPRate, err = strconv.ParseFloat(parts[3], 64)
+			if err != nil {
+				slog.Error(`can't parse "RATE_LIMITS" env`, slog.String("error", err.Error()))
+				continue
+			}
+			limit.IPBurst, err = strconv.Atoi(parts[4])
+			if err != nil {
+				slog.Error(`can't parse "RATE_LIMITS" env`, slog.String("error", err.Error()))
+				continue
+			}
+		}
+		cfg.RateLimits[group] = limit
+	}
+}
+
+func readMail(cfg *Config) {
+	cfg.Mail.Host = os.Getenv("SMTP_HOST")
+	cfg.Mail.Port = readString("SMTP_PORT", smtpPortDefault)
+	cfg.Mail.Username = os.Getenv("SMTP_USERNAME")
+	cfg.Mail.Password = os.Getenv("SMTP_PASSWORD")
+	cfg.Mail.From = readString("SMTP_FROM", mailFromDefault)
+
+	cfg.Mail.Locale = readString("NOTIFICATION_LOCALE", mailLocaleDefault)
+	cfg.Mail.MaxAttempts = readInt("NOTIFICATION_MAX_ATTEMPTS", mailMaxAttemptsDefault)
+	cfg.Mail.ClosingWindow = readDuration("TENDER_CLOSING_WINDOW", mailClosingWindowDefault)
+	cfg.Mail.Retention = readDuration("NOTIFICATION_RETENTION", mailRetentionDefault)
+}
+
+func readString(env string, def string) string {
+	value, exists := os.LookupEnv(env)
+	if !exists || value == "" {
+		return def
+	}
+	return value
+}
+
+func readInt(env string, def int) int {
+	value, exists := os.LookupEnv(env)
+	if !exists {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Error(fmt.Sprintf(`can't parse "%s" env`, env), slog.String("error", err.Error()))
+		return def
+	}
+	return n
+}
+
+func readDuration(env string, def time.Duration) time.Duration {
+	value, exists := os.LookupEnv(env)
+	if !exists {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Error(fmt.Sprintf(`can't parse "%s" env`, env), slog.String("error", err.Error()))
+		return def
+	}
+	return d
+}
+
+func readTimeouts(cfg *Config) {
+	cfg.Timeouts.Default = readDuration("QUERY_TIMEOUT", queryTimeoutDefault)
+	cfg.Timeouts.Routes = make(map[string]time.Duration)
+
+	// QUERY_TIMEOUTS="tenders=5s,bids.submit_decision=2s"
+	value, exists := os.LookupEnv("QUERY_TIMEOUTS")
+	if !exists || value == "" {
+		return
+	}
+	for _, el := range strings.Split(value, ",") {
+		route, timeout, found := strings.Cut(strings.TrimSpace(el), "=")
+		if !found {
+			slog.Error(`can't parse "QUERY_TIMEOUTS" env`, slog.String("value", el))
+			continue
+		}
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			slog.Error(`can't parse "QUERY_TIMEOUTS" env`, slog.String("error", err.Error()))
+			continue
+		}
+		cfg.Timeouts.Routes[route] = d
+	}
 }
@@ -1,20 +1,26 @@
 package bidfeedback
 
 import (
-	"errors"
+	"context"
 	"net/http"
+	"strconv"
+	"tender_service/internal/lib/actingorg"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	BidID       uuid.UUID `validate:"required,uuid"`
+	BidID       uuid.UUID
 	UserName    string
-	BidFeedback string `validate:"required,max=1000"`
+	BidFeedback string
+	Rating      *int
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
 }
 
 type Response struct {
@@ -29,18 +35,20 @@ type Response struct {
 }
 
 type BidFeedbackMaker interface {
-	BidFeedback(req Request) (Response, error)
+	LeaveFeedback(ctx context.Context, actor service.Actor, bidID uuid.UUID, feedback service.Feedback) (service.Bid, error)
 }
 
-func validateBadrequest(req *Request) string {
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errMsgs
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		AuthorType:  string(el.AuthorType),
+		Status:      string(el.Status),
+		AuthorID:    el.AuthorID,
 	}
-
-	return ""
 }
 
 func New(ts BidFeedbackMaker) http.HandlerFunc {
@@ -51,8 +59,7 @@ func New(ts BidFeedbackMaker) http.HandlerFunc {
 		bidID, err := uuid.Parse(bidStr)
 
 		if err != nil || bidStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("bidId"))
 			return
 		}
 		req.BidID = bidID
@@ -60,41 +67,31 @@ func New(ts BidFeedbackMaker) http.HandlerFunc {
 		req.UserName = r.URL.Query().Get("username")
 		req.BidFeedback = r.URL.Query().Get("bidFeedback")
 
-		if errMsg := validateBadrequest(&req); errMsg != "" {
-			w.WriteHeader(http.StatusBadRequest)
+		if r.URL.Query().Has("rating") {
+			rating, err := strconv.Atoi(r.URL.Query().Get("rating"))
+			if err != nil {
+				response.Render(w, r, response.Invalid("invalid rating"))
+				return
+			}
+			req.Rating = &rating
+		}
 
-			render.JSON(w, r, response.Error(errMsg))
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
 			return
 		}
+		req.OrganizationID = orgID
 
-		res, err := ts.BidFeedback(req)
+		bid, err := ts.LeaveFeedback(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID, service.Feedback{Text: req.BidFeedback, Rating: req.Rating})
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrBidNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(bid))
 
 	}
 }
@@ -1,21 +1,25 @@
 package bidsrollback
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
+	"tender_service/internal/lib/actingorg"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
 	BidID    uuid.UUID `json:"id"`
-	UserName string    `validate:"required"`
-	Version  uint      `validate:"required"`
+	UserName string
+	Version  uint
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
 }
 
 type Response struct {
@@ -30,27 +34,33 @@ type Response struct {
 }
 
 type BidRollbacker interface {
-	BidRollback(req Request) (Response, error)
+	RollbackBid(ctx context.Context, actor service.Actor, bidID uuid.UUID, version uint) (service.Bid, error)
+}
+
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		AuthorType:  string(el.AuthorType),
+		Status:      string(el.Status),
+		AuthorID:    el.AuthorID,
+	}
 }
 
 func validateBadrequest(req *Request, r *http.Request) error {
 	version := chi.URLParam(r, "version")
 	if value, err := strconv.Atoi(version); err != nil {
-		return err
+		return response.Invalid("invalid version")
 	} else {
 		if value < 0 {
-			return errors.New("version must be not negative")
+			return response.Invalid("version must be not negative")
 		}
 		req.Version = uint(value)
 	}
 
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errors.New(errMsgs)
-	}
-
 	return nil
 }
 
@@ -62,49 +72,34 @@ func New(ts BidRollbacker) http.HandlerFunc {
 		bidID, err := uuid.Parse(bidStr)
 
 		if err != nil || bidStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("bidId"))
 			return
 		}
 		req.BidID = bidID
 
 		req.UserName = r.URL.Query().Get("username")
 
-		if errMsg := validateBadrequest(&req, r); errMsg != nil {
-			w.WriteHeader(http.StatusBadRequest)
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
 
-			render.JSON(w, r, response.Error(errMsg.Error()))
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
 			return
 		}
+		req.OrganizationID = orgID
 
-		res, err := ts.BidRollback(req)
+		bid, err := ts.RollbackBid(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID, req.Version)
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrBidNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(bid))
 
 	}
 }
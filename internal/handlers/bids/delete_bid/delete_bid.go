@@ -0,0 +1,65 @@
+package deletebid
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	BidID    uuid.UUID
+	UserName string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
+}
+
+type Response struct {
+	ID        uuid.UUID `json:"id"`
+	DeletedAt string    `json:"deletedAt"`
+}
+
+type BidDeleter interface {
+	DeleteBid(ctx context.Context, actor service.Actor, bidID uuid.UUID) (service.Deletion, error)
+}
+
+func New(ts BidDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		bidStr := chi.URLParam(r, "bidId")
+		bidID, err := uuid.Parse(bidStr)
+
+		if err != nil || bidStr == "" {
+			response.Render(w, r, response.InvalidID("bidId"))
+			return
+		}
+		req.BidID = bidID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		deletion, err := ts.DeleteBid(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, Response{ID: deletion.ID, DeletedAt: time_converter.Time(deletion.DeletedAt)})
+
+	}
+}
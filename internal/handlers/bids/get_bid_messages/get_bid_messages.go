@@ -0,0 +1,104 @@
+package getbidmessages
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	BidID    uuid.UUID
+	UserName string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
+}
+
+type Response struct {
+	ID               uuid.UUID  `json:"id"`
+	BidID            uuid.UUID  `json:"bidId"`
+	ParentID         *uuid.UUID `json:"parentId,omitempty"`
+	Message          string     `json:"message"`
+	Rating           *int       `json:"rating,omitempty"`
+	AuthorUsername   string     `json:"authorUsername"`
+	AuthorName       string     `json:"authorName"`
+	OrganizationID   uuid.UUID  `json:"organizationId"`
+	OrganizationName string     `json:"organizationName"`
+	CreatedAt        string     `json:"createdAt"`
+	EditedAt         string     `json:"editedAt,omitempty"`
+	Replies          []Response `json:"replies"`
+}
+
+type ResponseList struct {
+	Response []Response
+}
+
+type BidMessagesGetter interface {
+	BidMessages(ctx context.Context, actor service.Actor, bidID uuid.UUID) ([]service.Message, error)
+}
+
+func responseOf(el service.Message) Response {
+	res := Response{
+		ID:               el.ID,
+		BidID:            el.BidID,
+		ParentID:         el.ParentID,
+		Message:          el.Text,
+		Rating:           el.Rating,
+		AuthorUsername:   el.AuthorUsername,
+		AuthorName:       el.AuthorName,
+		OrganizationID:   el.OrganizationID,
+		OrganizationName: el.OrganizationName,
+		CreatedAt:        time_converter.Time(el.CreatedAt),
+		EditedAt:         time_converter.OptionalTime(el.EditedAt),
+		Replies:          make([]Response, 0, len(el.Replies)),
+	}
+	for _, reply := range el.Replies {
+		res.Replies = append(res.Replies, responseOf(reply))
+	}
+	return res
+}
+
+func New(ts BidMessagesGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		bidStr := chi.URLParam(r, "bidId")
+		bidID, err := uuid.Parse(bidStr)
+
+		if err != nil || bidStr == "" {
+			response.Render(w, r, response.InvalidID("bidId"))
+			return
+		}
+		req.BidID = bidID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		threads, err := ts.BidMessages(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(threads))
+		for _, el := range threads {
+			res = append(res, responseOf(el))
+		}
+		render.JSON(w, r, res)
+
+	}
+}
@@ -1,9 +1,12 @@
 package getbidstatus
 
 import (
-	"errors"
+	"context"
 	"net/http"
+	"tender_service/internal/lib/actingorg"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -11,8 +14,10 @@ import (
 )
 
 type Request struct {
-	BidID    uuid.UUID `validate:"required,uuid"`
+	BidID    uuid.UUID
 	UserName string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
 }
 
 type Response struct {
@@ -20,7 +25,7 @@ type Response struct {
 }
 
 type BidStatusGetter interface {
-	BidStatus(req Request) (Response, error)
+	BidStatus(ctx context.Context, actor service.Actor, bidID uuid.UUID) (models.BidStatus, error)
 }
 
 func New(ts BidStatusGetter) http.HandlerFunc {
@@ -31,41 +36,28 @@ func New(ts BidStatusGetter) http.HandlerFunc {
 		bidID, err := uuid.Parse(bidStr)
 
 		if err != nil || bidStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("bidId"))
 			return
 		}
 		req.BidID = bidID
 
 		req.UserName = r.URL.Query().Get("username")
-		res, err := ts.BidStatus(req)
-
+		orgID, err := actingorg.FromRequest(r)
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrBidNotExists) {
-				w.WriteHeader(http.StatusBadRequest)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
 
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
+		status, err := ts.BidStatus(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID)
 
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+		if err != nil {
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, Response{Status: string(status)})
 
 	}
 }
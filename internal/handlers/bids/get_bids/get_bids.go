@@ -1,10 +1,14 @@
 package getbids
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/export"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -12,21 +16,29 @@ import (
 )
 
 type Request struct {
-	Limit    uint      `validate:"gte=0"`
-	OffSet   uint      `validate:"gte=0"`
-	TenderID uuid.UUID `validate:"uuid,required"`
-	Username string    `validate:"required,max=100"`
+	Limit    uint
+	OffSet   uint
+	TenderID uuid.UUID
+	Username string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
 }
 
 type Response struct {
-	ID          uuid.UUID `json:"id"`
-	Version     uint      `json:"version"`
-	CreatedAt   string    `json:"createdAt"`
-	Name        string    `json:"name" validate:"max=100"`
-	AuthorType  string    `json:"authorType"`
-	AuthorID    uuid.UUID `json:"authorId"`
-	Description string    `json:"description" validate:"max=500"`
-	Status      string    `json:"status"`
+	ID          uuid.UUID  `json:"id"`
+	Version     uint       `json:"version"`
+	CreatedAt   string     `json:"createdAt"`
+	Name        string     `json:"name" validate:"max=100"`
+	AuthorType  string     `json:"authorType"`
+	AuthorID    uuid.UUID  `json:"authorId"`
+	Description string     `json:"description" validate:"max=500"`
+	Status      string     `json:"status"`
+	LotID       *uuid.UUID `json:"lotId,omitempty"`
+	// Sealed is set when the name and description are hidden until the
+	// tender's submission deadline.
+	Sealed bool `json:"sealed,omitempty"`
+	// WithdrawalReason is why the author withdrew the bid
+	WithdrawalReason string `json:"withdrawalReason,omitempty"`
 }
 
 type ResponseList struct {
@@ -34,7 +46,48 @@ type ResponseList struct {
 }
 
 type BidsGetter interface {
-	GetBids(req Request) (ResponseList, error)
+	Bids(ctx context.Context, actor service.Actor, tenderID uuid.UUID, page service.Page, fn func(el service.Bid) error) error
+}
+
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:               el.ID,
+		Version:          el.Version,
+		CreatedAt:        time_converter.Time(el.CreatedAt),
+		Name:             el.Name,
+		AuthorType:       string(el.AuthorType),
+		AuthorID:         el.AuthorID,
+		Description:      el.Description,
+		Status:           string(el.Status),
+		LotID:            el.LotID,
+		Sealed:           el.Sealed,
+		WithdrawalReason: el.WithdrawalReason,
+	}
+}
+
+var columns = []string{"id", "version", "createdAt", "name", "authorType", "authorId", "description", "status", "lotId", "sealed", "withdrawalReason"}
+
+func row(el Response) []string {
+	return []string{
+		el.ID.String(),
+		strconv.FormatUint(uint64(el.Version), 10),
+		el.CreatedAt,
+		el.Name,
+		el.AuthorType,
+		el.AuthorID.String(),
+		el.Description,
+		el.Status,
+		lotColumn(el.LotID),
+		strconv.FormatBool(el.Sealed),
+		el.WithdrawalReason,
+	}
+}
+
+func lotColumn(lotID *uuid.UUID) string {
+	if lotID == nil {
+		return ""
+	}
+	return lotID.String()
 }
 
 const (
@@ -47,10 +100,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		limit := r.URL.Query().Get("limit")
 		if value, err := strconv.Atoi(limit); err != nil {
-			return err
+			return response.Invalid("invalid limit")
 		} else {
 			if value < 0 {
-				return errors.New("limit must be not negative")
+				return response.Invalid("limit must be not negative")
 			}
 			req.Limit = uint(value)
 		}
@@ -62,10 +115,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		offset := r.URL.Query().Get("offset")
 		if value, err := strconv.Atoi(offset); err != nil {
-			return err
+			return response.Invalid("invalid offset")
 		} else {
 			if value < 0 {
-				return errors.New("offset must be not negative")
+				return response.Invalid("offset must be not negative")
 			}
 			req.OffSet = uint(value)
 		}
@@ -76,7 +129,7 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	req.Username = r.URL.Query().Get("username")
 
 	if req.Username == "" {
-		return errors.New("username must be not empty")
+		return response.Invalid("username must be not empty")
 	}
 
 	return nil
@@ -89,8 +142,7 @@ func New(ts BidsGetter) http.HandlerFunc {
 
 		err := validateBadrequest(&req, r)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid values"))
+			response.Render(w, r, err)
 			return
 		}
 
@@ -98,35 +150,55 @@ func New(ts BidsGetter) http.HandlerFunc {
 		tenderID, err := uuid.Parse(tenderStr)
 
 		if err != nil || tenderStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid  tender id"))
+			response.Render(w, r, response.InvalidID("tenderId"))
 			return
 		}
 		req.TenderID = tenderID
 
-		res, err := ts.GetBids(req)
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
 
+		format, err := export.FromRequest(r)
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
+			response.Render(w, r, err)
+			return
+		}
 
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
+		var res ResponseList
+		var ew *export.Writer
+		actor := service.Actor{Username: req.Username, OrganizationID: req.OrganizationID}
+		page := service.Page{Limit: req.Limit, Offset: req.OffSet}
+		if format == export.JSON {
+			err = ts.Bids(r.Context(), actor, req.TenderID, page, func(el service.Bid) error {
+				res.Response = append(res.Response, responseOf(el))
+				return nil
+			})
+		} else {
+			ew = export.NewWriter(w, format, "bids", columns...)
+			err = ts.Bids(r.Context(), actor, req.TenderID, page, func(el service.Bid) error {
+				return ew.Write(row(responseOf(el))...)
+			})
+			if err == nil {
+				err = ew.Close()
 			}
+		}
 
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
+		if err != nil {
+			// The table is already partly sent, there is no status left
+			// to report the error with.
+			if ew.Started() {
 				return
 			}
 
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
+			return
+		}
+
+		if ew != nil {
 			return
 		}
 
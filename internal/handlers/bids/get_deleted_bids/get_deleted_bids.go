@@ -0,0 +1,115 @@
+package getdeletedbids
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	Limit    uint
+	OffSet   uint
+	UserName string
+}
+
+type Response struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	Status         string    `json:"status"`
+	TenderID       uuid.UUID `json:"tenderId"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	DeletedAt      string    `json:"deletedAt"`
+}
+
+type ResponseList struct {
+	Response []Response
+}
+
+type DeletedBidsGetter interface {
+	DeletedBids(ctx context.Context, userName string, page service.Page) ([]service.Bid, error)
+}
+
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:             el.ID,
+		Name:           el.Name,
+		Status:         string(el.Status),
+		TenderID:       el.TenderID,
+		OrganizationID: el.OrganizationID,
+		DeletedAt:      time_converter.OptionalTime(el.DeletedAt),
+	}
+}
+
+const (
+	limitDefault  = 5
+	offsetDefault = 0
+)
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	has := r.URL.Query().Has("limit")
+	if has {
+		limit := r.URL.Query().Get("limit")
+		if value, err := strconv.Atoi(limit); err != nil {
+			return response.Invalid("invalid limit")
+		} else {
+			if value < 0 {
+				return response.Invalid("limit must be not negative")
+			}
+			req.Limit = uint(value)
+		}
+	} else {
+		req.Limit = limitDefault
+	}
+
+	has = r.URL.Query().Has("offset")
+	if has {
+		offset := r.URL.Query().Get("offset")
+		if value, err := strconv.Atoi(offset); err != nil {
+			return response.Invalid("invalid offset")
+		} else {
+			if value < 0 {
+				return response.Invalid("offset must be not negative")
+			}
+			req.OffSet = uint(value)
+		}
+	} else {
+		req.OffSet = offsetDefault
+	}
+
+	req.UserName = r.URL.Query().Get("username")
+
+	return nil
+
+}
+
+func New(ts DeletedBidsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		err := validateBadrequest(&req, r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		bids, err := ts.DeletedBids(r.Context(), req.UserName, service.Page{Limit: req.Limit, Offset: req.OffSet})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(bids))
+		for _, el := range bids {
+			res = append(res, responseOf(el))
+		}
+		render.JSON(w, r, res)
+
+	}
+}
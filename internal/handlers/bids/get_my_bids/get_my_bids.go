@@ -1,30 +1,33 @@
 package getmybids
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	Limit    uint `validate:"gte=0"`
-	OffSet   uint `validate:"gte=0"`
+	Limit    uint
+	OffSet   uint
 	UserName string
 }
 
 type Response struct {
-	ID          uuid.UUID `json:"id"`
-	Version     uint      `json:"version"`
-	CreatedAt   string    `json:"createdAt"`
-	Name        string    `json:"name" validate:"max=100"`
-	AuthorType  string    `json:"authorType"`
-	AuthorID    uuid.UUID `json:"authorId"`
-	Description string    `json:"description" validate:"max=500"`
-	Status      string    `json:"status"`
+	ID          uuid.UUID  `json:"id"`
+	Version     uint       `json:"version"`
+	CreatedAt   string     `json:"createdAt"`
+	Name        string     `json:"name" validate:"max=100"`
+	AuthorType  string     `json:"authorType"`
+	AuthorID    uuid.UUID  `json:"authorId"`
+	Description string     `json:"description" validate:"max=500"`
+	Status      string     `json:"status"`
+	LotID       *uuid.UUID `json:"lotId,omitempty"`
 }
 
 type ResponseList struct {
@@ -32,7 +35,21 @@ type ResponseList struct {
 }
 
 type MyBidsGetter interface {
-	GetMyBids(req Request) (ResponseList, error)
+	MyBids(ctx context.Context, userName string, page service.Page, fn func(el service.Bid) error) error
+}
+
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		AuthorType:  string(el.AuthorType),
+		AuthorID:    el.AuthorID,
+		Description: el.Description,
+		Status:      string(el.Status),
+		LotID:       el.LotID,
+	}
 }
 
 const (
@@ -45,10 +62,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		limit := r.URL.Query().Get("limit")
 		if value, err := strconv.Atoi(limit); err != nil {
-			return err
+			return response.Invalid("invalid limit")
 		} else {
 			if value < 0 {
-				return errors.New("limit must be not negative")
+				return response.Invalid("limit must be not negative")
 			}
 			req.Limit = uint(value)
 		}
@@ -60,10 +77,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		offset := r.URL.Query().Get("offset")
 		if value, err := strconv.Atoi(offset); err != nil {
-			return err
+			return response.Invalid("invalid offset")
 		} else {
 			if value < 0 {
-				return errors.New("offset must be not negative")
+				return response.Invalid("offset must be not negative")
 			}
 			req.OffSet = uint(value)
 		}
@@ -76,7 +93,7 @@ func validateBadrequest(req *Request, r *http.Request) error {
 		req.UserName = r.URL.Query().Get("username")
 
 	} else {
-		return errors.New("username must be not empty")
+		return response.Invalid("username must be not empty")
 	}
 
 	return nil
@@ -89,21 +106,17 @@ func New(ts MyBidsGetter) http.HandlerFunc {
 
 		err := validateBadrequest(&req, r)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
-		res, err := ts.GetMyBids(req)
+		var res ResponseList
+		err = ts.MyBids(r.Context(), req.UserName, service.Page{Limit: req.Limit, Offset: req.OffSet}, func(el service.Bid) error {
+			res.Response = append(res.Response, responseOf(el))
+			return nil
+		})
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
@@ -1,10 +1,14 @@
 package getreviews
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/export"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -12,17 +16,29 @@ import (
 )
 
 type Request struct {
-	Limit             uint `validate:"gte=0"`
-	OffSet            uint `validate:"gte=0"`
+	Limit             uint
+	OffSet            uint
 	AuthorUsername    string
 	RequesterUsername string
-	TenderID          uuid.UUID `validate:"uuid,required"`
+	TenderID          uuid.UUID
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
 }
 
 type Response struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   string    `json:"createdAt"`
-	Description string    `json:"description" validate:"max=500"`
+	ID               uuid.UUID  `json:"id"`
+	CreatedAt        string     `json:"createdAt"`
+	Description      string     `json:"description" validate:"max=500"`
+	BidID            uuid.UUID  `json:"bidId"`
+	ParentID         *uuid.UUID `json:"parentId,omitempty"`
+	Rating           *int       `json:"rating,omitempty"`
+	AuthorUsername   string     `json:"authorUsername"`
+	AuthorName       string     `json:"authorName"`
+	OrganizationID   uuid.UUID  `json:"organizationId"`
+	OrganizationName string     `json:"organizationName"`
+	EditedAt         string     `json:"editedAt,omitempty"`
+	// Replies are the messages answering this one, oldest first
+	Replies []Response `json:"replies"`
 }
 
 type ResponseList struct {
@@ -30,7 +46,57 @@ type ResponseList struct {
 }
 
 type ReviewsGetter interface {
-	GetReviews(req Request) (ResponseList, error)
+	ReviewThreads(ctx context.Context, actor service.Actor, query service.ReviewsQuery) ([]service.Message, error)
+	// Reviews passes the messages ReviewThreads would return to fn one by
+	// one, oldest first and without nesting replies.
+	Reviews(ctx context.Context, actor service.Actor, query service.ReviewsQuery, fn func(el service.Message) error) error
+}
+
+func responseOf(el service.Message) Response {
+	res := Response{
+		ID:               el.ID,
+		CreatedAt:        time_converter.Time(el.CreatedAt),
+		Description:      el.Text,
+		BidID:            el.BidID,
+		ParentID:         el.ParentID,
+		Rating:           el.Rating,
+		AuthorUsername:   el.AuthorUsername,
+		AuthorName:       el.AuthorName,
+		OrganizationID:   el.OrganizationID,
+		OrganizationName: el.OrganizationName,
+		EditedAt:         time_converter.OptionalTime(el.EditedAt),
+		Replies:          make([]Response, 0, len(el.Replies)),
+	}
+	for _, reply := range el.Replies {
+		res.Replies = append(res.Replies, responseOf(reply))
+	}
+	return res
+}
+
+var columns = []string{"id", "bidId", "parentId", "createdAt", "editedAt", "rating",
+	"authorUsername", "authorName", "organizationId", "organizationName", "description"}
+
+func row(el Response) []string {
+	var parentID, rating string
+	if el.ParentID != nil {
+		parentID = el.ParentID.String()
+	}
+	if el.Rating != nil {
+		rating = strconv.Itoa(*el.Rating)
+	}
+	return []string{
+		el.ID.String(),
+		el.BidID.String(),
+		parentID,
+		el.CreatedAt,
+		el.EditedAt,
+		rating,
+		el.AuthorUsername,
+		el.AuthorName,
+		el.OrganizationID.String(),
+		el.OrganizationName,
+		el.Description,
+	}
 }
 
 const (
@@ -43,10 +109,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		limit := r.URL.Query().Get("limit")
 		if value, err := strconv.Atoi(limit); err != nil {
-			return err
+			return response.Invalid("invalid limit")
 		} else {
 			if value < 0 {
-				return errors.New("limit must be not negative")
+				return response.Invalid("limit must be not negative")
 			}
 			req.Limit = uint(value)
 		}
@@ -58,10 +124,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		offset := r.URL.Query().Get("offset")
 		if value, err := strconv.Atoi(offset); err != nil {
-			return err
+			return response.Invalid("invalid offset")
 		} else {
 			if value < 0 {
-				return errors.New("offset must be not negative")
+				return response.Invalid("offset must be not negative")
 			}
 			req.OffSet = uint(value)
 		}
@@ -74,7 +140,7 @@ func validateBadrequest(req *Request, r *http.Request) error {
 		req.AuthorUsername = r.URL.Query().Get("authorUsername")
 
 	} else {
-		return errors.New("authorUsername must be not empty")
+		return response.Invalid("authorUsername must be not empty")
 	}
 
 	has = r.URL.Query().Has("requesterUsername")
@@ -82,7 +148,7 @@ func validateBadrequest(req *Request, r *http.Request) error {
 		req.RequesterUsername = r.URL.Query().Get("requesterUsername")
 
 	} else {
-		return errors.New("requesterUsername must be not empty")
+		return response.Invalid("requesterUsername must be not empty")
 	}
 
 	return nil
@@ -96,8 +162,7 @@ func New(ts ReviewsGetter) http.HandlerFunc {
 		tenderID, err := uuid.Parse(tenderStr)
 
 		if err != nil || tenderStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid  tender id"))
+			response.Render(w, r, response.InvalidID("tenderId"))
 			return
 		}
 		req.TenderID = tenderID
@@ -105,42 +170,64 @@ func New(ts ReviewsGetter) http.HandlerFunc {
 		err = validateBadrequest(&req, r)
 
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
-		res, err := ts.GetReviews(req)
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
 
+		format, err := export.FromRequest(r)
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
+			response.Render(w, r, err)
+			return
+		}
 
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
+		var threads []service.Message
+		var ew *export.Writer
+		actor := service.Actor{Username: req.RequesterUsername, OrganizationID: req.OrganizationID}
+		query := service.ReviewsQuery{
+			TenderID:       req.TenderID,
+			AuthorUsername: req.AuthorUsername,
+			Page:           service.Page{Limit: req.Limit, Offset: req.OffSet},
+		}
+		if format == export.JSON {
+			threads, err = ts.ReviewThreads(r.Context(), actor, query)
+		} else {
+			ew = export.NewWriter(w, format, "reviews", columns...)
+			err = ts.Reviews(r.Context(), actor, query, func(el service.Message) error {
+				return ew.Write(row(responseOf(el))...)
+			})
+			if err == nil {
+				err = ew.Close()
 			}
+		}
 
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
+		if err != nil {
+			// The table is already partly sent, there is no status left
+			// to report the error with.
+			if ew.Started() {
 				return
 			}
 
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
+			return
+		}
+
+		if ew != nil {
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		if res.Response == nil {
-			res.Response = make([]Response, 0)
+		res := make([]Response, 0, len(threads))
+		for _, el := range threads {
+			res = append(res, responseOf(el))
 		}
-		render.JSON(w, r, res.Response)
+		render.JSON(w, r, res)
 
 	}
 }
@@ -1,105 +1,112 @@
 package newbid
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"tender_service/internal/lib/actingorg"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 	models2 "tender_service/internal/storage/models"
 
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	Name        string    `json:"name" validate:"required,max=100"`
-	TenderId    uuid.UUID `json:"tenderId" validate:"required,uuid"`
-	Description string    `json:"description" validate:"required,max=500"`
-	AuthorType  string    `json:"authorType" validate:"required"`
-	AuthorID    uuid.UUID `json:"authorId" validate:"required,uuid"`
+	Name        string    `json:"name"`
+	TenderId    uuid.UUID `json:"tenderId"`
+	Description string    `json:"description"`
+	AuthorType  string    `json:"authorType"`
+	AuthorID    uuid.UUID `json:"authorId"`
+	// LotID is the lot of the tender the bid is made for
+	LotID uuid.UUID `json:"lotId"`
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID `json:"organizationId"`
 }
 
 type Response struct {
-	ID          uuid.UUID `json:"id"`
-	Version     uint      `json:"version"`
-	CreatedAt   string    `json:"createdAt"`
-	Name        string    `json:"name" validate:"required,max=100"`
-	Description string    `json:"description" validate:"required,max=500"`
-	AuthorType  string    `json:"authorType"`
-	Status      string    `json:"status" validate:"required"`
-	AuthorID    uuid.UUID `json:"authorId"`
+	ID          uuid.UUID  `json:"id"`
+	Version     uint       `json:"version"`
+	CreatedAt   string     `json:"createdAt"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Description string     `json:"description" validate:"required,max=500"`
+	AuthorType  string     `json:"authorType"`
+	Status      string     `json:"status" validate:"required"`
+	AuthorID    uuid.UUID  `json:"authorId"`
+	LotID       *uuid.UUID `json:"lotId,omitempty"`
 }
 
 type BidSaver interface {
-	SaveBid(req Request) (Response, error)
+	SubmitBid(ctx context.Context, in service.NewBid) (service.Bid, error)
+}
+
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		AuthorType:  string(el.AuthorType),
+		Status:      string(el.Status),
+		AuthorID:    el.AuthorID,
+		LotID:       el.LotID,
+	}
 }
 
-func validateBadrequest(req *Request, r *http.Request) string {
+func validateBadrequest(req *Request, r *http.Request) error {
 	const op = "handlers.newBid.ValidateBadRequest"
 	err := render.DecodeJSON(r.Body, &req)
 	if errors.Is(err, io.EOF) {
-		return "request body is empty"
+		return response.ErrEmptyBody
 	}
 	if err != nil {
 		slog.Info(err.Error(), slog.String("op", op))
-		return "invalid request"
+		return response.ErrInvalidRequest
 	}
 
-	if !models2.ValidateBidAuthorType(models2.BidAuthorType(req.AuthorType)) {
-		return "invalid authorType"
-	}
-
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errMsgs
-	}
-
-	return ""
+	return nil
 }
 
 func New(ts BidSaver) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req Request
 
-		if errMsg := validateBadrequest(&req, r); errMsg != "" {
-			w.WriteHeader(http.StatusBadRequest)
-
-			render.JSON(w, r, response.Error(errMsg))
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
 			return
 		}
 
-		res, err := ts.SaveBid(req)
-
+		orgID, err := actingorg.FromRequest(r)
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) || errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
+			response.Render(w, r, err)
+			return
+		}
+		if req.OrganizationID == uuid.Nil {
+			req.OrganizationID = orgID
+		}
 
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
+		bid, err := ts.SubmitBid(r.Context(), service.NewBid{
+			TenderID:       req.TenderId,
+			Name:           req.Name,
+			Description:    req.Description,
+			AuthorType:     models2.BidAuthorType(req.AuthorType),
+			AuthorID:       req.AuthorID,
+			LotID:          req.LotID,
+			OrganizationID: req.OrganizationID,
+		})
 
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+		if err != nil {
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(bid))
 
 	}
 }
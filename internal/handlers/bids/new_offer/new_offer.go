@@ -0,0 +1,84 @@
+package newoffer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/tenders/get_auction"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	BidID    uuid.UUID
+	UserName string
+	Price    float64 `json:"price"`
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
+}
+
+type OfferPlacer interface {
+	PlaceOffer(ctx context.Context, actor service.Actor, bidID uuid.UUID, price float64) (service.Auction, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.newOffer.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts OfferPlacer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		bidStr := chi.URLParam(r, "bidId")
+		bidID, err := uuid.Parse(bidStr)
+
+		if err != nil || bidStr == "" {
+			response.Render(w, r, response.InvalidID("bidId"))
+			return
+		}
+		req.BidID = bidID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		auction, err := ts.PlaceOffer(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID, req.Price)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, getauction.ResponseOf(auction))
+
+	}
+}
@@ -1,66 +1,76 @@
 package patchbid
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"tender_service/internal/lib/actingorg"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 	models2 "tender_service/internal/storage/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
 	UserName    string
-	BidID       uuid.UUID `validate:"uuid"`
+	BidID       uuid.UUID
 	TenderID    uuid.UUID `json:"tenderId"`
-	Name        string    `json:"name" validate:"max=100"`
-	Description string    `json:"description" validate:"max=500"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
 	Status      string    `json:"status"`
+	LotID       uuid.UUID `json:"lotId"`
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
 }
 
 type Response struct {
-	ID          uuid.UUID `json:"id"`
-	Version     uint      `json:"version"`
-	CreatedAt   string    `json:"createdAt"`
-	Name        string    `json:"name" validate:"required,max=100"`
-	Description string    `json:"description" validate:"required,max=500"`
-	AuthorType  string    `json:"authorType"`
-	Status      string    `json:"status" validate:"required"`
-	AuthorID    uuid.UUID `json:"authorId"`
+	ID          uuid.UUID  `json:"id"`
+	Version     uint       `json:"version"`
+	CreatedAt   string     `json:"createdAt"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Description string     `json:"description" validate:"required,max=500"`
+	AuthorType  string     `json:"authorType"`
+	Status      string     `json:"status" validate:"required"`
+	AuthorID    uuid.UUID  `json:"authorId"`
+	LotID       *uuid.UUID `json:"lotId,omitempty"`
 }
 
 type BidPatcher interface {
-	PatchBid(req Request) (Response, error)
+	EditBid(ctx context.Context, actor service.Actor, patch service.BidPatch) (service.Bid, error)
 }
 
-func validateBadrequest(req *Request, r *http.Request) string {
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		AuthorType:  string(el.AuthorType),
+		Status:      string(el.Status),
+		AuthorID:    el.AuthorID,
+		LotID:       el.LotID,
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
 	err := render.DecodeJSON(r.Body, req)
 	if errors.Is(err, io.EOF) {
-		return "request body is empty"
+		return response.ErrEmptyBody
 	}
 
 	if err != nil {
 		slog.Info(err.Error())
-		return "invalid request"
+		return response.ErrInvalidRequest
 	}
 
-	if !models2.ValidateBidStatus(models2.BidStatus(req.Status)) && req.Status != "" {
-		return "incorrect bid status"
-	}
-
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errMsgs
-	}
-
-	return ""
+	return nil
 }
 
 func New(ts BidPatcher) http.HandlerFunc {
@@ -71,54 +81,40 @@ func New(ts BidPatcher) http.HandlerFunc {
 		bidID, err := uuid.Parse(bidStr)
 
 		if err != nil || bidStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("bidId"))
 			return
 		}
 		req.BidID = bidID
 
 		req.UserName = r.URL.Query().Get("username")
 
-		if errMsg := validateBadrequest(&req, r); errMsg != "" {
-			w.WriteHeader(http.StatusBadRequest)
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
 
-			render.JSON(w, r, response.Error(errMsg))
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
 			return
 		}
+		req.OrganizationID = orgID
 
-		res, err := ts.PatchBid(req)
+		bid, err := ts.EditBid(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, service.BidPatch{
+			BidID:       req.BidID,
+			TenderID:    req.TenderID,
+			Name:        req.Name,
+			Description: req.Description,
+			Status:      models2.BidStatus(req.Status),
+			LotID:       req.LotID,
+		})
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrBidNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(bid))
 	}
 }
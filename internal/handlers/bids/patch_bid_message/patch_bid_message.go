@@ -0,0 +1,129 @@
+package patchbidmessage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	BidID     uuid.UUID
+	MessageID uuid.UUID
+	UserName  string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
+	Message        string `json:"message"`
+	Rating         *int   `json:"rating"`
+}
+
+type Response struct {
+	ID               uuid.UUID  `json:"id"`
+	BidID            uuid.UUID  `json:"bidId"`
+	ParentID         *uuid.UUID `json:"parentId,omitempty"`
+	Message          string     `json:"message"`
+	Rating           *int       `json:"rating,omitempty"`
+	AuthorUsername   string     `json:"authorUsername"`
+	AuthorName       string     `json:"authorName"`
+	OrganizationID   uuid.UUID  `json:"organizationId"`
+	OrganizationName string     `json:"organizationName"`
+	CreatedAt        string     `json:"createdAt"`
+	EditedAt         string     `json:"editedAt,omitempty"`
+}
+
+type BidMessagePatcher interface {
+	EditMessage(ctx context.Context, actor service.Actor, patch service.MessagePatch) (service.Message, error)
+}
+
+func responseOf(el service.Message) Response {
+	return Response{
+		ID:               el.ID,
+		BidID:            el.BidID,
+		ParentID:         el.ParentID,
+		Message:          el.Text,
+		Rating:           el.Rating,
+		AuthorUsername:   el.AuthorUsername,
+		AuthorName:       el.AuthorName,
+		OrganizationID:   el.OrganizationID,
+		OrganizationName: el.OrganizationName,
+		CreatedAt:        time_converter.Time(el.CreatedAt),
+		EditedAt:         time_converter.OptionalTime(el.EditedAt),
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error())
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts BidMessagePatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		bidStr := chi.URLParam(r, "bidId")
+		bidID, err := uuid.Parse(bidStr)
+
+		if err != nil || bidStr == "" {
+			response.Render(w, r, response.InvalidID("bidId"))
+			return
+		}
+		req.BidID = bidID
+
+		messageStr := chi.URLParam(r, "messageId")
+		messageID, err := uuid.Parse(messageStr)
+
+		if err != nil || messageStr == "" {
+			response.Render(w, r, response.InvalidID("messageId"))
+			return
+		}
+		req.MessageID = messageID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		message, err := ts.EditMessage(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, service.MessagePatch{
+			BidID:     req.BidID,
+			MessageID: req.MessageID,
+			Text:      req.Message,
+			Rating:    req.Rating,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, responseOf(message))
+
+	}
+}
@@ -1,21 +1,25 @@
 package putbidstatus
 
 import (
-	"errors"
+	"context"
 	"net/http"
+	"tender_service/internal/lib/actingorg"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 	models2 "tender_service/internal/storage/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	BidID    uuid.UUID `validate:"required,uuid"`
+	BidID    uuid.UUID
 	UserName string
-	Status   string `validate:"required"`
+	Status   string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
 }
 
 type Response struct {
@@ -30,23 +34,20 @@ type Response struct {
 }
 
 type BidStatusPutter interface {
-	BidStatusPutter(req Request) (Response, error)
+	SetBidStatus(ctx context.Context, actor service.Actor, bidID uuid.UUID, status models2.BidStatus) (service.Bid, error)
 }
 
-func validateBadrequest(req *Request) string {
-
-	if !models2.ValidateBidStatus(models2.BidStatus(req.Status)) {
-		return "incorrect bid status"
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		AuthorType:  string(el.AuthorType),
+		Status:      string(el.Status),
+		AuthorID:    el.AuthorID,
 	}
-
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errMsgs
-	}
-
-	return ""
 }
 
 func New(ts BidStatusPutter) http.HandlerFunc {
@@ -57,8 +58,7 @@ func New(ts BidStatusPutter) http.HandlerFunc {
 		bidID, err := uuid.Parse(bidStr)
 
 		if err != nil || bidStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("bidId"))
 			return
 		}
 		req.BidID = bidID
@@ -66,41 +66,22 @@ func New(ts BidStatusPutter) http.HandlerFunc {
 		req.UserName = r.URL.Query().Get("username")
 		req.Status = r.URL.Query().Get("status")
 
-		if errMsg := validateBadrequest(&req); errMsg != "" {
-			w.WriteHeader(http.StatusBadRequest)
-
-			render.JSON(w, r, response.Error(errMsg))
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
 			return
 		}
+		req.OrganizationID = orgID
 
-		res, err := ts.BidStatusPutter(req)
+		bid, err := ts.SetBidStatus(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID, models2.BidStatus(req.Status))
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrBidNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(bid))
 
 	}
 }
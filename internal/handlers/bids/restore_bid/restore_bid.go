@@ -0,0 +1,82 @@
+package restorebid
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	BidID    uuid.UUID
+	UserName string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
+}
+
+type Response struct {
+	ID          uuid.UUID `json:"id"`
+	Version     uint      `json:"version"`
+	CreatedAt   string    `json:"createdAt"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	TenderID    uuid.UUID `json:"tenderId"`
+}
+
+type BidRestorer interface {
+	RestoreBid(ctx context.Context, actor service.Actor, bidID uuid.UUID) (service.Bid, error)
+}
+
+func responseOf(el service.Bid) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		Status:      string(el.Status),
+		TenderID:    el.TenderID,
+	}
+}
+
+func New(ts BidRestorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		bidStr := chi.URLParam(r, "bidId")
+		bidID, err := uuid.Parse(bidStr)
+
+		if err != nil || bidStr == "" {
+			response.Render(w, r, response.InvalidID("bidId"))
+			return
+		}
+		req.BidID = bidID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		bid, err := ts.RestoreBid(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, responseOf(bid))
+
+	}
+}
@@ -0,0 +1,60 @@
+package resubmitbid
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/handlers/bids/withdraw_bid"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	BidID    uuid.UUID
+	UserName string
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
+}
+
+type BidResubmitter interface {
+	ResubmitBid(ctx context.Context, actor service.Actor, bidID uuid.UUID) (service.Bid, error)
+}
+
+func New(ts BidResubmitter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		bidStr := chi.URLParam(r, "bidId")
+		bidID, err := uuid.Parse(bidStr)
+
+		if err != nil || bidStr == "" {
+			response.Render(w, r, response.InvalidID("bidId"))
+			return
+		}
+		req.BidID = bidID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		bid, err := ts.ResubmitBid(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, withdrawbid.ResponseOf(bid))
+
+	}
+}
@@ -0,0 +1,110 @@
+package withdrawbid
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	BidID    uuid.UUID
+	UserName string
+	Reason   string `json:"reason"`
+	// OrganizationID is the organization the user acts on behalf of
+	OrganizationID uuid.UUID
+}
+
+type Response struct {
+	ID               uuid.UUID `json:"id"`
+	Version          uint      `json:"version"`
+	CreatedAt        string    `json:"createdAt"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	AuthorType       string    `json:"authorType"`
+	Status           string    `json:"status"`
+	AuthorID         uuid.UUID `json:"authorId"`
+	WithdrawalReason string    `json:"withdrawalReason,omitempty"`
+}
+
+type BidWithdrawer interface {
+	WithdrawBid(ctx context.Context, actor service.Actor, bidID uuid.UUID, reason string) (service.Bid, error)
+}
+
+func ResponseOf(el service.Bid) Response {
+	return Response{
+		ID:               el.ID,
+		Version:          el.Version,
+		CreatedAt:        time_converter.Time(el.CreatedAt),
+		Name:             el.Name,
+		Description:      el.Description,
+		AuthorType:       string(el.AuthorType),
+		Status:           string(el.Status),
+		AuthorID:         el.AuthorID,
+		WithdrawalReason: el.WithdrawalReason,
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.withdrawBid.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts BidWithdrawer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		bidStr := chi.URLParam(r, "bidId")
+		bidID, err := uuid.Parse(bidStr)
+
+		if err != nil || bidStr == "" {
+			response.Render(w, r, response.InvalidID("bidId"))
+			return
+		}
+		req.BidID = bidID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		bid, err := ts.WithdrawBid(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID}, req.BidID, req.Reason)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, ResponseOf(bid))
+
+	}
+}
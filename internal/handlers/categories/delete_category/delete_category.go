@@ -0,0 +1,54 @@
+package deletecategory
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	CategoryID uuid.UUID
+	UserName   string
+}
+
+type Response struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type CategoryDeleter interface {
+	DeleteCategory(ctx context.Context, userName string, categoryID uuid.UUID) (service.Category, error)
+}
+
+func New(ts CategoryDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		categoryStr := chi.URLParam(r, "categoryId")
+		categoryID, err := uuid.Parse(categoryStr)
+
+		if err != nil || categoryStr == "" {
+			response.Render(w, r, response.InvalidID("categoryId"))
+			return
+		}
+		req.CategoryID = categoryID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		category, err := ts.DeleteCategory(r.Context(), req.UserName, req.CategoryID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, Response{ID: category.ID, Name: category.Name})
+
+	}
+}
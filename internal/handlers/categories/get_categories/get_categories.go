@@ -0,0 +1,80 @@
+package getcategories
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	ParentID uuid.UUID
+}
+
+type Response struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *uuid.UUID `json:"parentId"`
+	CreatedAt string     `json:"createdAt"`
+}
+
+type ResponseList struct {
+	Response []Response
+}
+
+type CategoriesGetter interface {
+	Categories(ctx context.Context, parentID uuid.UUID) ([]service.Category, error)
+}
+
+func responseOf(el service.Category) Response {
+	return Response{
+		ID:        el.ID,
+		Name:      el.Name,
+		ParentID:  el.ParentID,
+		CreatedAt: time_converter.Time(el.CreatedAt),
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	has := r.URL.Query().Has("parentId")
+	if has {
+		parentID, err := uuid.Parse(r.URL.Query().Get("parentId"))
+		if err != nil {
+			return response.Invalid("invalid parent id")
+		}
+		req.ParentID = parentID
+	}
+
+	return nil
+}
+
+func New(ts CategoriesGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		err := validateBadrequest(&req, r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		categories, err := ts.Categories(r.Context(), req.ParentID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(categories))
+		for _, el := range categories {
+			res = append(res, responseOf(el))
+		}
+		render.JSON(w, r, res)
+
+	}
+}
@@ -0,0 +1,80 @@
+package newcategory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	UserName string
+	Name     string    `json:"name"`
+	ParentID uuid.UUID `json:"parentId"`
+}
+
+type Response struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *uuid.UUID `json:"parentId"`
+	CreatedAt string     `json:"createdAt"`
+}
+
+type CategorySaver interface {
+	CreateCategory(ctx context.Context, userName string, in service.NewCategory) (service.Category, error)
+}
+
+func responseOf(el service.Category) Response {
+	return Response{
+		ID:        el.ID,
+		Name:      el.Name,
+		ParentID:  el.ParentID,
+		CreatedAt: time_converter.Time(el.CreatedAt),
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.newCategory.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts CategorySaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		category, err := ts.CreateCategory(r.Context(), req.UserName, service.NewCategory{Name: req.Name, ParentID: req.ParentID})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, responseOf(category))
+
+	}
+}
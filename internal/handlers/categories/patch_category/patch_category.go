@@ -0,0 +1,96 @@
+package patchcategory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	CategoryID uuid.UUID
+	UserName   string
+	Name       string    `json:"name"`
+	ParentID   uuid.UUID `json:"parentId"`
+	// DetachParent moves the category to the root of the tree
+	DetachParent bool `json:"detachParent"`
+}
+
+type Response struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *uuid.UUID `json:"parentId"`
+	CreatedAt string     `json:"createdAt"`
+}
+
+type CategoryPatcher interface {
+	EditCategory(ctx context.Context, userName string, patch service.CategoryPatch) (service.Category, error)
+}
+
+func responseOf(el service.Category) Response {
+	return Response{
+		ID:        el.ID,
+		Name:      el.Name,
+		ParentID:  el.ParentID,
+		CreatedAt: time_converter.Time(el.CreatedAt),
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+	if err != nil {
+		slog.Info(err.Error())
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts CategoryPatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		categoryStr := chi.URLParam(r, "categoryId")
+		categoryID, err := uuid.Parse(categoryStr)
+
+		if err != nil || categoryStr == "" {
+			response.Render(w, r, response.InvalidID("categoryId"))
+			return
+		}
+		req.CategoryID = categoryID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		category, err := ts.EditCategory(r.Context(), req.UserName, service.CategoryPatch{
+			CategoryID:   req.CategoryID,
+			Name:         req.Name,
+			ParentID:     req.ParentID,
+			DetachParent: req.DetachParent,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, responseOf(category))
+
+	}
+}
@@ -0,0 +1,60 @@
+package getmyorganizations
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	UserName string
+}
+
+type Response struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	Role        string    `json:"role"`
+}
+
+type ResponseList struct {
+	Response []Response
+}
+
+type OrganizationsGetter interface {
+	MyOrganizations(ctx context.Context, userName string) ([]service.Membership, error)
+}
+
+func New(ts OrganizationsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		req.UserName = r.URL.Query().Get("username")
+
+		memberships, err := ts.MyOrganizations(r.Context(), req.UserName)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(memberships))
+		for _, el := range memberships {
+			res = append(res, Response{
+				ID:          el.OrganizationID,
+				Name:        el.Name,
+				Description: el.Description,
+				Type:        string(el.Type),
+				Role:        string(el.Role),
+			})
+		}
+		render.JSON(w, r, res)
+
+	}
+}
@@ -0,0 +1,57 @@
+package getnotificationsettings
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	UserName string
+}
+
+type Response struct {
+	Email         string `json:"email"`
+	Locale        string `json:"locale"`
+	NewBid        bool   `json:"newBid"`
+	Decision      bool   `json:"decision"`
+	Feedback      bool   `json:"feedback"`
+	TenderClosing bool   `json:"tenderClosing"`
+}
+
+type NotificationSettingsGetter interface {
+	NotificationSettings(ctx context.Context, userName string) (service.NotificationSettings, error)
+}
+
+func ResponseOf(el service.NotificationSettings) Response {
+	return Response{
+		Email:         el.Email,
+		Locale:        el.Locale,
+		NewBid:        el.NewBid,
+		Decision:      el.Decision,
+		Feedback:      el.Feedback,
+		TenderClosing: el.TenderClosing,
+	}
+}
+
+func New(ts NotificationSettingsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		req.UserName = r.URL.Query().Get("username")
+
+		settings, err := ts.NotificationSettings(r.Context(), req.UserName)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, ResponseOf(settings))
+
+	}
+}
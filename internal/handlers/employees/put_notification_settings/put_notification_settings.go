@@ -0,0 +1,84 @@
+package putnotificationsettings
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/employees/get_notification_settings"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/render"
+)
+
+// Request replaces the settings, the kinds of emails left out stay on.
+type Request struct {
+	UserName      string
+	Email         string `json:"email"`
+	Locale        string `json:"locale"`
+	NewBid        *bool  `json:"newBid"`
+	Decision      *bool  `json:"decision"`
+	Feedback      *bool  `json:"feedback"`
+	TenderClosing *bool  `json:"tenderClosing"`
+}
+
+type NotificationSettingsPutter interface {
+	SetNotificationSettings(ctx context.Context, userName string, in service.NotificationSettings) (service.NotificationSettings, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.putNotificationSettings.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func enabled(value *bool) bool {
+	return value == nil || *value
+}
+
+// Input is the use case input of a request.
+func Input(req Request) service.NotificationSettings {
+	return service.NotificationSettings{
+		Email:         req.Email,
+		Locale:        req.Locale,
+		NewBid:        enabled(req.NewBid),
+		Decision:      enabled(req.Decision),
+		Feedback:      enabled(req.Feedback),
+		TenderClosing: enabled(req.TenderClosing),
+	}
+}
+
+func New(ts NotificationSettingsPutter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		settings, err := ts.SetNotificationSettings(r.Context(), req.UserName, Input(req))
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, getnotificationsettings.ResponseOf(settings))
+
+	}
+}
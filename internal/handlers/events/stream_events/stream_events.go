@@ -0,0 +1,161 @@
+package streamevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	UserName       string
+	TenderID       uuid.UUID
+	OrganizationID uuid.UUID
+	// LastEventID is the last event the client has seen, nil for a new
+	// stream
+	LastEventID *uint64
+}
+
+type Response struct {
+	Type              string     `json:"type"`
+	TenderID          uuid.UUID  `json:"tenderId"`
+	OrganizationID    uuid.UUID  `json:"organizationId"`
+	BidID             *uuid.UUID `json:"bidId,omitempty"`
+	BidOrganizationID *uuid.UUID `json:"bidOrganizationId,omitempty"`
+	Status            string     `json:"status,omitempty"`
+	CreatedAt         string     `json:"createdAt"`
+}
+
+type EventsSubscriber interface {
+	Subscribe(ctx context.Context, actor service.Actor, query service.EventsQuery) (*service.Subscription, error)
+}
+
+func responseOf(el service.Event) Response {
+	return Response{
+		Type:              string(el.Type),
+		TenderID:          el.TenderID,
+		OrganizationID:    el.OrganizationID,
+		BidID:             el.BidID,
+		BidOrganizationID: el.BidOrganizationID,
+		Status:            el.Status,
+		CreatedAt:         time_converter.Time(el.CreatedAt),
+	}
+}
+
+func parseID(r *http.Request, param string) (uuid.UUID, error) {
+	value := r.URL.Query().Get(param)
+	if value == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, response.InvalidID(param)
+	}
+	return id, nil
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	req.UserName = r.URL.Query().Get("username")
+	if req.UserName == "" {
+		return response.Invalid("username must be not empty")
+	}
+
+	var err error
+	req.TenderID, err = parseID(r, "tenderId")
+	if err != nil {
+		return err
+	}
+
+	req.OrganizationID, err = parseID(r, "organizationId")
+	if err != nil {
+		return err
+	}
+
+	// Browsers resume with the header, the parameter is for the clients
+	// that can't set it.
+	last := r.Header.Get("Last-Event-ID")
+	if last == "" {
+		last = r.URL.Query().Get("lastEventId")
+	}
+	if last != "" {
+		value, err := strconv.ParseUint(last, 10, 64)
+		if err != nil {
+			return response.Invalid("invalid last event id")
+		}
+		req.LastEventID = &value
+	}
+
+	return nil
+}
+
+func New(ts EventsSubscriber) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		err := validateBadrequest(&req, r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		sub, err := ts.Subscribe(r.Context(), service.Actor{Username: req.UserName}, service.EventsQuery{
+			TenderID:       req.TenderID,
+			OrganizationID: req.OrganizationID,
+			LastEventID:    req.LastEventID,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		defer sub.Close()
+
+		// The stream outlives the server's write timeout.
+		rc := http.NewResponseController(w)
+		_ = rc.SetWriteDeadline(time.Time{})
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		for {
+			events, err := sub.Next(r.Context())
+			if err != nil {
+				// The status is already sent, the client reconnects and
+				// resumes after the last event it got.
+				return
+			}
+
+			if len(events) == 0 {
+				_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			for _, el := range events {
+				data, _ := json.Marshal(responseOf(el))
+				_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", el.ID, el.Type, data)
+				if err != nil {
+					break
+				}
+			}
+			if err != nil {
+				return
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
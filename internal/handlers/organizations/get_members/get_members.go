@@ -0,0 +1,71 @@
+package getmembers
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	OrganizationID uuid.UUID
+	UserName       string
+}
+
+type Response struct {
+	UserID    uuid.UUID `json:"userId"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	Role      string    `json:"role"`
+}
+
+type ResponseList struct {
+	Response []Response
+}
+
+type MembersGetter interface {
+	Members(ctx context.Context, userName string, orgID uuid.UUID) ([]service.Member, error)
+}
+
+func New(ts MembersGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		organizationStr := chi.URLParam(r, "organizationId")
+		organizationID, err := uuid.Parse(organizationStr)
+
+		if err != nil || organizationStr == "" {
+			response.Render(w, r, response.InvalidID("organizationId"))
+			return
+		}
+		req.OrganizationID = organizationID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		members, err := ts.Members(r.Context(), req.UserName, req.OrganizationID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(members))
+		for _, el := range members {
+			res = append(res, Response{
+				UserID:    el.UserID,
+				Username:  el.Username,
+				FirstName: el.FirstName,
+				LastName:  el.LastName,
+				Role:      string(el.Role),
+			})
+		}
+		render.JSON(w, r, res)
+
+	}
+}
@@ -0,0 +1,114 @@
+package getreputation
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	OrganizationID uuid.UUID
+	UserName       string
+}
+
+// Stats are the counters of a bidder. AverageRating is absent until the
+// bidder gets a rated feedback.
+type Stats struct {
+	BidsSubmitted int64    `json:"bidsSubmitted"`
+	BidsApproved  int64    `json:"bidsApproved"`
+	BidsRejected  int64    `json:"bidsRejected"`
+	AverageRating *float64 `json:"averageRating"`
+	WinRate       float64  `json:"winRate"`
+}
+
+type ServiceTypeStats struct {
+	ServiceType string `json:"serviceType"`
+	Stats
+}
+
+type EmployeeStats struct {
+	Username string `json:"username"`
+	Stats
+	ServiceTypes []ServiceTypeStats `json:"serviceTypes"`
+}
+
+type Response struct {
+	OrganizationID uuid.UUID `json:"organizationId"`
+	Name           string    `json:"name"`
+	Stats
+	ServiceTypes []ServiceTypeStats `json:"serviceTypes"`
+	Employees    []EmployeeStats    `json:"employees"`
+}
+
+type ReputationGetter interface {
+	Reputation(ctx context.Context, userName string, orgID uuid.UUID) (service.Reputation, error)
+}
+
+func statsOf(el service.ReputationStats) Stats {
+	return Stats{
+		BidsSubmitted: el.BidsSubmitted,
+		BidsApproved:  el.BidsApproved,
+		BidsRejected:  el.BidsRejected,
+		AverageRating: el.AverageRating,
+		WinRate:       el.WinRate,
+	}
+}
+
+func serviceTypesOf(list []service.ServiceTypeReputation) []ServiceTypeStats {
+	res := make([]ServiceTypeStats, 0, len(list))
+	for _, el := range list {
+		res = append(res, ServiceTypeStats{ServiceType: el.ServiceType, Stats: statsOf(el.ReputationStats)})
+	}
+	return res
+}
+
+func responseOf(el service.Reputation) Response {
+	res := Response{
+		OrganizationID: el.OrganizationID,
+		Name:           el.Name,
+		Stats:          statsOf(el.ReputationStats),
+		ServiceTypes:   serviceTypesOf(el.ServiceTypes),
+		Employees:      make([]EmployeeStats, 0, len(el.Employees)),
+	}
+	for _, employee := range el.Employees {
+		res.Employees = append(res.Employees, EmployeeStats{
+			Username:     employee.Username,
+			Stats:        statsOf(employee.ReputationStats),
+			ServiceTypes: serviceTypesOf(employee.ServiceTypes),
+		})
+	}
+	return res
+}
+
+func New(ts ReputationGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		organizationStr := chi.URLParam(r, "organizationId")
+		organizationID, err := uuid.Parse(organizationStr)
+
+		if err != nil || organizationStr == "" {
+			response.Render(w, r, response.InvalidID("organizationId"))
+			return
+		}
+		req.OrganizationID = organizationID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		reputation, err := ts.Reputation(r.Context(), req.UserName, req.OrganizationID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, responseOf(reputation))
+
+	}
+}
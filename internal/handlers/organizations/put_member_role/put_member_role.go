@@ -0,0 +1,68 @@
+package putmemberrole
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	models2 "tender_service/internal/storage/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	OrganizationID uuid.UUID
+	MemberID       uuid.UUID
+	UserName       string
+	Role           string
+}
+
+type Response struct {
+	UserID         uuid.UUID `json:"userId"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	Role           string    `json:"role"`
+}
+
+type MemberRolePutter interface {
+	SetMemberRole(ctx context.Context, userName string, orgID uuid.UUID, memberID uuid.UUID, role models2.OrganizationRole) error
+}
+
+func New(ts MemberRolePutter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		organizationStr := chi.URLParam(r, "organizationId")
+		organizationID, err := uuid.Parse(organizationStr)
+
+		if err != nil || organizationStr == "" {
+			response.Render(w, r, response.InvalidID("organizationId"))
+			return
+		}
+		req.OrganizationID = organizationID
+
+		memberStr := chi.URLParam(r, "userId")
+		memberID, err := uuid.Parse(memberStr)
+
+		if err != nil || memberStr == "" {
+			response.Render(w, r, response.InvalidID("userId"))
+			return
+		}
+		req.MemberID = memberID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		req.Role = r.URL.Query().Get("role")
+
+		err = ts.SetMemberRole(r.Context(), req.UserName, req.OrganizationID, req.MemberID, models2.OrganizationRole(req.Role))
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, Response{UserID: req.MemberID, OrganizationID: req.OrganizationID, Role: req.Role})
+
+	}
+}
@@ -0,0 +1,91 @@
+package answerquestion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/tenders/get_questions"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID   uuid.UUID
+	QuestionID uuid.UUID
+	UserName   string
+	Answer     string `json:"answer"`
+	Public     bool   `json:"public"`
+	// Description amends the tender's description as its next version
+	Description string `json:"description"`
+}
+
+type QuestionAnswerer interface {
+	AnswerQuestion(ctx context.Context, actor service.Actor, tenderID uuid.UUID, questionID uuid.UUID, in service.Answer) (service.Question, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.answerQuestion.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts QuestionAnswerer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		questionStr := chi.URLParam(r, "questionId")
+		questionID, err := uuid.Parse(questionStr)
+
+		if err != nil || questionStr == "" {
+			response.Render(w, r, response.InvalidID("questionId"))
+			return
+		}
+		req.QuestionID = questionID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		question, err := ts.AnswerQuestion(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, req.QuestionID, service.Answer{
+			Text:        req.Answer,
+			Public:      req.Public,
+			Description: req.Description,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, getquestions.ResponseOf(question))
+
+	}
+}
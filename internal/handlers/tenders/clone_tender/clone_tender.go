@@ -0,0 +1,77 @@
+package clonetender
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/tenders/new_tender"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+// Request may come without a body, the new tender then keeps the name
+// and has no submission deadline.
+type Request struct {
+	TenderID           uuid.UUID
+	UserName           string
+	Name               string     `json:"name"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline"`
+}
+
+type TenderCloner interface {
+	CloneTender(ctx context.Context, actor service.Actor, tenderID uuid.UUID, in service.TenderCopy) (service.Tender, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.cloneTender.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts TenderCloner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		tender, err := ts.CloneTender(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, service.TenderCopy{
+			Name:               req.Name,
+			SubmissionDeadline: req.SubmissionDeadline,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, new_tender.ResponseOf(tender))
+
+	}
+}
@@ -0,0 +1,64 @@
+package deleteinvitation
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID     uuid.UUID
+	InvitationID uuid.UUID
+	UserName     string
+}
+
+type Response struct {
+	ID       uuid.UUID `json:"id"`
+	TenderID uuid.UUID `json:"tenderId"`
+}
+
+type InvitationDeleter interface {
+	DeleteInvitation(ctx context.Context, actor service.Actor, tenderID uuid.UUID, invitationID uuid.UUID) error
+}
+
+func New(ts InvitationDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		invitationStr := chi.URLParam(r, "invitationId")
+		invitationID, err := uuid.Parse(invitationStr)
+
+		if err != nil || invitationStr == "" {
+			response.Render(w, r, response.InvalidID("invitationId"))
+			return
+		}
+		req.InvitationID = invitationID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		err = ts.DeleteInvitation(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, req.InvitationID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, Response{ID: req.InvitationID, TenderID: req.TenderID})
+
+	}
+}
@@ -0,0 +1,53 @@
+package deletetemplate
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TemplateID uuid.UUID
+	UserName   string
+}
+
+type Response struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type TemplateDeleter interface {
+	DeleteTemplate(ctx context.Context, actor service.Actor, templateID uuid.UUID) error
+}
+
+func New(ts TemplateDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		templateStr := chi.URLParam(r, "templateId")
+		templateID, err := uuid.Parse(templateStr)
+
+		if err != nil || templateStr == "" {
+			response.Render(w, r, response.InvalidID("templateId"))
+			return
+		}
+		req.TemplateID = templateID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		err = ts.DeleteTemplate(r.Context(), service.Actor{Username: req.UserName}, req.TemplateID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, Response{ID: req.TemplateID})
+
+	}
+}
@@ -0,0 +1,61 @@
+package deletetender
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID uuid.UUID
+	UserName string
+}
+
+type Response struct {
+	ID        uuid.UUID `json:"id"`
+	DeletedAt string    `json:"deletedAt"`
+	// Bids is the number of bids deleted along with the tender
+	Bids int64 `json:"bids"`
+}
+
+type TenderDeleter interface {
+	DeleteTender(ctx context.Context, actor service.Actor, tenderID uuid.UUID) (service.Deletion, error)
+}
+
+func New(ts TenderDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		deletion, err := ts.DeleteTender(r.Context(), service.Actor{Username: req.UserName}, req.TenderID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, Response{
+			ID:        deletion.ID,
+			DeletedAt: time_converter.Time(deletion.DeletedAt),
+			Bids:      deletion.Bids,
+		})
+
+	}
+}
@@ -0,0 +1,99 @@
+package getauction
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID uuid.UUID
+	UserName string
+}
+
+type Rank struct {
+	Rank int `json:"rank"`
+	// BidID is only told to the tender organization and the bid's side
+	BidID     *uuid.UUID `json:"bidId,omitempty"`
+	Price     float64    `json:"price"`
+	Offers    int        `json:"offers"`
+	OfferedAt string     `json:"offeredAt"`
+	Own       bool       `json:"own,omitempty"`
+}
+
+type Response struct {
+	TenderID     uuid.UUID `json:"tenderId"`
+	StartPrice   float64   `json:"startPrice"`
+	MinDecrement float64   `json:"minDecrement"`
+	Duration     string    `json:"duration"`
+	Extension    string    `json:"extension"`
+	StartsAt     string    `json:"startsAt,omitempty"`
+	EndsAt       string    `json:"endsAt,omitempty"`
+	Status       string    `json:"status"`
+	Ranking      []Rank    `json:"ranking,omitempty"`
+}
+
+type AuctionGetter interface {
+	Auction(ctx context.Context, actor service.Actor, tenderID uuid.UUID) (service.Auction, error)
+}
+
+// ResponseOf is the auction as the auction handlers answer with it.
+func ResponseOf(el service.Auction) Response {
+	res := Response{
+		TenderID:     el.TenderID,
+		StartPrice:   el.StartPrice,
+		MinDecrement: el.MinDecrement,
+		Duration:     el.Duration.String(),
+		Extension:    el.Extension.String(),
+		Status:       string(el.Status),
+	}
+	if !el.StartsAt.IsZero() {
+		res.StartsAt = time_converter.Time(el.StartsAt)
+		res.EndsAt = time_converter.Time(el.EndsAt)
+	}
+	for _, rank := range el.Ranking {
+		res.Ranking = append(res.Ranking, Rank{
+			Rank:      rank.Rank,
+			BidID:     rank.BidID,
+			Price:     rank.Price,
+			Offers:    rank.Offers,
+			OfferedAt: time_converter.Time(rank.OfferedAt),
+			Own:       rank.Own,
+		})
+	}
+	return res
+}
+
+func New(ts AuctionGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		auction, err := ts.Auction(r.Context(), service.Actor{Username: req.UserName}, req.TenderID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, ResponseOf(auction))
+
+	}
+}
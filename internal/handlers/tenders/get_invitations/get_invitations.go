@@ -0,0 +1,76 @@
+package getinvitations
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID uuid.UUID
+	UserName string
+}
+
+type Response struct {
+	ID               uuid.UUID  `json:"id"`
+	TenderID         uuid.UUID  `json:"tenderId"`
+	OrganizationID   *uuid.UUID `json:"organizationId"`
+	EmployeeUsername string     `json:"employeeUsername,omitempty"`
+	CreatedAt        string     `json:"createdAt"`
+}
+
+type ResponseList struct {
+	Response []Response
+}
+
+type InvitationsGetter interface {
+	Invitations(ctx context.Context, actor service.Actor, tenderID uuid.UUID) ([]service.Invitation, error)
+}
+
+func responseOf(el service.Invitation) Response {
+	return Response{
+		ID:               el.ID,
+		TenderID:         el.TenderID,
+		OrganizationID:   el.OrganizationID,
+		EmployeeUsername: el.EmployeeUsername,
+		CreatedAt:        time_converter.Time(el.CreatedAt),
+	}
+}
+
+func New(ts InvitationsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		invitations, err := ts.Invitations(r.Context(), service.Actor{Username: req.UserName}, req.TenderID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(invitations))
+		for _, el := range invitations {
+			res = append(res, responseOf(el))
+		}
+		render.JSON(w, r, res)
+
+	}
+}
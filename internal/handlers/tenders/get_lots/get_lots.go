@@ -0,0 +1,79 @@
+package getlots
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID uuid.UUID
+	UserName string
+}
+
+type Response struct {
+	ID          uuid.UUID `json:"id"`
+	TenderID    uuid.UUID `json:"tenderId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Quantity    int       `json:"quantity"`
+	Winners     int       `json:"winners"`
+	Status      string    `json:"status"`
+	CreatedAt   string    `json:"createdAt"`
+}
+
+type LotsGetter interface {
+	Lots(ctx context.Context, actor service.Actor, tenderID uuid.UUID) ([]service.Lot, error)
+}
+
+// ResponseOf is the lot as the lot handlers answer with it.
+func ResponseOf(el service.Lot) Response {
+	return Response{
+		ID:          el.ID,
+		TenderID:    el.TenderID,
+		Name:        el.Name,
+		Description: el.Description,
+		Quantity:    el.Quantity,
+		Winners:     el.Winners,
+		Status:      string(el.Status),
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+	}
+}
+
+func New(ts LotsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		lots, err := ts.Lots(r.Context(), service.Actor{Username: req.UserName}, req.TenderID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(lots))
+		for _, el := range lots {
+			res = append(res, ResponseOf(el))
+		}
+		render.JSON(w, r, res)
+
+	}
+}
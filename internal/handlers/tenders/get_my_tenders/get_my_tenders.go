@@ -1,18 +1,21 @@
 package getmytenders
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
+	"tender_service/internal/lib/export"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	Limit    uint `validate:"gte=1"`
-	OffSet   uint `validate:"gte=0"`
+	Limit    uint
+	OffSet   uint
 	UserName string
 }
 
@@ -31,7 +34,33 @@ type ResponseList struct {
 }
 
 type MyTendersGetter interface {
-	GetMyTenders(req Request) (ResponseList, error)
+	MyTenders(ctx context.Context, userName string, page service.Page, fn func(el service.Tender) error) error
+}
+
+func responseOf(el service.Tender) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		ServiceType: string(el.ServiceType),
+		Status:      string(el.Status),
+	}
+}
+
+var columns = []string{"id", "version", "createdAt", "name", "description", "serviceType", "status"}
+
+func row(el Response) []string {
+	return []string{
+		el.ID.String(),
+		strconv.FormatUint(uint64(el.Version), 10),
+		el.CreatedAt,
+		el.Name,
+		el.Description,
+		el.ServiceType,
+		el.Status,
+	}
 }
 
 const (
@@ -44,10 +73,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		limit := r.URL.Query().Get("limit")
 		if value, err := strconv.Atoi(limit); err != nil {
-			return err
+			return response.Invalid("invalid limit")
 		} else {
 			if value < 0 {
-				return errors.New("limit must be not negative")
+				return response.Invalid("limit must be not negative")
 			}
 			req.Limit = uint(value)
 		}
@@ -59,10 +88,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		offset := r.URL.Query().Get("offset")
 		if value, err := strconv.Atoi(offset); err != nil {
-			return err
+			return response.Invalid("invalid offset")
 		} else {
 			if value < 0 {
-				return errors.New("offset must be not negative")
+				return response.Invalid("offset must be not negative")
 			}
 			req.OffSet = uint(value)
 		}
@@ -75,7 +104,7 @@ func validateBadrequest(req *Request, r *http.Request) error {
 		req.UserName = r.URL.Query().Get("username")
 
 	} else {
-		return errors.New("username must be not empty")
+		return response.Invalid("username must be not empty")
 	}
 
 	return nil
@@ -88,21 +117,45 @@ func New(ts MyTendersGetter) http.HandlerFunc {
 
 		err := validateBadrequest(&req, r)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
+			return
+		}
+		format, err := export.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
 			return
 		}
-		res, err := ts.GetMyTenders(req)
+
+		var res ResponseList
+		var ew *export.Writer
+		page := service.Page{Limit: req.Limit, Offset: req.OffSet}
+		if format == export.JSON {
+			err = ts.MyTenders(r.Context(), req.UserName, page, func(el service.Tender) error {
+				res.Response = append(res.Response, responseOf(el))
+				return nil
+			})
+		} else {
+			ew = export.NewWriter(w, format, "tenders", columns...)
+			err = ts.MyTenders(r.Context(), req.UserName, page, func(el service.Tender) error {
+				return ew.Write(row(responseOf(el))...)
+			})
+			if err == nil {
+				err = ew.Close()
+			}
+		}
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
+			// The table is already partly sent, there is no status left
+			// to report the error with.
+			if ew.Started() {
 				return
 			}
 
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
+			return
+		}
+
+		if ew != nil {
 			return
 		}
 
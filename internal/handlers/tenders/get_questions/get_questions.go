@@ -0,0 +1,88 @@
+package getquestions
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID uuid.UUID
+	UserName string
+}
+
+type Response struct {
+	ID             uuid.UUID  `json:"id"`
+	TenderID       uuid.UUID  `json:"tenderId"`
+	Question       string     `json:"question"`
+	Author         string     `json:"authorUsername,omitempty"`
+	OrganizationID *uuid.UUID `json:"organizationId,omitempty"`
+	Answer         string     `json:"answer,omitempty"`
+	Public         bool       `json:"public"`
+	AnsweredBy     string     `json:"answeredBy,omitempty"`
+	AnsweredAt     string     `json:"answeredAt,omitempty"`
+	TenderVersion  *uint      `json:"tenderVersion,omitempty"`
+	CreatedAt      string     `json:"createdAt"`
+}
+
+type QuestionsGetter interface {
+	Questions(ctx context.Context, actor service.Actor, tenderID uuid.UUID) ([]service.Question, error)
+}
+
+// ResponseOf is the question as the question handlers answer with it.
+func ResponseOf(el service.Question) Response {
+	res := Response{
+		ID:             el.ID,
+		TenderID:       el.TenderID,
+		Question:       el.Question,
+		Author:         el.Author,
+		OrganizationID: el.OrganizationID,
+		Answer:         el.Answer,
+		Public:         el.Public,
+		AnsweredBy:     el.AnsweredBy,
+		TenderVersion:  el.TenderVersion,
+		CreatedAt:      time_converter.Time(el.CreatedAt),
+	}
+	if el.AnsweredAt != nil {
+		res.AnsweredAt = time_converter.Time(*el.AnsweredAt)
+	}
+	return res
+}
+
+func New(ts QuestionsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		questions, err := ts.Questions(r.Context(), service.Actor{Username: req.UserName}, req.TenderID)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(questions))
+		for _, el := range questions {
+			res = append(res, ResponseOf(el))
+		}
+		render.JSON(w, r, res)
+
+	}
+}
@@ -0,0 +1,85 @@
+package gettemplates
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	UserName string
+	// OrganizationID is the organization whose templates are listed
+	OrganizationID uuid.UUID
+}
+
+type Response struct {
+	ID             uuid.UUID   `json:"id"`
+	OrganizationID uuid.UUID   `json:"organizationId"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+	ServiceType    string      `json:"serviceType"`
+	Categories     []uuid.UUID `json:"categories"`
+	Visibility     string      `json:"visibility"`
+	SealedBids     bool        `json:"sealedBids"`
+	CreatedAt      string      `json:"createdAt"`
+}
+
+type TemplatesGetter interface {
+	Templates(ctx context.Context, actor service.Actor) ([]service.Template, error)
+}
+
+// ResponseOf is the template as the template handlers answer with it.
+func ResponseOf(el service.Template) Response {
+	categories := el.Categories
+	if categories == nil {
+		categories = []uuid.UUID{}
+	}
+
+	return Response{
+		ID:             el.ID,
+		OrganizationID: el.OrganizationID,
+		Name:           el.Name,
+		Description:    el.Description,
+		ServiceType:    string(el.ServiceType),
+		Categories:     categories,
+		Visibility:     string(el.Visibility),
+		SealedBids:     el.SealedBids,
+		CreatedAt:      time_converter.Time(el.CreatedAt),
+	}
+}
+
+func New(ts TemplatesGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		req.UserName = r.URL.Query().Get("username")
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+		req.OrganizationID = orgID
+
+		templates, err := ts.Templates(r.Context(), service.Actor{Username: req.UserName, OrganizationID: req.OrganizationID})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		res := make([]Response, 0, len(templates))
+		for _, el := range templates {
+			res = append(res, ResponseOf(el))
+		}
+		render.JSON(w, r, res)
+
+	}
+}
@@ -1,9 +1,11 @@
 package gettenderstatus
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -11,7 +13,7 @@ import (
 )
 
 type Request struct {
-	TenderID uuid.UUID `validate:"required,uuid"`
+	TenderID uuid.UUID
 	UserName string
 }
 
@@ -20,7 +22,7 @@ type Response struct {
 }
 
 type TenderStatusGetter interface {
-	Status(req Request) (Response, error)
+	TenderStatus(ctx context.Context, actor service.Actor, tenderID uuid.UUID) (models.TenderStatus, error)
 }
 
 func New(ts TenderStatusGetter) http.HandlerFunc {
@@ -31,42 +33,22 @@ func New(ts TenderStatusGetter) http.HandlerFunc {
 		tenderID, err := uuid.Parse(tenderStr)
 
 		if err != nil || tenderStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("tenderId"))
 			return
 		}
 		req.TenderID = tenderID
 
 		req.UserName = r.URL.Query().Get("username")
 
-		res, err := ts.Status(req)
+		status, err := ts.TenderStatus(r.Context(), service.Actor{Username: req.UserName}, req.TenderID)
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusBadRequest)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, Response{Status: string(status)})
 
 	}
 }
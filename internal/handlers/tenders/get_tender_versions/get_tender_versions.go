@@ -0,0 +1,176 @@
+package gettenderversions
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"tender_service/internal/lib/export"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	Limit    uint
+	OffSet   uint
+	TenderID uuid.UUID
+	UserName string
+}
+
+type Response struct {
+	Version     uint   `json:"version"`
+	CreatedAt   string `json:"createdAt"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ServiceType string `json:"serviceType"`
+	Status      string `json:"status"`
+}
+
+type ResponseList struct {
+	Response []Response
+}
+
+type TenderVersionsGetter interface {
+	TenderVersions(ctx context.Context, actor service.Actor, tenderID uuid.UUID, page service.Page, fn func(el service.TenderVersion) error) error
+}
+
+func responseOf(el service.TenderVersion) Response {
+	return Response{
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		ServiceType: string(el.ServiceType),
+		Status:      string(el.Status),
+	}
+}
+
+var columns = []string{"version", "createdAt", "name", "description", "serviceType", "status"}
+
+func row(el Response) []string {
+	return []string{
+		strconv.FormatUint(uint64(el.Version), 10),
+		el.CreatedAt,
+		el.Name,
+		el.Description,
+		el.ServiceType,
+		el.Status,
+	}
+}
+
+const (
+	limitDefault  = 5
+	offsetDefault = 0
+)
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	has := r.URL.Query().Has("limit")
+	if has {
+		limit := r.URL.Query().Get("limit")
+		if value, err := strconv.Atoi(limit); err != nil {
+			return response.Invalid("invalid limit")
+		} else {
+			if value < 0 {
+				return response.Invalid("limit must be not negative")
+			}
+			req.Limit = uint(value)
+		}
+	} else {
+		req.Limit = limitDefault
+	}
+
+	has = r.URL.Query().Has("offset")
+	if has {
+		offset := r.URL.Query().Get("offset")
+		if value, err := strconv.Atoi(offset); err != nil {
+			return response.Invalid("invalid offset")
+		} else {
+			if value < 0 {
+				return response.Invalid("offset must be not negative")
+			}
+			req.OffSet = uint(value)
+		}
+	} else {
+		req.OffSet = offsetDefault
+	}
+
+	req.UserName = r.URL.Query().Get("username")
+
+	if req.UserName == "" {
+		return response.Invalid("username must be not empty")
+	}
+
+	return nil
+}
+
+func New(ts TenderVersionsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		err = validateBadrequest(&req, r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		format, err := export.FromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		var res ResponseList
+		var ew *export.Writer
+		actor := service.Actor{Username: req.UserName}
+		page := service.Page{Limit: req.Limit, Offset: req.OffSet}
+		if format == export.JSON {
+			err = ts.TenderVersions(r.Context(), actor, req.TenderID, page, func(el service.TenderVersion) error {
+				res.Response = append(res.Response, responseOf(el))
+				return nil
+			})
+		} else {
+			ew = export.NewWriter(w, format, "tender_versions", columns...)
+			err = ts.TenderVersions(r.Context(), actor, req.TenderID, page, func(el service.TenderVersion) error {
+				return ew.Write(row(responseOf(el))...)
+			})
+			if err == nil {
+				err = ew.Close()
+			}
+		}
+
+		if err != nil {
+			// The table is already partly sent, there is no status left
+			// to report the error with.
+			if ew.Started() {
+				return
+			}
+
+			response.Render(w, r, err)
+			return
+		}
+
+		if ew != nil {
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if res.Response == nil {
+			res.Response = make([]Response, 0)
+		}
+		render.JSON(w, r, res.Response)
+
+	}
+}
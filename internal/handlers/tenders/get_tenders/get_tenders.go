@@ -1,20 +1,23 @@
 package gettenders
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
 	"tender_service/internal/lib/response"
-	models2 "tender_service/internal/storage/models"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	Limit      uint `validate:"gte=0"`
-	OffSet     uint `validate:"gte=0"`
+	Limit      uint
+	OffSet     uint
 	SeviceType []string
+	// UserName is optional, anonymous requests see public tenders only
+	UserName string
 }
 
 type Response struct {
@@ -32,7 +35,19 @@ type ResponseList struct {
 }
 
 type TendersGetter interface {
-	GetTenders(req Request) (ResponseList, error)
+	Tenders(ctx context.Context, query service.TendersQuery, fn func(el service.Tender) error) error
+}
+
+func responseOf(el service.Tender) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		ServiceType: string(el.ServiceType),
+		Status:      string(el.Status),
+	}
 }
 
 const (
@@ -45,10 +60,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		limit := r.URL.Query().Get("limit")
 		if value, err := strconv.Atoi(limit); err != nil {
-			return err
+			return response.Invalid("invalid limit")
 		} else {
 			if value < 0 {
-				return errors.New("limit must be not negative")
+				return response.Invalid("limit must be not negative")
 			}
 			req.Limit = uint(value)
 		}
@@ -60,10 +75,10 @@ func validateBadrequest(req *Request, r *http.Request) error {
 	if has {
 		offset := r.URL.Query().Get("offset")
 		if value, err := strconv.Atoi(offset); err != nil {
-			return err
+			return response.Invalid("invalid offset")
 		} else {
 			if value < 0 {
-				return errors.New("offset must be not negative")
+				return response.Invalid("offset must be not negative")
 			}
 			req.OffSet = uint(value)
 		}
@@ -71,16 +86,9 @@ func validateBadrequest(req *Request, r *http.Request) error {
 		req.OffSet = offsetDefault
 	}
 
-	serviceTypes := r.URL.Query()["service_type"]
+	req.SeviceType = r.URL.Query()["service_type"]
 
-	for _, el := range serviceTypes {
-		e := models2.TenderServiceType(el)
-		if !models2.ValidateTenderServiceType(e) {
-			return errors.New("service type not correct")
-		}
-	}
-
-	req.SeviceType = serviceTypes
+	req.UserName = r.URL.Query().Get("username")
 
 	return nil
 
@@ -92,15 +100,22 @@ func New(ts TendersGetter) http.HandlerFunc {
 
 		err := validateBadrequest(&req, r)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
-		res, err := ts.GetTenders(req)
+		var res ResponseList
+		query := service.TendersQuery{
+			Username:     req.UserName,
+			ServiceTypes: req.SeviceType,
+			Page:         service.Page{Limit: req.Limit, Offset: req.OffSet},
+		}
+		err = ts.Tenders(r.Context(), query, func(el service.Tender) error {
+			res.Response = append(res.Response, responseOf(el))
+			return nil
+		})
 
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
@@ -0,0 +1,323 @@
+package importtenders
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender_service/internal/handlers/tenders/new_tender"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+// Format is the encoding of the imported rows.
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+// Row is one imported tender. Error is set when the row could not be
+// decoded.
+type Row struct {
+	Tender new_tender.Request
+	Error  error
+}
+
+type Request struct {
+	Rows []Row
+	// DryRun checks every row, including the creators' rights, and rolls
+	// the import back
+	DryRun bool
+}
+
+// RowError is the error a row would have been answered with had its tender
+// been created alone.
+type RowError struct {
+	Row   int               `json:"row"`
+	Error response.Response `json:"error"`
+}
+
+type Response struct {
+	DryRun   bool                  `json:"dryRun"`
+	Rows     int                   `json:"rows"`
+	Imported int                   `json:"imported"`
+	Errors   []RowError            `json:"errors"`
+	Tenders  []new_tender.Response `json:"tenders"`
+}
+
+type TendersImporter interface {
+	ImportTenders(ctx context.Context, in service.Import) (service.ImportResult, error)
+}
+
+// Input is the use case input of a request.
+func Input(req Request) service.Import {
+	in := service.Import{DryRun: req.DryRun}
+	for _, el := range req.Rows {
+		in.Rows = append(in.Rows, service.ImportRow{Tender: new_tender.Input(el.Tender), Error: el.Error})
+	}
+	return in
+}
+
+// ResponseOf describes the result with the errors of its rows told in lang.
+func ResponseOf(lang string, res service.ImportResult) Response {
+	out := Response{
+		DryRun:   res.DryRun,
+		Rows:     res.Rows,
+		Imported: res.Imported,
+		Errors:   make([]RowError, 0, len(res.Errors)),
+		Tenders:  make([]new_tender.Response, 0, len(res.Tenders)),
+	}
+	for _, el := range res.Errors {
+		out.Errors = append(out.Errors, RowError{Row: el.Row, Error: response.ErrorIn(lang, el.Error)})
+	}
+	for _, el := range res.Tenders {
+		out.Tenders = append(out.Tenders, new_tender.ResponseOf(el))
+	}
+	return out
+}
+
+// maxBodySize bounds the imported file.
+const maxBodySize = 32 << 20
+
+// csvColumns are the columns a CSV file may have, named like the fields of
+// the new tender request. Categories are separated by semicolons.
+var csvColumns = map[string]func(req *new_tender.Request, value string) error{
+	"name": func(req *new_tender.Request, value string) error {
+		req.Name = value
+		return nil
+	},
+	"description": func(req *new_tender.Request, value string) error {
+		req.Description = value
+		return nil
+	},
+	"serviceType": func(req *new_tender.Request, value string) error {
+		req.ServiceType = value
+		return nil
+	},
+	"organizationId": func(req *new_tender.Request, value string) error {
+		if value == "" {
+			return nil
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return response.Invalid("invalid organizationId")
+		}
+		req.OrganizationId = id
+		return nil
+	},
+	"creatorUsername": func(req *new_tender.Request, value string) error {
+		req.CreatorUsername = value
+		return nil
+	},
+	"categories": func(req *new_tender.Request, value string) error {
+		for _, el := range strings.Split(value, ";") {
+			el = strings.TrimSpace(el)
+			if el == "" {
+				continue
+			}
+			id, err := uuid.Parse(el)
+			if err != nil {
+				return response.Invalid("invalid categories")
+			}
+			req.Categories = append(req.Categories, id)
+		}
+		return nil
+	},
+	"visibility": func(req *new_tender.Request, value string) error {
+		req.Visibility = value
+		return nil
+	},
+	"sealedBids": func(req *new_tender.Request, value string) error {
+		if value == "" {
+			return nil
+		}
+		sealed, err := strconv.ParseBool(value)
+		if err != nil {
+			return response.Invalid("invalid sealedBids")
+		}
+		req.SealedBids = sealed
+		return nil
+	},
+	"submissionDeadline": func(req *new_tender.Request, value string) error {
+		if value == "" {
+			return nil
+		}
+		deadline, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return response.Invalid("invalid submissionDeadline")
+		}
+		req.SubmissionDeadline = &deadline
+		return nil
+	},
+}
+
+// FormatFromRequest returns the format given by the format query parameter
+// or, when it is not set, by the Content-Type header.
+func FormatFromRequest(r *http.Request) (Format, error) {
+	value := r.URL.Query().Get("format")
+	if value == "" {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return "", response.Invalid("unknown import format")
+		}
+		switch mediaType {
+		case "text/csv":
+			value = string(CSV)
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			value = string(NDJSON)
+		}
+	}
+
+	switch Format(strings.ToLower(value)) {
+	case CSV:
+		return CSV, nil
+	case NDJSON:
+		return NDJSON, nil
+	}
+	return "", response.Invalid("unknown import format")
+}
+
+// Parse reads the rows of body, they are checked by the service the same
+// way a single new tender is. A row that can't be decoded keeps the problem
+// in its Error; an error is returned only when the file as a whole can't
+// be read.
+func Parse(body io.Reader, format Format) ([]Row, error) {
+	if format == CSV {
+		return parseCSV(body)
+	}
+	return parseNDJSON(body)
+}
+
+func parseCSV(body io.Reader) ([]Row, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, response.ErrEmptyBody
+	}
+	if err != nil {
+		return nil, response.Invalid("invalid csv: " + err.Error())
+	}
+
+	setters := make([]func(req *new_tender.Request, value string) error, len(header))
+	for i, el := range header {
+		setter, ok := csvColumns[strings.TrimSpace(el)]
+		if !ok {
+			return nil, response.Invalid(fmt.Sprintf("unknown column %q", el))
+		}
+		setters[i] = setter
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, response.Invalid("invalid csv: " + err.Error())
+		}
+
+		var row Row
+		if len(record) != len(header) {
+			row.Error = response.Invalid(fmt.Sprintf("expected %d fields, got %d", len(header), len(record)))
+			rows = append(rows, row)
+			continue
+		}
+		for i, value := range record {
+			if err := setters[i](&row.Tender, strings.TrimSpace(value)); err != nil {
+				row.Error = err
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseNDJSON(body io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxBodySize)
+
+	var rows []Row
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var row Row
+		if err := json.Unmarshal([]byte(line), &row.Tender); err != nil {
+			row.Error = response.ErrInvalidRequest
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, response.Invalid("invalid ndjson: " + err.Error())
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrEmptyBody
+	}
+	return rows, nil
+}
+
+func New(ts TendersImporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		format, err := FormatFromRequest(r)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		if r.URL.Query().Has("dryRun") {
+			req.DryRun, err = strconv.ParseBool(r.URL.Query().Get("dryRun"))
+			if err != nil {
+				response.Render(w, r, response.Invalid("invalid dryRun"))
+				return
+			}
+		}
+
+		req.Rows, err = Parse(http.MaxBytesReader(w, r.Body, maxBodySize), format)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		result, err := ts.ImportTenders(r.Context(), Input(req))
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		res := ResponseOf(response.Language(r), result)
+
+		// Nothing is imported when any row fails.
+		if len(res.Errors) > 0 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			render.JSON(w, r, res)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, res)
+
+	}
+}
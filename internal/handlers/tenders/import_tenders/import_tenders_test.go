@@ -0,0 +1,72 @@
+package importtenders
+
+import (
+	"strings"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"testing"
+)
+
+type tender struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestResponseOf(t *testing.T) {
+	fieldErr := response.Validate(tender{})
+	if fieldErr == nil {
+		t.Fatal("no field errors to report")
+	}
+
+	rows, err := Parse(strings.NewReader("name,sealedBids\ntender,maybe\ntender\n"), CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := service.ImportResult{Rows: 3}
+	for i, el := range rows {
+		res.Errors = append(res.Errors, service.ImportError{Row: i + 1, Error: el.Error})
+	}
+	res.Errors = append(res.Errors,
+		service.ImportError{Row: 3, Error: fieldErr},
+		service.ImportError{Row: 4, Error: response.ErrNoRights},
+	)
+
+	tests := []struct {
+		row     int
+		code    response.Code
+		pointer string
+	}{
+		{1, "incorrect_value", ""},
+		{2, "incorrect_value", ""},
+		{3, response.CodeValidation, "/name"},
+		{4, "no_rights", ""},
+	}
+
+	for _, lang := range []string{"en", "ru"} {
+		out := ResponseOf(lang, res)
+		if len(out.Errors) != len(tests) {
+			t.Fatalf("%s: %d errors, want %d", lang, len(out.Errors), len(tests))
+		}
+
+		for i, tt := range tests {
+			got := out.Errors[i]
+			if got.Row != tt.row || got.Error.Code != tt.code {
+				t.Errorf("%s: error %d is row %d with code %q, want row %d with %q", lang, i, got.Row, got.Error.Code, tt.row, tt.code)
+			}
+			if got.Error.Reason == "" {
+				t.Errorf("%s: error %d has no reason", lang, i)
+			}
+			if tt.pointer == "" {
+				continue
+			}
+			if len(got.Error.Errors) != 1 || got.Error.Errors[0].Pointer != tt.pointer || got.Error.Errors[0].Reason == "" {
+				t.Errorf("%s: error %d field errors %+v, want one at %s", lang, i, got.Error.Errors, tt.pointer)
+			}
+		}
+	}
+
+	en, ru := ResponseOf("en", res), ResponseOf("ru", res)
+	if en.Errors[2].Error.Errors[0].Reason == ru.Errors[2].Error.Errors[0].Reason {
+		t.Error("field error is not localized")
+	}
+}
@@ -0,0 +1,94 @@
+package newinvitation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID         uuid.UUID
+	UserName         string
+	OrganizationID   uuid.UUID `json:"organizationId"`
+	EmployeeUsername string    `json:"employeeUsername"`
+}
+
+type Response struct {
+	ID               uuid.UUID  `json:"id"`
+	TenderID         uuid.UUID  `json:"tenderId"`
+	OrganizationID   *uuid.UUID `json:"organizationId"`
+	EmployeeUsername string     `json:"employeeUsername,omitempty"`
+	CreatedAt        string     `json:"createdAt"`
+}
+
+type InvitationSaver interface {
+	Invite(ctx context.Context, actor service.Actor, tenderID uuid.UUID, orgID uuid.UUID, employeeUsername string) (service.Invitation, error)
+}
+
+func responseOf(el service.Invitation) Response {
+	return Response{
+		ID:               el.ID,
+		TenderID:         el.TenderID,
+		OrganizationID:   el.OrganizationID,
+		EmployeeUsername: el.EmployeeUsername,
+		CreatedAt:        time_converter.Time(el.CreatedAt),
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.newInvitation.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts InvitationSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		actor := service.Actor{Username: req.UserName}
+		invitation, err := ts.Invite(r.Context(), actor, req.TenderID, req.OrganizationID, req.EmployeeUsername)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, responseOf(invitation))
+
+	}
+}
@@ -0,0 +1,83 @@
+package newlot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/tenders/get_lots"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID    uuid.UUID
+	UserName    string
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	// Winners is how many bids may be approved in the lot, one when unset
+	Winners int `json:"winners"`
+}
+
+type LotSaver interface {
+	AddLot(ctx context.Context, actor service.Actor, tenderID uuid.UUID, in service.NewLot) (service.Lot, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.newLot.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts LotSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		lot, err := ts.AddLot(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, service.NewLot{
+			Name:        req.Name,
+			Description: req.Description,
+			Quantity:    req.Quantity,
+			Winners:     req.Winners,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, getlots.ResponseOf(lot))
+
+	}
+}
@@ -0,0 +1,80 @@
+package newtemplate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/tenders/get_templates"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	UserName       string
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	ServiceType    string    `json:"serviceType"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	// Categories are linked to the tenders in addition to their service type
+	Categories []uuid.UUID `json:"categories"`
+	Visibility string      `json:"visibility"`
+	SealedBids bool        `json:"sealedBids"`
+}
+
+type TemplateSaver interface {
+	SaveTemplate(ctx context.Context, in service.NewTemplate) (service.Template, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.newTemplate.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts TemplateSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		template, err := ts.SaveTemplate(r.Context(), service.NewTemplate{
+			Name:            req.Name,
+			Description:     req.Description,
+			ServiceType:     models.TenderServiceType(req.ServiceType),
+			OrganizationID:  req.OrganizationID,
+			CreatorUsername: req.UserName,
+			Categories:      req.Categories,
+			Visibility:      models.TenderVisibility(req.Visibility),
+			SealedBids:      req.SealedBids,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, gettemplates.ResponseOf(template))
+
+	}
+}
@@ -1,64 +1,98 @@
 package new_tender
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 	models2 "tender_service/internal/storage/models"
+	"time"
 
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	Name            string    `json:"name" validate:"required,max=100"`
-	Description     string    `json:"description" validate:"required,max=500"`
-	ServiceType     string    `json:"serviceType" validate:"required"`
-	OrganizationId  uuid.UUID `json:"organizationId" validate:"required,max=100,uuid"`
-	CreatorUsername string    `json:"creatorUsername" validate:"required"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	ServiceType     string    `json:"serviceType"`
+	OrganizationId  uuid.UUID `json:"organizationId"`
+	CreatorUsername string    `json:"creatorUsername"`
+	// Categories are linked to the tender in addition to its service type
+	Categories []uuid.UUID `json:"categories"`
+	Visibility string      `json:"visibility"`
+	// SealedBids hides bid contents from the tender organization until
+	// SubmissionDeadline, after which no bids are accepted
+	SealedBids         bool       `json:"sealedBids"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline"`
 }
 
 type Response struct {
-	ID          uuid.UUID `json:"id"`
-	Version     uint      `json:"version"`
-	CreatedAt   string    `json:"createdAt"`
-	Name        string    `json:"name" validate:"required,max=100"`
-	Description string    `json:"description" validate:"required,max=500"`
-	ServiceType string    `json:"serviceType"`
-	Status      string    `json:"status" validate:"required"`
+	ID                 uuid.UUID   `json:"id"`
+	Version            uint        `json:"version"`
+	CreatedAt          string      `json:"createdAt"`
+	Name               string      `json:"name" validate:"required,max=100"`
+	Description        string      `json:"description" validate:"required,max=500"`
+	ServiceType        string      `json:"serviceType"`
+	Status             string      `json:"status" validate:"required"`
+	Categories         []uuid.UUID `json:"categories"`
+	Visibility         string      `json:"visibility"`
+	SealedBids         bool        `json:"sealedBids"`
+	SubmissionDeadline string      `json:"submissionDeadline,omitempty"`
 }
 
 type TenderSaver interface {
-	SaveTender(req Request) (Response, error)
+	CreateTender(ctx context.Context, in service.NewTender) (service.Tender, error)
 }
 
-func validateBadrequest(req *Request, r *http.Request) string {
+// Input is the use case input of a request.
+func Input(req Request) service.NewTender {
+	return service.NewTender{
+		Name:               req.Name,
+		Description:        req.Description,
+		ServiceType:        models2.TenderServiceType(req.ServiceType),
+		OrganizationID:     req.OrganizationId,
+		CreatorUsername:    req.CreatorUsername,
+		Categories:         req.Categories,
+		Visibility:         models2.TenderVisibility(req.Visibility),
+		SealedBids:         req.SealedBids,
+		SubmissionDeadline: req.SubmissionDeadline,
+	}
+}
+
+func ResponseOf(el service.Tender) Response {
+	return Response{
+		ID:                 el.ID,
+		Version:            el.Version,
+		CreatedAt:          time_converter.Time(el.CreatedAt),
+		Name:               el.Name,
+		Description:        el.Description,
+		ServiceType:        string(el.ServiceType),
+		Status:             string(el.Status),
+		Categories:         el.Categories,
+		Visibility:         string(el.Visibility),
+		SealedBids:         el.SealedBids,
+		SubmissionDeadline: time_converter.OptionalTime(el.SubmissionDeadline),
+	}
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
 	const op = "handlers.Tender.validateBadrequest"
 	err := render.DecodeJSON(r.Body, &req)
 	if errors.Is(err, io.EOF) {
-		return "request body is empty"
+		return response.ErrEmptyBody
 	}
 
 	if err != nil {
 		slog.Info(err.Error(), slog.String("op", op))
-		return "invalid request"
+		return response.ErrInvalidRequest
 	}
 
-	if !models2.ValidateTenderServiceType(models2.TenderServiceType(req.ServiceType)) {
-		return "incorrect service type"
-	}
-
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errMsgs
-	}
-
-	return ""
+	return nil
 }
 
 func New(ts TenderSaver) http.HandlerFunc {
@@ -66,35 +100,20 @@ func New(ts TenderSaver) http.HandlerFunc {
 
 		var req Request
 
-		if errMsg := validateBadrequest(&req, r); errMsg != "" {
-			w.WriteHeader(http.StatusBadRequest)
-
-			render.JSON(w, r, response.Error(errMsg))
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
 			return
 		}
 
-		res, err := ts.SaveTender(req)
+		tender, err := ts.CreateTender(r.Context(), Input(req))
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, ResponseOf(tender))
 
 	}
 }
@@ -0,0 +1,77 @@
+package tenderfromtemplate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/tenders/new_tender"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+// Request may come without a body, the new tender then keeps the name
+// and has no submission deadline.
+type Request struct {
+	TemplateID         uuid.UUID
+	UserName           string
+	Name               string     `json:"name"`
+	SubmissionDeadline *time.Time `json:"submissionDeadline"`
+}
+
+type TenderIssuer interface {
+	TenderFromTemplate(ctx context.Context, actor service.Actor, templateID uuid.UUID, in service.TenderCopy) (service.Tender, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.tenderFromTemplate.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func New(ts TenderIssuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		templateStr := chi.URLParam(r, "templateId")
+		templateID, err := uuid.Parse(templateStr)
+
+		if err != nil || templateStr == "" {
+			response.Render(w, r, response.InvalidID("templateId"))
+			return
+		}
+		req.TemplateID = templateID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		tender, err := ts.TenderFromTemplate(r.Context(), service.Actor{Username: req.UserName}, req.TemplateID, service.TenderCopy{
+			Name:               req.Name,
+			SubmissionDeadline: req.SubmissionDeadline,
+		})
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, new_tender.ResponseOf(tender))
+
+	}
+}
@@ -1,68 +1,96 @@
 package patchtenderstatus
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 	models2 "tender_service/internal/storage/models"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
 	TenderID    uuid.UUID `json:"id"`
 	UserName    string
-	Name        string `json:"name" validate:"max=100"`
-	Description string `json:"description" validate:"max=500"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 	ServiceType string `json:"serviceType"`
 	Status      string `json:"status"`
+	// Categories replace the extra categories of the tender when set
+	Categories         []uuid.UUID `json:"categories"`
+	Visibility         string      `json:"visibility"`
+	SealedBids         *bool       `json:"sealedBids"`
+	SubmissionDeadline *time.Time  `json:"submissionDeadline"`
 }
 
 type Response struct {
-	ID          uuid.UUID `json:"id"`
-	Version     uint      `json:"version"`
-	CreatedAt   string    `json:"createdAt"`
-	Name        string    `json:"name" validate:"max=100"`
-	Description string    `json:"description" validate:"max=500"`
-	ServiceType string    `json:"serviceType"`
-	Status      string    `json:"status"`
+	ID                 uuid.UUID   `json:"id"`
+	Version            uint        `json:"version"`
+	CreatedAt          string      `json:"createdAt"`
+	Name               string      `json:"name" validate:"max=100"`
+	Description        string      `json:"description" validate:"max=500"`
+	ServiceType        string      `json:"serviceType"`
+	Status             string      `json:"status"`
+	Categories         []uuid.UUID `json:"categories"`
+	Visibility         string      `json:"visibility"`
+	SealedBids         bool        `json:"sealedBids"`
+	SubmissionDeadline string      `json:"submissionDeadline,omitempty"`
 }
 
 type TenderStatusPatcher interface {
-	PatchTender(req Request) (Response, error)
+	EditTender(ctx context.Context, actor service.Actor, patch service.TenderPatch) (service.Tender, error)
 }
 
-func validateBadrequest(req *Request, r *http.Request) string {
-	err := render.DecodeJSON(r.Body, req)
-	if errors.Is(err, io.EOF) {
-		return "request body is empty"
-	}
-	if err != nil {
-		slog.Info(err.Error())
-		return "invalid request"
+// Patch is the use case input of a request.
+func Patch(req Request) service.TenderPatch {
+	return service.TenderPatch{
+		TenderID:           req.TenderID,
+		Name:               req.Name,
+		Description:        req.Description,
+		ServiceType:        models2.TenderServiceType(req.ServiceType),
+		Status:             models2.TenderStatus(req.Status),
+		Categories:         req.Categories,
+		Visibility:         models2.TenderVisibility(req.Visibility),
+		SealedBids:         req.SealedBids,
+		SubmissionDeadline: req.SubmissionDeadline,
 	}
+}
 
-	if !models2.ValidateTenderServiceType(models2.TenderServiceType(req.ServiceType)) && req.ServiceType != "" {
-		return "incorrect service type"
+func responseOf(el service.Tender) Response {
+	return Response{
+		ID:                 el.ID,
+		Version:            el.Version,
+		CreatedAt:          time_converter.Time(el.CreatedAt),
+		Name:               el.Name,
+		Description:        el.Description,
+		ServiceType:        string(el.ServiceType),
+		Status:             string(el.Status),
+		Categories:         el.Categories,
+		Visibility:         string(el.Visibility),
+		SealedBids:         el.SealedBids,
+		SubmissionDeadline: time_converter.OptionalTime(el.SubmissionDeadline),
 	}
+}
 
-	if !models2.ValidateTenderStatus(models2.TenderStatus(req.Status)) && req.Status != "" {
-		return "incorrect tender status"
+func validateBadrequest(req *Request, r *http.Request) error {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
 	}
-
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errMsgs
+	if err != nil {
+		slog.Info(err.Error())
+		return response.ErrInvalidRequest
 	}
 
-	return ""
+	return nil
 }
 
 func New(ts TenderStatusPatcher) http.HandlerFunc {
@@ -73,49 +101,27 @@ func New(ts TenderStatusPatcher) http.HandlerFunc {
 		tenderID, err := uuid.Parse(tenderStr)
 
 		if err != nil || tenderStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("tenderId"))
 			return
 		}
 		req.TenderID = tenderID
 
 		req.UserName = r.URL.Query().Get("username")
 
-		if errMsg := validateBadrequest(&req, r); errMsg != "" {
-			w.WriteHeader(http.StatusBadRequest)
-
-			render.JSON(w, r, response.Error(errMsg))
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
 			return
 		}
 
-		res, err := ts.PatchTender(req)
+		tender, err := ts.EditTender(r.Context(), service.Actor{Username: req.UserName}, Patch(req))
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(tender))
 
 	}
 }
@@ -0,0 +1,108 @@
+package putauction
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/handlers/tenders/get_auction"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID     uuid.UUID
+	UserName     string
+	StartPrice   float64 `json:"startPrice"`
+	MinDecrement float64 `json:"minDecrement"`
+	// Duration and Extension are Go durations such as "30m"
+	Duration  string `json:"duration"`
+	Extension string `json:"extension"`
+}
+
+type AuctionPutter interface {
+	SetAuction(ctx context.Context, actor service.Actor, tenderID uuid.UUID, in service.AuctionSettings) (service.Auction, error)
+}
+
+func validateBadrequest(req *Request, r *http.Request) error {
+	const op = "handlers.putAuction.validateBadrequest"
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		return response.ErrEmptyBody
+	}
+
+	if err != nil {
+		slog.Info(err.Error(), slog.String("op", op))
+		return response.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+// settings parses the durations of the request.
+func settings(req *Request) (service.AuctionSettings, error) {
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return service.AuctionSettings{}, response.Invalid("invalid duration")
+	}
+
+	var extension time.Duration
+	if req.Extension != "" {
+		extension, err = time.ParseDuration(req.Extension)
+		if err != nil {
+			return service.AuctionSettings{}, response.Invalid("invalid extension")
+		}
+	}
+
+	return service.AuctionSettings{
+		StartPrice:   req.StartPrice,
+		MinDecrement: req.MinDecrement,
+		Duration:     duration,
+		Extension:    extension,
+	}, nil
+}
+
+func New(ts AuctionPutter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		in, err := settings(&req)
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		auction, err := ts.SetAuction(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, in)
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, getauction.ResponseOf(auction))
+
+	}
+}
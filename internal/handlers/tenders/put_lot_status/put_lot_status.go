@@ -0,0 +1,64 @@
+package putlotstatus
+
+import (
+	"context"
+	"net/http"
+	"tender_service/internal/handlers/tenders/get_lots"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	models2 "tender_service/internal/storage/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+type Request struct {
+	TenderID uuid.UUID
+	LotID    uuid.UUID
+	UserName string
+	Status   string
+}
+
+type LotStatusPutter interface {
+	SetLotStatus(ctx context.Context, actor service.Actor, tenderID uuid.UUID, lotID uuid.UUID, status models2.LotStatus) (service.Lot, error)
+}
+
+func New(ts LotStatusPutter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		tenderStr := chi.URLParam(r, "tenderId")
+		tenderID, err := uuid.Parse(tenderStr)
+
+		if err != nil || tenderStr == "" {
+			response.Render(w, r, response.InvalidID("tenderId"))
+			return
+		}
+		req.TenderID = tenderID
+
+		lotStr := chi.URLParam(r, "lotId")
+		lotID, err := uuid.Parse(lotStr)
+
+		if err != nil || lotStr == "" {
+			response.Render(w, r, response.InvalidID("lotId"))
+			return
+		}
+		req.LotID = lotID
+
+		req.UserName = r.URL.Query().Get("username")
+
+		req.Status = r.URL.Query().Get("status")
+
+		lot, err := ts.SetLotStatus(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, req.LotID, models2.LotStatus(req.Status))
+
+		if err != nil {
+			response.Render(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, getlots.ResponseOf(lot))
+
+	}
+}
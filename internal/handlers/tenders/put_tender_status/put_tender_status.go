@@ -1,21 +1,22 @@
 package puttenderstatus
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 	models2 "tender_service/internal/storage/models"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
-	TenderID uuid.UUID `validate:"required,uuid"`
-	UserName string    `validate:"required"`
-	Status   string    `validate:"required"`
+	TenderID uuid.UUID
+	UserName string
+	Status   string
 }
 
 type Response struct {
@@ -29,23 +30,19 @@ type Response struct {
 }
 
 type TenderStatusPutter interface {
-	StatusPut(req Request) (Response, error)
+	SetTenderStatus(ctx context.Context, actor service.Actor, tenderID uuid.UUID, status models2.TenderStatus) (service.Tender, error)
 }
 
-func validateBadrequest(req *Request) string {
-
-	if !models2.ValidateTenderStatus(models2.TenderStatus(req.Status)) {
-		return "incorrect tender status"
-	}
-
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errMsgs
+func responseOf(el service.Tender) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		ServiceType: string(el.ServiceType),
+		Status:      string(el.Status),
 	}
-
-	return ""
 }
 
 func New(ts TenderStatusPutter) http.HandlerFunc {
@@ -56,8 +53,7 @@ func New(ts TenderStatusPutter) http.HandlerFunc {
 		tenderID, err := uuid.Parse(tenderStr)
 
 		if err != nil || tenderStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("tenderId"))
 			return
 		}
 		req.TenderID = tenderID
@@ -66,41 +62,15 @@ func New(ts TenderStatusPutter) http.HandlerFunc {
 
 		req.Status = r.URL.Query().Get("status")
 
-		if errMsg := validateBadrequest(&req); errMsg != "" {
-			w.WriteHeader(http.StatusBadRequest)
-
-			render.JSON(w, r, response.Error(errMsg))
-			return
-		}
-
-		res, err := ts.StatusPut(req)
+		tender, err := ts.SetTenderStatus(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, models2.TenderStatus(req.Status))
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(tender))
 
 	}
 }
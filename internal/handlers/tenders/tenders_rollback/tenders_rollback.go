@@ -1,21 +1,22 @@
 package tendersrollback
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
 	"tender_service/internal/lib/response"
+	"tender_service/internal/lib/time_converter"
+	"tender_service/internal/service"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type Request struct {
 	TenderID uuid.UUID `json:"id"`
-	UserName string    `validate:"required"`
-	Version  uint      `validate:"required"`
+	UserName string
+	Version  uint
 }
 
 type Response struct {
@@ -29,27 +30,32 @@ type Response struct {
 }
 
 type TenderRollbacker interface {
-	TenderRollback(req Request) (Response, error)
+	RollbackTender(ctx context.Context, actor service.Actor, tenderID uuid.UUID, version uint) (service.Tender, error)
+}
+
+func responseOf(el service.Tender) Response {
+	return Response{
+		ID:          el.ID,
+		Version:     el.Version,
+		CreatedAt:   time_converter.Time(el.CreatedAt),
+		Name:        el.Name,
+		Description: el.Description,
+		ServiceType: string(el.ServiceType),
+		Status:      string(el.Status),
+	}
 }
 
 func validateBadrequest(req *Request, r *http.Request) error {
 	version := chi.URLParam(r, "version")
 	if value, err := strconv.Atoi(version); err != nil {
-		return err
+		return response.Invalid("invalid version")
 	} else {
 		if value < 0 {
-			return errors.New("version must be not negative")
+			return response.Invalid("version must be not negative")
 		}
 		req.Version = uint(value)
 	}
 
-	if err := validator.New().Struct(req); err != nil {
-		validateErr := err.(validator.ValidationErrors)
-
-		errMsgs := response.ValidationError(validateErr)
-		return errors.New(errMsgs)
-	}
-
 	return nil
 }
 
@@ -61,49 +67,27 @@ func New(ts TenderRollbacker) http.HandlerFunc {
 		tenderID, err := uuid.Parse(tenderStr)
 
 		if err != nil || tenderStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, response.Error("invalid id"))
+			response.Render(w, r, response.InvalidID("tenderId"))
 			return
 		}
 		req.TenderID = tenderID
 
 		req.UserName = r.URL.Query().Get("username")
 
-		if errMsg := validateBadrequest(&req, r); errMsg != nil {
-			w.WriteHeader(http.StatusBadRequest)
-
-			render.JSON(w, r, response.Error(errMsg.Error()))
+		if err := validateBadrequest(&req, r); err != nil {
+			response.Render(w, r, err)
 			return
 		}
 
-		res, err := ts.TenderRollback(req)
+		tender, err := ts.RollbackTender(r.Context(), service.Actor{Username: req.UserName}, req.TenderID, req.Version)
 
 		if err != nil {
-			if errors.Is(err, response.ErrUserNotExists) {
-				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrTenderNotExists) {
-				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			if errors.Is(err, response.ErrNoRights) {
-				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error(err.Error()))
-				return
-			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, response.Error(err.Error()))
+			response.Render(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, res)
+		render.JSON(w, r, responseOf(tender))
 
 	}
 }
@@ -0,0 +1,43 @@
+package actingorg
+
+import (
+	"net/http"
+	"tender_service/internal/lib/response"
+
+	"github.com/google/uuid"
+)
+
+// Header and Param name the organization an employee acts on behalf of.
+const (
+	Header = "X-Organization-Id"
+	Param  = "organizationId"
+)
+
+var ErrInvalid = response.InvalidID(Param)
+
+// FromRequest returns the acting organization of the request, or uuid.Nil
+// when it is not given. The query parameter and the header must agree when
+// both are set.
+func FromRequest(r *http.Request) (uuid.UUID, error) {
+	param := r.URL.Query().Get(Param)
+	header := r.Header.Get(Header)
+
+	if param == "" && header == "" {
+		return uuid.Nil, nil
+	}
+
+	if param != "" && header != "" && param != header {
+		return uuid.Nil, ErrInvalid
+	}
+
+	value := param
+	if value == "" {
+		value = header
+	}
+
+	orgID, err := uuid.Parse(value)
+	if err != nil || orgID == uuid.Nil {
+		return uuid.Nil, ErrInvalid
+	}
+	return orgID, nil
+}
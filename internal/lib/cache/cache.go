@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is implemented by cache backends. Values are opaque bytes so that
+// a shared backend can keep them for every replica; the in-process LRU
+// backend is enough for a single replica, deployments with several
+// replicas need a shared one for the invalidations to reach all of them.
+type Cache interface {
+	// Get returns the value of the key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set keeps the value for ttl, or until it is evicted when ttl is zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	type op struct {
+		// set, get or delete
+		kind  string
+		key   string
+		value string
+		ttl   time.Duration
+		// sleep is waited for before the operation
+		sleep time.Duration
+		// found and want are what get returns
+		found bool
+		want  string
+	}
+
+	tests := []struct {
+		name     string
+		capacity int
+		ops      []op
+	}{
+		{
+			name:     "returns what was set",
+			capacity: 2,
+			ops: []op{
+				{kind: "set", key: "a", value: "1"},
+				{kind: "get", key: "a", found: true, want: "1"},
+				{kind: "get", key: "b"},
+			},
+		},
+		{
+			name:     "replaces the value of a key",
+			capacity: 2,
+			ops: []op{
+				{kind: "set", key: "a", value: "1"},
+				{kind: "set", key: "a", value: "2"},
+				{kind: "get", key: "a", found: true, want: "2"},
+			},
+		},
+		{
+			name:     "evicts the least recently set",
+			capacity: 2,
+			ops: []op{
+				{kind: "set", key: "a", value: "1"},
+				{kind: "set", key: "b", value: "2"},
+				{kind: "set", key: "c", value: "3"},
+				{kind: "get", key: "a"},
+				{kind: "get", key: "b", found: true, want: "2"},
+				{kind: "get", key: "c", found: true, want: "3"},
+			},
+		},
+		{
+			name:     "evicts the least recently read",
+			capacity: 2,
+			ops: []op{
+				{kind: "set", key: "a", value: "1"},
+				{kind: "set", key: "b", value: "2"},
+				{kind: "get", key: "a", found: true, want: "1"},
+				{kind: "set", key: "c", value: "3"},
+				{kind: "get", key: "a", found: true, want: "1"},
+				{kind: "get", key: "b"},
+			},
+		},
+		{
+			name:     "keeps nothing with no capacity",
+			capacity: 0,
+			ops: []op{
+				{kind: "set", key: "a", value: "1"},
+				{kind: "get", key: "a"},
+			},
+		},
+		{
+			name:     "deletes keys",
+			capacity: 3,
+			ops: []op{
+				{kind: "set", key: "a", value: "1"},
+				{kind: "set", key: "b", value: "2"},
+				{kind: "delete", key: "a"},
+				{kind: "delete", key: "missing"},
+				{kind: "get", key: "a"},
+				{kind: "get", key: "b", found: true, want: "2"},
+			},
+		},
+		{
+			name:     "expires values after ttl",
+			capacity: 2,
+			ops: []op{
+				{kind: "set", key: "a", value: "1", ttl: 10 * time.Millisecond},
+				{kind: "set", key: "b", value: "2"},
+				{kind: "get", key: "a", found: true, want: "1"},
+				{kind: "get", key: "a", sleep: 20 * time.Millisecond},
+				{kind: "get", key: "b", found: true, want: "2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewLRU(tt.capacity)
+
+			for i, el := range tt.ops {
+				time.Sleep(el.sleep)
+
+				switch el.kind {
+				case "set":
+					if err := c.Set(ctx, el.key, []byte(el.value), el.ttl); err != nil {
+						t.Fatalf("op %d: set %q: %v", i+1, el.key, err)
+					}
+				case "delete":
+					if err := c.Delete(ctx, el.key); err != nil {
+						t.Fatalf("op %d: delete %q: %v", i+1, el.key, err)
+					}
+				case "get":
+					value, found, err := c.Get(ctx, el.key)
+					if err != nil {
+						t.Fatalf("op %d: get %q: %v", i+1, el.key, err)
+					}
+					if found != el.found || string(value) != el.want {
+						t.Errorf("op %d: get %q = %q, %v, want %q, %v", i+1, el.key, value, found, el.want, el.found)
+					}
+				}
+			}
+
+			if c.order.Len() != len(c.entries) {
+				t.Errorf("%d entries in order, %d in map", c.order.Len(), len(c.entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// formulaPrefixes start the values spreadsheets read as formulas.
+const formulaPrefixes = "=+-@\t\r"
+
+type csvTable struct {
+	w *csv.Writer
+}
+
+func newCSV(w io.Writer) *csvTable {
+	return &csvTable{w: csv.NewWriter(w)}
+}
+
+func (t *csvTable) writeRow(values []string) error {
+	cells := make([]string, len(values))
+	for i, el := range values {
+		cells[i] = csvCell(el)
+	}
+
+	err := t.w.Write(cells)
+	if err != nil {
+		return err
+	}
+	// csv.Writer buffers on its own; flushing with every row keeps the
+	// buffer bounded while the http layer decides when to send.
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func (t *csvTable) close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// csvCell keeps a user's text from running as a formula when the file is
+// opened in a spreadsheet, the leading quote makes it plain text. Numbers
+// are left as they are.
+func csvCell(value string) string {
+	if value == "" || !strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
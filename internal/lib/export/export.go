@@ -0,0 +1,167 @@
+package export
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"tender_service/internal/lib/response"
+	"time"
+)
+
+// Format is the representation a list endpoint responds with.
+type Format string
+
+const (
+	JSON Format = "json"
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// Param is the query parameter choosing the format. It takes precedence
+// over the Accept header.
+const Param = "format"
+
+const (
+	csvContentType  = "text/csv"
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var ErrUnsupported = response.ErrUnsupportedFormat
+
+// FromRequest returns the format asked for by the request, JSON when none
+// of the export formats is asked for.
+func FromRequest(r *http.Request) (Format, error) {
+	if r.URL.Query().Has(Param) {
+		switch Format(strings.ToLower(r.URL.Query().Get(Param))) {
+		case JSON:
+			return JSON, nil
+		case CSV:
+			return CSV, nil
+		case XLSX:
+			return XLSX, nil
+		}
+		return "", ErrUnsupported
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case csvContentType:
+			return CSV, nil
+		case xlsxContentType:
+			return XLSX, nil
+		case "application/json", "*/*":
+			return JSON, nil
+		}
+	}
+	return JSON, nil
+}
+
+// Writer streams a table to the response, one row at a time. Nothing is
+// written until the first row or Close, so errors found before that can
+// still be answered with a status code.
+type Writer struct {
+	w       http.ResponseWriter
+	format  Format
+	name    string
+	columns []string
+	started bool
+	rows    int
+	table   table
+}
+
+type table interface {
+	writeRow(values []string) error
+	close() error
+}
+
+// flushEvery is how many rows are buffered before they are pushed to the
+// client. Every push also moves the server's write deadline writeWindow
+// ahead, so a long table is not cut off while it keeps moving.
+const (
+	flushEvery  = 100
+	writeWindow = 15 * time.Second
+)
+
+// NewWriter returns a writer of a table with the given columns, sent as
+// the attachment name.csv or name.xlsx.
+func NewWriter(w http.ResponseWriter, format Format, name string, columns ...string) *Writer {
+	return &Writer{
+		w:       w,
+		format:  format,
+		name:    name,
+		columns: columns,
+	}
+}
+
+// Started reports whether the response has been sent already.
+func (e *Writer) Started() bool {
+	return e != nil && e.started
+}
+
+// Write adds a row to the table.
+func (e *Writer) Write(values ...string) error {
+	err := e.start()
+	if err != nil {
+		return err
+	}
+
+	err = e.table.writeRow(values)
+	if err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%flushEvery == 0 {
+		rc := http.NewResponseController(e.w)
+		_ = rc.SetWriteDeadline(time.Now().Add(writeWindow))
+		err = rc.Flush()
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close finishes the table, sending just the header when there were no
+// rows.
+func (e *Writer) Close() error {
+	err := e.start()
+	if err != nil {
+		return err
+	}
+	return e.table.close()
+}
+
+func (e *Writer) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	contentType := csvContentType + "; charset=utf-8"
+	if e.format == XLSX {
+		contentType = xlsxContentType
+	}
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": e.name + "." + string(e.format),
+	}))
+	_ = http.NewResponseController(e.w).SetWriteDeadline(time.Now().Add(writeWindow))
+	e.w.WriteHeader(http.StatusOK)
+
+	var err error
+	if e.format == XLSX {
+		e.table, err = newXLSX(e.w)
+	} else {
+		e.table = newCSV(e.w)
+	}
+	if err != nil {
+		return err
+	}
+	return e.table.writeRow(e.columns)
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+var formulaCells = []struct {
+	value string
+	csv   string
+}{
+	{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+	{"+1+1", "'+1+1"},
+	{"-2+3+cmd|' /C calc'!A0", "'-2+3+cmd|' /C calc'!A0"},
+	{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+	{"\t=1", "'\t=1"},
+	{"\r=1", "'\r=1"},
+	{"-15", "-15"},
+	{"+3.5", "+3.5"},
+	{"plain = text", "plain = text"},
+}
+
+func TestCSVFormulas(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec, CSV, "tenders", "value")
+	for _, el := range formulaCells {
+		if err := w.Write(el.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(formulaCells)+1 {
+		t.Fatalf("%d records, want %d", len(records), len(formulaCells)+1)
+	}
+	for i, el := range formulaCells {
+		if got := records[i+1][0]; got != el.csv {
+			t.Errorf("cell %q written as %q, want %q", el.value, got, el.csv)
+		}
+	}
+}
+
+func TestXLSXFormulas(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec, XLSX, "tenders", "value")
+	for _, el := range formulaCells {
+		if err := w.Write(el.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := archive.Open(sheetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(sheet, []byte("<f>")) || bytes.Contains(sheet, []byte("<f ")) {
+		t.Error("sheet holds a formula")
+	}
+	if n := bytes.Count(sheet, []byte(`<c t="inlineStr">`)); n != len(formulaCells)+1 {
+		t.Errorf("%d string cells, want %d", n, len(formulaCells)+1)
+	}
+	if !bytes.Contains(sheet, []byte("=HYPERLINK(&#34;http://evil&#34;)")) {
+		t.Error("text of a formula-like cell is changed")
+	}
+}
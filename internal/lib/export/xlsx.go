@@ -0,0 +1,116 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+)
+
+// The workbook holds a single sheet with inline strings, which lets rows
+// be written as they come instead of collecting a shared string table.
+// Inline strings are never read as formulas, whatever text they hold.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+const (
+	sheetName   = "xl/worksheets/sheet1.xml"
+	sheetHeader = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetFooter = `</sheetData></worksheet>`
+)
+
+type xlsxTable struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+}
+
+func newXLSX(w io.Writer) (*xlsxTable, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.WriteString(f, part.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := archive.Create(sheetName)
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	_, err = sheet.WriteString(sheetHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxTable{zip: archive, sheet: sheet}, nil
+}
+
+func (t *xlsxTable) writeRow(values []string) error {
+	_, err := t.sheet.WriteString("<row>")
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		_, err = t.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err != nil {
+			return err
+		}
+		err = xml.EscapeText(t.sheet, []byte(value))
+		if err != nil {
+			return err
+		}
+		_, err = t.sheet.WriteString("</t></is></c>")
+		if err != nil {
+			return err
+		}
+	}
+	_, err = t.sheet.WriteString("</row>")
+	return err
+}
+
+func (t *xlsxTable) close() error {
+	_, err := t.sheet.WriteString(sheetFooter)
+	if err != nil {
+		return err
+	}
+	err = t.sheet.Flush()
+	if err != nil {
+		return err
+	}
+	return t.zip.Close()
+}
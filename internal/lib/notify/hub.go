@@ -0,0 +1,45 @@
+// Package notify wakes up the goroutines waiting for something to change.
+package notify
+
+import "sync"
+
+// Hub fans a wake-up out to every subscriber. Wake-ups carry no data and
+// coalesce: a subscriber that has not caught up with the previous one gets
+// a single pending wake-up, so a slow subscriber never blocks Notify.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan struct{}]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan struct{}]struct{})}
+}
+
+// Subscribe returns the channel the wake-ups arrive on and the function
+// that stops them.
+func (h *Hub) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs, ch)
+		h.mu.Unlock()
+	}
+}
+
+// Notify wakes up every subscriber.
+func (h *Hub) Notify() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryAllow(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 3}
+
+	type step struct {
+		// after is the time passed since the previous request
+		after     time.Duration
+		key       string
+		allowed   bool
+		remaining int
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "allows the burst at once",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2},
+				{key: "a", allowed: true, remaining: 1},
+				{key: "a", allowed: true, remaining: 0},
+				{key: "a", allowed: false, remaining: 0},
+			},
+		},
+		{
+			name: "refills at the rate",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2},
+				{key: "a", allowed: true, remaining: 1},
+				{key: "a", allowed: true, remaining: 0},
+				{after: 250 * time.Millisecond, key: "a", allowed: false, remaining: 0},
+				{after: 250 * time.Millisecond, key: "a", allowed: true, remaining: 0},
+				{after: time.Second, key: "a", allowed: true, remaining: 1},
+			},
+		},
+		{
+			name: "refills no further than the burst",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2},
+				{after: time.Hour, key: "a", allowed: true, remaining: 2},
+			},
+		},
+		{
+			name: "keeps a bucket per key",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2},
+				{key: "a", allowed: true, remaining: 1},
+				{key: "a", allowed: true, remaining: 0},
+				{key: "b", allowed: true, remaining: 2},
+				{key: "a", allowed: false, remaining: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			m := NewMemory()
+			m.now = func() time.Time { return now }
+
+			for i, el := range tt.steps {
+				now = now.Add(el.after)
+				res, err := m.Allow(context.Background(), el.key, limit)
+				if err != nil {
+					t.Fatalf("request %d: %v", i+1, err)
+				}
+				if res.Allowed != el.allowed || res.Remaining != el.remaining {
+					t.Errorf("request %d: allowed %v with %d remaining, want %v with %d",
+						i+1, res.Allowed, res.Remaining, el.allowed, el.remaining)
+				}
+				if res.Limit != limit.Burst {
+					t.Errorf("request %d: limit %d, want %d", i+1, res.Limit, limit.Burst)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	limit := Limit{Rate: 0.5, Burst: 1}
+
+	if res, _ := m.Allow(context.Background(), "a", limit); !res.Allowed {
+		t.Fatal("first request denied")
+	}
+
+	res, _ := m.Allow(context.Background(), "a", limit)
+	if res.Allowed {
+		t.Fatal("request over the burst allowed")
+	}
+	if res.RetryAfter != 2*time.Second {
+		t.Errorf("retry after %v, want %v", res.RetryAfter, 2*time.Second)
+	}
+	if res.Reset != 2*time.Second {
+		t.Errorf("reset after %v, want %v", res.Reset, 2*time.Second)
+	}
+
+	now = now.Add(res.RetryAfter)
+	if res, _ := m.Allow(context.Background(), "a", limit); !res.Allowed {
+		t.Error("request after the retry delay denied")
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	m.lastSweep = now
+
+	m.Allow(context.Background(), "idle", Limit{Rate: 1, Burst: 10})
+	now = now.Add(30 * time.Second)
+	m.Allow(context.Background(), "busy", Limit{Rate: 1, Burst: 100})
+
+	now = now.Add(sweepInterval)
+	m.Allow(context.Background(), "other", Limit{Rate: 1, Burst: 1})
+
+	if _, ok := m.buckets["idle"]; ok {
+		t.Error("full idle bucket kept")
+	}
+	if _, ok := m.buckets["busy"]; !ok {
+		t.Error("refilling bucket dropped")
+	}
+}
@@ -0,0 +1,27 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit describes a token bucket: Rate tokens are added per second up to Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter is implemented by rate limit backends. The in-process Memory
+// backend is enough for a single replica, deployments with several replicas
+// need a shared one.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,54 @@
+package response
+
+import "net/http"
+
+// Code names an error for the clients, codes are never renamed.
+type Code string
+
+const (
+	CodeInternal   Code = "internal_error"
+	CodeValidation Code = "validation_failed"
+)
+
+// codesByError gives the code and the HTTP status of every error a handler
+// may answer with.
+var codesByError = []struct {
+	err    error
+	code   Code
+	status int
+}{
+	{ErrUserNotExists, "user_not_exists", http.StatusUnauthorized},
+	{ErrIncorrectValue, "incorrect_value", http.StatusBadRequest},
+	{ErrInternalError, CodeInternal, http.StatusInternalServerError},
+	{ErrTenderNotExists, "tender_not_exists", http.StatusNotFound},
+	{ErrBidNotExists, "bid_not_exists", http.StatusNotFound},
+	{ErrEmptyBody, "request_body_empty", http.StatusBadRequest},
+	{ErrBodyTooLarge, "request_body_too_large", http.StatusRequestEntityTooLarge},
+	{ErrInvalidRequest, "invalid_request", http.StatusBadRequest},
+	{ErrInvalidID, "invalid_id", http.StatusBadRequest},
+	{ErrUnsupportedFormat, "unsupported_format", http.StatusBadRequest},
+	{ErrRouteNotExists, "route_not_exists", http.StatusNotFound},
+	{ErrMethodNotAllowed, "method_not_allowed", http.StatusMethodNotAllowed},
+	{ErrInvitationNotExists, "invitation_not_exists", http.StatusNotFound},
+	{ErrLotNotExists, "lot_not_exists", http.StatusNotFound},
+	{ErrAuctionNotExists, "auction_not_exists", http.StatusNotFound},
+	{ErrQuestionNotExists, "question_not_exists", http.StatusNotFound},
+	{ErrTemplateNotExists, "template_not_exists", http.StatusNotFound},
+	{ErrMessageNotExists, "message_not_exists", http.StatusNotFound},
+	{ErrEditWindowExpired, "edit_window_expired", http.StatusForbidden},
+	{ErrOrganizationNotExists, "organization_not_exists", http.StatusNotFound},
+	{ErrOrganizationAmbiguous, "organization_ambiguous", http.StatusBadRequest},
+	{ErrMemberNotExists, "member_not_exists", http.StatusNotFound},
+	{ErrLastAdmin, "last_admin", http.StatusConflict},
+	{ErrCategoryNotExists, "category_not_exists", http.StatusNotFound},
+	{ErrCategoryInUse, "category_in_use", http.StatusConflict},
+	{ErrNotificationSettingsNotExists, "notification_settings_not_exists", http.StatusNotFound},
+	{ErrNoRights, "no_rights", http.StatusForbidden},
+	{ErrRequestCanceled, "request_canceled", StatusClientClosedRequest},
+	{ErrDeadlineExceeded, "deadline_exceeded", http.StatusGatewayTimeout},
+	{ErrTooManyRequests, "too_many_requests", http.StatusTooManyRequests},
+	{ErrIdempotencyKeyNotExists, "idempotency_key_not_exists", http.StatusNotFound},
+	{ErrIdempotencyKeyReused, "idempotency_key_reused", http.StatusUnprocessableEntity},
+	{ErrIdempotencyKeyInProgress, "idempotency_key_in_progress", http.StatusConflict},
+	{ErrIdempotencyKeyTooLong, "idempotency_key_too_long", http.StatusBadRequest},
+}
@@ -0,0 +1,185 @@
+package response
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when Accept-Language asks for no supported one.
+const DefaultLanguage = "en"
+
+// Language picks the most preferred language of Accept-Language the
+// messages are translated to.
+func Language(r *http.Request) string {
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if _, ok := messages[CodeInternal][base]; ok && q > bestQ {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+func message(code Code, lang string) string {
+	if msg, ok := messages[code][lang]; ok {
+		return msg
+	}
+	return messages[code][DefaultLanguage]
+}
+
+// messages are the reasons of the codes in every supported language.
+var messages = map[Code]map[string]string{
+	CodeInternal: {
+		"en": "Internal server error.",
+		"ru": "Внутренняя ошибка сервера.",
+	},
+	CodeValidation: {
+		"en": "The data is malformed or does not meet the requirements.",
+		"ru": "Данные неправильно сформированы или не соответствуют требованиям.",
+	},
+	"user_not_exists": {
+		"en": "The user does not exist or is incorrect.",
+		"ru": "Пользователь не существует или некорректен.",
+	},
+	"incorrect_value": {
+		"en": "The data is malformed or does not meet the requirements.",
+		"ru": "Данные неправильно сформированы или не соответствуют требованиям.",
+	},
+	"tender_not_exists": {
+		"en": "The tender is not found.",
+		"ru": "Тендер не найден.",
+	},
+	"bid_not_exists": {
+		"en": "The bid is not found.",
+		"ru": "Предложение не найдено.",
+	},
+	"request_body_empty": {
+		"en": "The request body is empty.",
+		"ru": "Тело запроса пустое.",
+	},
+	"request_body_too_large": {
+		"en": "The request body is too large.",
+		"ru": "Тело запроса слишком большое.",
+	},
+	"invalid_request": {
+		"en": "The request or its parameters have an invalid format.",
+		"ru": "Неверный формат запроса или его параметры.",
+	},
+	"invalid_id": {
+		"en": "The id has an invalid format.",
+		"ru": "Неверный формат идентификатора.",
+	},
+	"unsupported_format": {
+		"en": "The export format is not supported.",
+		"ru": "Формат выгрузки не поддерживается.",
+	},
+	"route_not_exists": {
+		"en": "The route is not found.",
+		"ru": "Маршрут не найден.",
+	},
+	"method_not_allowed": {
+		"en": "The method is not allowed for the route.",
+		"ru": "Метод не поддерживается маршрутом.",
+	},
+	"invitation_not_exists": {
+		"en": "The invitation is not found.",
+		"ru": "Приглашение не найдено.",
+	},
+	"lot_not_exists": {
+		"en": "The lot is not found.",
+		"ru": "Лот не найден.",
+	},
+	"auction_not_exists": {
+		"en": "The auction is not found.",
+		"ru": "Аукцион не найден.",
+	},
+	"question_not_exists": {
+		"en": "The question is not found.",
+		"ru": "Вопрос не найден.",
+	},
+	"template_not_exists": {
+		"en": "The template is not found.",
+		"ru": "Шаблон не найден.",
+	},
+	"message_not_exists": {
+		"en": "The message is not found.",
+		"ru": "Сообщение не найдено.",
+	},
+	"edit_window_expired": {
+		"en": "The message can no longer be edited.",
+		"ru": "Сообщение больше нельзя редактировать.",
+	},
+	"organization_not_exists": {
+		"en": "The organization is not found.",
+		"ru": "Организация не найдена.",
+	},
+	"organization_ambiguous": {
+		"en": "The user is responsible for several organizations, specify organizationId.",
+		"ru": "Пользователь ответственный в нескольких организациях, укажите organizationId.",
+	},
+	"member_not_exists": {
+		"en": "The employee is not a member of the organization.",
+		"ru": "Сотрудник не состоит в организации.",
+	},
+	"last_admin": {
+		"en": "The organization must keep at least one admin.",
+		"ru": "В организации должен остаться хотя бы один администратор.",
+	},
+	"category_not_exists": {
+		"en": "The category is not found.",
+		"ru": "Категория не найдена.",
+	},
+	"category_in_use": {
+		"en": "The category has subcategories or tenders.",
+		"ru": "У категории есть подкатегории или тендеры.",
+	},
+	"notification_settings_not_exists": {
+		"en": "The notification settings are not found.",
+		"ru": "Настройки уведомлений не найдены.",
+	},
+	"no_rights": {
+		"en": "Not enough rights to perform the action.",
+		"ru": "Недостаточно прав для выполнения действия.",
+	},
+	"request_canceled": {
+		"en": "The request was canceled.",
+		"ru": "Запрос отменён.",
+	},
+	"deadline_exceeded": {
+		"en": "The request took too long.",
+		"ru": "Превышено время выполнения запроса.",
+	},
+	"too_many_requests": {
+		"en": "Too many requests, try again later.",
+		"ru": "Слишком много запросов, повторите позже.",
+	},
+	"idempotency_key_not_exists": {
+		"en": "The idempotency key is not found.",
+		"ru": "Ключ идемпотентности не найден.",
+	},
+	"idempotency_key_reused": {
+		"en": "The idempotency key was already used for another request.",
+		"ru": "Ключ идемпотентности уже использован для другого запроса.",
+	},
+	"idempotency_key_in_progress": {
+		"en": "The request with this idempotency key is in progress.",
+		"ru": "Запрос с этим ключом идемпотентности ещё выполняется.",
+	},
+	"idempotency_key_too_long": {
+		"en": "The idempotency key is too long.",
+		"ru": "Ключ идемпотентности слишком длинный.",
+	},
+}
@@ -3,30 +3,106 @@ package response
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 
-	validator "github.com/go-playground/validator/v10"
+	"github.com/go-chi/render"
 )
 
+const StatusClientClosedRequest = 499
+
+// Response is the error envelope of every handler. Code is stable for
+// clients to branch on, Reason is told in the language of the request and
+// Detail keeps the untranslated text when it says more than the code.
 type Response struct {
-	Reason string `json:"reason"`
+	Code   Code         `json:"code"`
+	Reason string       `json:"reason"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// Error describes err for the client of the request. Errors without a code
+// are reported as internal ones and logged instead of shown.
+func Error(r *http.Request, err error) Response {
+	return describe(Language(r), err, slog.String("path", r.URL.Path))
 }
 
-func Error(msg string) Response {
-	return Response{
-		Reason: msg,
+// ErrorIn describes err in lang, for the errors reported inside a response
+// rather than as one.
+func ErrorIn(lang string, err error) Response {
+	return describe(lang, err)
+}
+
+func describe(lang string, err error, attrs ...any) Response {
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		return Response{
+			Code:   CodeValidation,
+			Reason: message(CodeValidation, lang),
+			Errors: fieldErrs.localize(lang),
+		}
+	}
+
+	for _, el := range codesByError {
+		if !errors.Is(err, el.err) {
+			continue
+		}
+
+		res := Response{Code: el.code, Reason: message(el.code, lang)}
+		if err.Error() != el.err.Error() {
+			res.Detail = err.Error()
+		}
+		return res
 	}
+
+	slog.Error(err.Error(), append([]any{slog.String("op", "response.Error")}, attrs...)...)
+	return Response{Code: CodeInternal, Reason: message(CodeInternal, lang)}
 }
 
-func ValidationError(errs validator.ValidationErrors) string {
-	for _, err := range errs {
-		switch err.ActualTag() {
-		case "required":
-			return fmt.Sprintf("field %s is required", err.Field())
-		default:
-			return fmt.Sprintf("field %s is not valid", err.Field())
+// Status is the HTTP status a handler answers err with, errors without a
+// code are internal ones.
+func Status(err error) int {
+	var ref referenced
+	if errors.As(err, &ref) {
+		return http.StatusBadRequest
+	}
+
+	for _, el := range codesByError {
+		if errors.Is(err, el.err) {
+			return el.status
 		}
 	}
-	return ""
+	return http.StatusInternalServerError
+}
+
+// Render answers the request with err, its status and its description.
+func Render(w http.ResponseWriter, r *http.Request, err error) {
+	w.WriteHeader(Status(err))
+	render.JSON(w, r, Error(r, err))
+}
+
+// Referenced marks a missing resource that the request refers to, not the
+// one it is made to: it keeps its code but is answered with 400 as any
+// other incorrect value. Other errors are returned as they are.
+func Referenced(err error) error {
+	if Status(err) != http.StatusNotFound {
+		return err
+	}
+	return referenced{err}
+}
+
+type referenced struct{ error }
+
+func (e referenced) Unwrap() error { return e.error }
+
+// Invalid reports a request value breaking a rule that is not a field tag.
+func Invalid(msg string) error {
+	return fmt.Errorf("%w: %s", ErrIncorrectValue, msg)
+}
+
+// InvalidID reports a malformed id in the named URL parameter.
+func InvalidID(param string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidID, param)
 }
 
 var (
@@ -36,5 +112,49 @@ var (
 	ErrTenderNotExists = errors.New("tender not exists")
 	ErrBidNotExists    = errors.New("bid not exists")
 
+	ErrEmptyBody      = errors.New("request body is empty")
+	ErrBodyTooLarge   = errors.New("request body is too large")
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrInvalidID      = errors.New("invalid id")
+
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+
+	ErrRouteNotExists   = errors.New("route not exists")
+	ErrMethodNotAllowed = errors.New("method not allowed")
+
+	ErrInvitationNotExists = errors.New("invitation not exists")
+
+	ErrLotNotExists = errors.New("lot not exists")
+
+	ErrAuctionNotExists = errors.New("auction not exists")
+
+	ErrQuestionNotExists = errors.New("question not exists")
+
+	ErrTemplateNotExists = errors.New("template not exists")
+
+	ErrMessageNotExists  = errors.New("message not exists")
+	ErrEditWindowExpired = errors.New("message can no longer be edited")
+
+	ErrOrganizationNotExists = errors.New("organization not exists")
+	ErrOrganizationAmbiguous = errors.New("user is responsible for several organizations, specify organizationId")
+
+	ErrMemberNotExists = errors.New("employee is not a member of the organization")
+	ErrLastAdmin       = errors.New("organization must keep at least one admin")
+
+	ErrCategoryNotExists = errors.New("category not exists")
+	ErrCategoryInUse     = errors.New("category has subcategories or tenders")
+
+	ErrNotificationSettingsNotExists = errors.New("notification settings not exists")
+
 	ErrNoRights = errors.New("no rights for this operation")
+
+	ErrRequestCanceled  = errors.New("request canceled")
+	ErrDeadlineExceeded = errors.New("request deadline exceeded")
+
+	ErrTooManyRequests = errors.New("too many requests")
+
+	ErrIdempotencyKeyNotExists  = errors.New("idempotency key not exists")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key already used for another request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
+	ErrIdempotencyKeyTooLong    = errors.New("idempotency key is too long")
 )
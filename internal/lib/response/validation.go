@@ -0,0 +1,204 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+// FieldError is a field of the request body that failed a validation tag,
+// Pointer is the JSON pointer of the field.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Reason  string `json:"reason"`
+
+	field string
+	param string
+}
+
+// FieldErrors are all the fields that failed validation, they are
+// ErrIncorrectValue for the callers.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, el := range e {
+		msgs = append(msgs, fieldMessage(el, DefaultLanguage))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e FieldErrors) Is(target error) bool {
+	return target == ErrIncorrectValue
+}
+
+func (e FieldErrors) localize(lang string) []FieldError {
+	res := make([]FieldError, 0, len(e))
+	for _, el := range e {
+		el.Reason = fieldMessage(el, lang)
+		res = append(res, el)
+	}
+	return res
+}
+
+var validate = newValidator()
+
+// newValidator names the fields by their JSON names, and the fields
+// without one, such as those of the service inputs, by their Go names in
+// lower camel case.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || name == "" {
+			return lowerCamel(f.Name)
+		}
+		return name
+	})
+	return v
+}
+
+// lowerCamel lowers the leading capitals of a Go name and spells a
+// trailing ID the way the API does, so TenderID is tenderId and ID is id.
+func lowerCamel(name string) string {
+	if base, ok := strings.CutSuffix(name, "ID"); ok && base != "" {
+		name = base + "Id"
+	}
+
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// Validate checks the struct tags of a request and reports every field
+// that failed them.
+func Validate(req any) error {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	res := make(FieldErrors, 0, len(errs))
+	for _, el := range errs {
+		param := el.Param()
+		if el.ActualTag() == "required_without" {
+			// the param names the other field by its Go name
+			param = lowerCamel(param)
+		}
+
+		res = append(res, FieldError{
+			Pointer: pointer(el.Namespace()),
+			Code:    el.ActualTag(),
+			field:   el.Field(),
+			param:   param,
+		})
+	}
+	return res
+}
+
+// pointer turns a namespace like "Request.items[0].name" into the JSON
+// pointer "/items/0/name".
+func pointer(namespace string) string {
+	_, path, _ := strings.Cut(namespace, ".")
+
+	var b strings.Builder
+	for _, part := range strings.Split(path, ".") {
+		name, index, _ := strings.Cut(part, "[")
+		b.WriteString("/" + escapePointer(name))
+		for index != "" {
+			var key string
+			key, index, _ = strings.Cut(index, "]")
+			b.WriteString("/" + escapePointer(key))
+			index = strings.TrimPrefix(index, "[")
+		}
+	}
+	return b.String()
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func fieldMessage(el FieldError, lang string) string {
+	formats, ok := fieldMessages[el.Code]
+	if !ok {
+		formats = fieldMessages[""]
+	}
+
+	format := formats[lang]
+	if strings.Count(format, "%s") == 2 {
+		return fmt.Sprintf(format, el.field, el.param)
+	}
+	return fmt.Sprintf(format, el.field)
+}
+
+// fieldMessages are the reasons of the validation tags, the empty tag is
+// the one of the tags without their own.
+var fieldMessages = map[string]map[string]string{
+	"": {
+		"en": "field %s is not valid",
+		"ru": "поле %s заполнено некорректно",
+	},
+	"required": {
+		"en": "field %s is required",
+		"ru": "поле %s обязательно",
+	},
+	"required_without": {
+		"en": "field %s is required without %s",
+		"ru": "поле %s обязательно без %s",
+	},
+	"max": {
+		"en": "field %s must not exceed %s",
+		"ru": "поле %s не должно превышать %s",
+	},
+	"min": {
+		"en": "field %s must be at least %s",
+		"ru": "поле %s должно быть не меньше %s",
+	},
+	"gt": {
+		"en": "field %s must be greater than %s",
+		"ru": "поле %s должно быть больше %s",
+	},
+	"gte": {
+		"en": "field %s must be at least %s",
+		"ru": "поле %s должно быть не меньше %s",
+	},
+	"lt": {
+		"en": "field %s must be less than %s",
+		"ru": "поле %s должно быть меньше %s",
+	},
+	"lte": {
+		"en": "field %s must not exceed %s",
+		"ru": "поле %s не должно превышать %s",
+	},
+	"oneof": {
+		"en": "field %s must be one of: %s",
+		"ru": "поле %s должно быть одним из: %s",
+	},
+	"uuid": {
+		"en": "field %s must be a UUID",
+		"ru": "поле %s должно быть UUID",
+	},
+	"email": {
+		"en": "field %s must be an email address",
+		"ru": "поле %s должно быть адресом email",
+	},
+}
@@ -5,3 +5,11 @@ import "time"
 func Time(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
+
+// OptionalTime formats t, leaving it empty when not set.
+func OptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return Time(*t)
+}
@@ -0,0 +1,136 @@
+package mail
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Received is an email taken by a Sink.
+type Received struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Sink is an SMTP server keeping the emails instead of delivering them, for
+// local runs and tests. It speaks just enough SMTP for the SMTP sender.
+type Sink struct {
+	ln       net.Listener
+	received func(Received)
+
+	mu       sync.Mutex
+	messages []Received
+}
+
+// NewSink starts a sink on addr, ":0" picks a free port. received, when
+// set, is called with every email taken.
+func NewSink(addr string, received func(Received)) (*Sink, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sink{ln: ln, received: received}
+	go s.serve()
+	return s, nil
+}
+
+// Addr is the address the sink listens on.
+func (s *Sink) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Messages returns the emails taken so far.
+func (s *Sink) Messages() []Received {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Received(nil), s.messages...)
+}
+
+func (s *Sink) Close() error {
+	return s.ln.Close()
+}
+
+func (s *Sink) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.session(conn)
+	}
+}
+
+func (s *Sink) session(conn net.Conn) {
+	tp := textproto.NewConn(conn)
+	defer tp.Close()
+
+	reply := func(code int, msg string) bool {
+		return tp.PrintfLine("%d %s", code, msg) == nil
+	}
+
+	if !reply(220, "tender-service sink ready") {
+		return
+	}
+
+	var msg Received
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply(250, "sink")
+		case "MAIL":
+			msg = Received{From: address(arg)}
+			reply(250, "OK")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			reply(250, "OK")
+		case "DATA":
+			if !reply(354, "end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			s.take(msg)
+			msg = Received{}
+			reply(250, "OK")
+		case "RSET":
+			msg = Received{}
+			reply(250, "OK")
+		case "NOOP":
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "command not implemented")
+		}
+	}
+}
+
+func (s *Sink) take(msg Received) {
+	s.mu.Lock()
+	s.messages = append(s.messages, msg)
+	s.mu.Unlock()
+
+	if s.received != nil {
+		s.received(msg)
+	}
+}
+
+// address extracts the address of "FROM:<a@b>" and "TO:<a@b>".
+func address(arg string) string {
+	_, value, _ := strings.Cut(arg, ":")
+	value, _, _ = strings.Cut(strings.TrimSpace(value), " ")
+	return strings.Trim(value, "<>")
+}
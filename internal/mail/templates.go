@@ -0,0 +1,115 @@
+// Package mail sends the email notifications queued by the service: it
+// renders them from localized templates, sends them over SMTP with retries
+// and provides an SMTP sink for local runs and tests.
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	"tender_service/internal/storage/models"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var files embed.FS
+
+// fallbackLocale is used when neither the recipient's nor the default
+// locale has the template.
+const fallbackLocale = "en"
+
+// Content is a rendered email.
+type Content struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Templates are the emails of every kind in every locale, found in
+// templates/{locale}/{kind}.tmpl. A template defines "subject" and "text",
+// rendered as text, and "html".
+type Templates struct {
+	text   map[string]*texttemplate.Template
+	html   map[string]*htmltemplate.Template
+	locale string
+}
+
+var funcs = map[string]any{
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	},
+}
+
+// LoadTemplates parses the embedded templates, locale being the default
+// language.
+func LoadTemplates(locale string) (*Templates, error) {
+	t := &Templates{
+		text:   make(map[string]*texttemplate.Template),
+		html:   make(map[string]*htmltemplate.Template),
+		locale: locale,
+	}
+
+	names, err := fs.Glob(files, "templates/*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		src, err := files.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		key := path.Base(path.Dir(name)) + "/" + strings.TrimSuffix(path.Base(name), ".tmpl")
+
+		t.text[key], err = texttemplate.New(key).Funcs(funcs).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("mail: %s: %w", name, err)
+		}
+		t.html[key], err = htmltemplate.New(key).Funcs(funcs).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("mail: %s: %w", name, err)
+		}
+	}
+	return t, nil
+}
+
+// Render renders the email of the kind in the locale, falling back to the
+// default one.
+func (t *Templates) Render(kind models.NotificationKind, locale string, data models.NotificationData) (Content, error) {
+	var key string
+	for _, el := range []string{locale, t.locale, fallbackLocale} {
+		if _, ok := t.text[el+"/"+string(kind)]; ok && el != "" {
+			key = el + "/" + string(kind)
+			break
+		}
+	}
+	if key == "" {
+		return Content{}, fmt.Errorf("mail: no template for %s", kind)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.text[key].ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Content{}, err
+	}
+	if err := t.text[key].ExecuteTemplate(&text, "text", data); err != nil {
+		return Content{}, err
+	}
+	if err := t.html[key].ExecuteTemplate(&html, "html", data); err != nil {
+		return Content{}, err
+	}
+
+	return Content{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
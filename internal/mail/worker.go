@@ -0,0 +1,144 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+	"tender_service/internal/config"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// batchSize is how many emails are claimed at a time
+	batchSize = 20
+	// lease is how long a claimed email is not handed out again
+	lease = 5 * time.Minute
+	// sendTimeout bounds the delivery of one email
+	sendTimeout = 30 * time.Second
+	// pollInterval is how often the queue is looked at
+	pollInterval = 10 * time.Second
+
+	retryDelay    = time.Minute
+	retryDelayMax = time.Hour
+)
+
+// Queue is where the emails wait to be sent, implemented by the storage.
+type Queue interface {
+	// ClaimNotifications takes up to limit emails due to be sent, counts
+	// the attempt and keeps them from being handed out again for lease.
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error)
+	CompleteNotification(ctx context.Context, id uuid.UUID) error
+	// RetryNotification records why the email was not sent and when to
+	// try it again, or gives up on it when next is nil.
+	RetryNotification(ctx context.Context, id uuid.UUID, next *time.Time, reason string) error
+}
+
+// Worker sends the queued emails, retrying the failed ones with a growing
+// delay until they run out of attempts.
+type Worker struct {
+	queue       Queue
+	sender      Sender
+	templates   *Templates
+	from        string
+	maxAttempts int
+	log         *slog.Logger
+}
+
+func NewWorker(queue Queue, sender Sender, templates *Templates, cfg config.Mail, log *slog.Logger) *Worker {
+	return &Worker{
+		queue:       queue,
+		sender:      sender,
+		templates:   templates,
+		from:        cfg.From,
+		maxAttempts: cfg.MaxAttempts,
+		log:         log,
+	}
+}
+
+// Run sends the emails as they become due until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.flush(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// flush sends the emails due now.
+func (w *Worker) flush(ctx context.Context) {
+	for {
+		notifications, err := w.queue.ClaimNotifications(ctx, batchSize, lease)
+		if err != nil {
+			w.log.Error("failed to claim notifications", slog.String("error", err.Error()))
+			return
+		}
+
+		for _, el := range notifications {
+			w.deliver(ctx, &el)
+		}
+
+		if len(notifications) < batchSize {
+			return
+		}
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, n *models.Notification) {
+	log := w.log.With(slog.String("notification", n.ID.String()), slog.String("kind", string(n.Kind)))
+
+	// A template that fails to render won't do better next time.
+	content, err := w.templates.Render(n.Kind, n.Locale, n.Data)
+	if err != nil {
+		log.Error("failed to render notification", slog.String("error", err.Error()))
+		w.retry(ctx, log, n, nil, err)
+		return
+	}
+
+	msg, err := Compose(w.from, n.Email, content, time.Now())
+	if err == nil {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = w.sender.Send(sendCtx, w.from, []string{n.Email}, msg)
+		cancel()
+	}
+
+	if err != nil {
+		var next *time.Time
+		if n.Attempts < w.maxAttempts {
+			at := time.Now().Add(backoff(n.Attempts))
+			next = &at
+		}
+		log.Warn("failed to send notification", slog.Int("attempt", n.Attempts), slog.String("error", err.Error()))
+		w.retry(ctx, log, n, next, err)
+		return
+	}
+
+	err = w.queue.CompleteNotification(ctx, n.ID)
+	if err != nil {
+		log.Error("failed to complete notification", slog.String("error", err.Error()))
+	}
+}
+
+func (w *Worker) retry(ctx context.Context, log *slog.Logger, n *models.Notification, next *time.Time, reason error) {
+	err := w.queue.RetryNotification(ctx, n.ID, next, reason.Error())
+	if err != nil {
+		log.Error("failed to reschedule notification", slog.String("error", err.Error()))
+	}
+}
+
+// backoff is the delay before the attempt following the given one.
+func backoff(attempts int) time.Duration {
+	d := retryDelay
+	for i := 1; i < attempts && d < retryDelayMax; i++ {
+		d *= 2
+	}
+	return min(d, retryDelayMax)
+}
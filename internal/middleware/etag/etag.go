@@ -0,0 +1,80 @@
+package etag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// New tags the successful responses to GET requests with an ETag made of
+// their body and answers 304 Not Modified without one when the client
+// already has it, as told by If-None-Match.
+func New(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if rec.status != 0 && rec.status != http.StatusOK {
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(rec.body.Bytes())
+		tag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", tag)
+
+		if matches(r.Header.Get("If-None-Match"), tag) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(rec.body.Bytes())
+	}
+	return http.HandlerFunc(fn)
+}
+
+// matches tells whether one of the comma separated entity tags of the
+// If-None-Match header is tag, compared weakly as the header requires.
+func matches(header string, tag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, el := range strings.Split(header, ",") {
+		el = strings.TrimSpace(el)
+		if el == "*" || strings.TrimPrefix(el, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder holds the response back until its tag is known.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
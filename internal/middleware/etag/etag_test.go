@@ -0,0 +1,141 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	body := `{"name":"tender"}`
+	h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(body))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenders", nil))
+	tag := rec.Header().Get("ETag")
+	if tag == "" {
+		t.Fatal("no ETag on a successful response")
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		ifNoneMatch string
+		status      int
+		body        string
+		tagged      bool
+	}{
+		{
+			name:   "tags the response",
+			method: http.MethodGet,
+			path:   "/tenders",
+			status: http.StatusOK,
+			body:   body,
+			tagged: true,
+		},
+		{
+			name:        "answers not modified to the same tag",
+			method:      http.MethodGet,
+			path:        "/tenders",
+			ifNoneMatch: tag,
+			status:      http.StatusNotModified,
+			tagged:      true,
+		},
+		{
+			name:        "compares tags weakly",
+			method:      http.MethodGet,
+			path:        "/tenders",
+			ifNoneMatch: `"other", W/` + tag,
+			status:      http.StatusNotModified,
+			tagged:      true,
+		},
+		{
+			name:        "answers not modified to any tag",
+			method:      http.MethodGet,
+			path:        "/tenders",
+			ifNoneMatch: "*",
+			status:      http.StatusNotModified,
+			tagged:      true,
+		},
+		{
+			name:        "sends the body for another tag",
+			method:      http.MethodGet,
+			path:        "/tenders",
+			ifNoneMatch: `"other"`,
+			status:      http.StatusOK,
+			body:        body,
+			tagged:      true,
+		},
+		{
+			name:        "passes errors through untagged",
+			method:      http.MethodGet,
+			path:        "/missing",
+			ifNoneMatch: tag,
+			status:      http.StatusNotFound,
+			body:        body,
+		},
+		{
+			name:        "passes writes through untagged",
+			method:      http.MethodPost,
+			path:        "/tenders",
+			ifNoneMatch: tag,
+			status:      http.StatusOK,
+			body:        body,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+			if rec.Body.String() != tt.body {
+				t.Errorf("body %q, want %q", rec.Body.String(), tt.body)
+			}
+			if got := rec.Header().Get("ETag"); (got == tag) != tt.tagged {
+				t.Errorf("ETag %q, want tagged %v", got, tt.tagged)
+			}
+			if tt.status == http.StatusNotModified && rec.Header().Get("Content-Type") != "" {
+				t.Error("Content-Type sent without a body")
+			}
+		})
+	}
+}
+
+func TestNewChangedBody(t *testing.T) {
+	body := "first"
+	h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tenders", nil))
+	tag := rec.Header().Get("ETag")
+
+	body = "second"
+	r := httptest.NewRequest(http.MethodGet, "/tenders", nil)
+	r.Header.Set("If-None-Match", tag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != body {
+		t.Errorf("status %d with body %q, want %d with %q", rec.Code, rec.Body.String(), http.StatusOK, body)
+	}
+	if rec.Header().Get("ETag") == tag {
+		t.Error("changed body kept the old ETag")
+	}
+}
@@ -0,0 +1,175 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// maxBodySize is the largest body any route accepts, that of the tender
+	// import
+	maxBodySize = 32 << 20
+)
+
+type KeyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (bool, error)
+	GetIdempotencyKey(ctx context.Context, key string) (*models.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+}
+
+// New replays the stored response for POST, PUT and PATCH requests retried
+// with the same Idempotency-Key. Requests without the header pass through.
+func New(ks KeyStore, ttl time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" || !mutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxKeyLength {
+				response.Render(w, r, response.ErrIdempotencyKeyTooLong)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+			if err != nil {
+				response.Render(w, r, response.ErrInvalidRequest)
+				return
+			}
+			if len(body) > maxBodySize {
+				response.Render(w, r, response.ErrBodyTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record := &models.IdempotencyKey{
+				Key:         key,
+				Method:      r.Method,
+				Path:        r.URL.Path,
+				Fingerprint: fingerprint(r, body),
+				ExpiresAt:   time.Now().Add(ttl),
+			}
+
+			reserved, err := ks.ReserveIdempotencyKey(r.Context(), record)
+			if err != nil {
+				response.Render(w, r, err)
+				return
+			}
+
+			if !reserved {
+				replay(w, r, ks, record)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			buf := &bytes.Buffer{}
+			ww.Tee(buf)
+
+			// the request context may already be done when the handler returns,
+			// the outcome must be recorded regardless
+			ctx := context.WithoutCancel(r.Context())
+			defer func() {
+				if rec := recover(); rec != nil {
+					release(ctx, ks, key)
+					panic(rec)
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			if !final(status) {
+				release(ctx, ks, key)
+				return
+			}
+
+			err = ks.CompleteIdempotencyKey(ctx, key, status, ww.Header().Get("Content-Type"), buf.Bytes())
+			if err != nil {
+				slog.Error("failed to store idempotent response", slog.String("key", key), slog.String("error", err.Error()))
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, ks KeyStore, record *models.IdempotencyKey) {
+	stored, err := ks.GetIdempotencyKey(r.Context(), record.Key)
+	if err != nil {
+		if errors.Is(err, response.ErrIdempotencyKeyNotExists) {
+			// released by a failed attempt in the meantime
+			err = response.ErrIdempotencyKeyInProgress
+		}
+		response.Render(w, r, err)
+		return
+	}
+
+	if stored.Fingerprint != record.Fingerprint {
+		response.Render(w, r, response.ErrIdempotencyKeyReused)
+		return
+	}
+
+	if !stored.Completed {
+		response.Render(w, r, response.ErrIdempotencyKeyInProgress)
+		return
+	}
+
+	if stored.ContentType != "" {
+		w.Header().Set("Content-Type", stored.ContentType)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	w.Write(stored.Body)
+}
+
+func release(ctx context.Context, ks KeyStore, key string) {
+	if err := ks.ReleaseIdempotencyKey(ctx, key); err != nil {
+		slog.Error("failed to release idempotency key", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	io.WriteString(h, "\n")
+	io.WriteString(h, r.URL.RequestURI())
+	io.WriteString(h, "\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// final tells whether a response with status is the outcome of the request
+// for good. Server errors, rate limiting, conflicts and timeouts may go
+// away on a retry, so they are not replayed.
+func final(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooEarly, http.StatusTooManyRequests, response.StatusClientClosedRequest:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+func mutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
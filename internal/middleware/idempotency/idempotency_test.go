@@ -0,0 +1,275 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+	"testing"
+	"time"
+)
+
+// memoryStore keeps the keys in a map, as the database does in a table.
+type memoryStore struct {
+	mu   sync.Mutex
+	keys map[string]models.IdempotencyKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[string]models.IdempotencyKey)}
+}
+
+func (s *memoryStore) ReserveIdempotencyKey(_ context.Context, key *models.IdempotencyKey) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key.Key]; ok {
+		return false, nil
+	}
+	s.keys[key.Key] = *key
+	return true, nil
+}
+
+func (s *memoryStore) GetIdempotencyKey(_ context.Context, key string) (*models.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.keys[key]
+	if !ok {
+		return nil, response.ErrIdempotencyKeyNotExists
+	}
+	return &stored, nil
+}
+
+func (s *memoryStore) CompleteIdempotencyKey(_ context.Context, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.keys[key]
+	stored.Completed = true
+	stored.StatusCode = statusCode
+	stored.ContentType = contentType
+	stored.Body = body
+	s.keys[key] = stored
+	return nil
+}
+
+func (s *memoryStore) ReleaseIdempotencyKey(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}
+
+type request struct {
+	method string
+	key    string
+	body   string
+}
+
+func (r request) send(h http.Handler) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(r.method, "/api/tenders/new", strings.NewReader(r.body))
+	if r.key != "" {
+		req.Header.Set(Header, r.key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNew(t *testing.T) {
+	post := request{method: http.MethodPost, key: "key", body: `{"name":"tender"}`}
+
+	tests := []struct {
+		name string
+		// status is what the handler answers
+		status   int
+		requests []request
+		// want are the statuses of the responses, in order
+		want []int
+		// calls is how many requests reach the handler
+		calls    int
+		replayed bool
+	}{
+		{
+			name:     "replays the stored response",
+			status:   http.StatusCreated,
+			requests: []request{post, post},
+			want:     []int{http.StatusCreated, http.StatusCreated},
+			calls:    1,
+			replayed: true,
+		},
+		{
+			name:     "replays a client error",
+			status:   http.StatusBadRequest,
+			requests: []request{post, post},
+			want:     []int{http.StatusBadRequest, http.StatusBadRequest},
+			calls:    1,
+			replayed: true,
+		},
+		{
+			name:     "rejects the key reused with another body",
+			status:   http.StatusCreated,
+			requests: []request{post, {method: http.MethodPost, key: "key", body: `{"name":"other"}`}},
+			want:     []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			calls:    1,
+		},
+		{
+			name:     "rejects the key reused with another method",
+			status:   http.StatusCreated,
+			requests: []request{post, {method: http.MethodPut, key: "key", body: post.body}},
+			want:     []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			calls:    1,
+		},
+		{
+			name:     "retries a server error",
+			status:   http.StatusInternalServerError,
+			requests: []request{post, post},
+			want:     []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			calls:    2,
+		},
+		{
+			name:     "retries a conflict",
+			status:   http.StatusConflict,
+			requests: []request{post, post},
+			want:     []int{http.StatusConflict, http.StatusConflict},
+			calls:    2,
+		},
+		{
+			name:     "retries a rate limited request",
+			status:   http.StatusTooManyRequests,
+			requests: []request{post, post},
+			want:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests},
+			calls:    2,
+		},
+		{
+			name:     "retries a canceled request",
+			status:   response.StatusClientClosedRequest,
+			requests: []request{post, post},
+			want:     []int{response.StatusClientClosedRequest, response.StatusClientClosedRequest},
+			calls:    2,
+		},
+		{
+			name:     "passes requests without the key through",
+			status:   http.StatusCreated,
+			requests: []request{{method: http.MethodPost, body: post.body}, {method: http.MethodPost, body: post.body}},
+			want:     []int{http.StatusCreated, http.StatusCreated},
+			calls:    2,
+		},
+		{
+			name:     "passes reads through",
+			status:   http.StatusOK,
+			requests: []request{{method: http.MethodGet, key: "key"}, {method: http.MethodGet, key: "key"}},
+			want:     []int{http.StatusOK, http.StatusOK},
+			calls:    2,
+		},
+		{
+			name:     "rejects a too long key",
+			status:   http.StatusCreated,
+			requests: []request{{method: http.MethodPost, key: strings.Repeat("k", maxKeyLength+1), body: post.body}},
+			want:     []int{http.StatusBadRequest},
+		},
+		{
+			name:     "rejects a too large body",
+			status:   http.StatusCreated,
+			requests: []request{{method: http.MethodPost, key: "key", body: strings.Repeat(" ", maxBodySize+1)}},
+			want:     []int{http.StatusRequestEntityTooLarge},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			h := New(newMemoryStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"calls":` + strconv.Itoa(calls) + `}`))
+			}))
+
+			var first, last *httptest.ResponseRecorder
+			for i, req := range tt.requests {
+				rec := req.send(h)
+				if rec.Code != tt.want[i] {
+					t.Fatalf("request %d: status %d, want %d", i+1, rec.Code, tt.want[i])
+				}
+				if first == nil {
+					first = rec
+				}
+				last = rec
+			}
+
+			if calls != tt.calls {
+				t.Errorf("handler called %d times, want %d", calls, tt.calls)
+			}
+			if replayed := last.Header().Get(ReplayedHeader) == "true"; replayed != tt.replayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.replayed)
+			}
+			if tt.replayed {
+				if last.Body.String() != first.Body.String() {
+					t.Errorf("replayed body %q, want %q", last.Body.String(), first.Body.String())
+				}
+				if got := last.Header().Get("Content-Type"); got != "application/json" {
+					t.Errorf("replayed Content-Type %q, want application/json", got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewInProgress(t *testing.T) {
+	ks := newMemoryStore()
+	post := request{method: http.MethodPost, key: "key", body: `{"name":"tender"}`}
+
+	var retry *httptest.ResponseRecorder
+	h := New(ks, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the retry comes while the first attempt is still running
+		if retry == nil {
+			retry = post.send(New(ks, time.Hour)(http.NotFoundHandler()))
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	if rec := post.send(h); rec.Code != http.StatusCreated {
+		t.Fatalf("first attempt: status %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if retry.Code != http.StatusConflict {
+		t.Errorf("retry in progress: status %d, want %d", retry.Code, http.StatusConflict)
+	}
+	if rec := post.send(h); rec.Code != http.StatusCreated || rec.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("retry after completion: status %d, replayed %q, want a replayed %d",
+			rec.Code, rec.Header().Get(ReplayedHeader), http.StatusCreated)
+	}
+}
+
+func TestFinal(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusCreated, true},
+		{http.StatusBadRequest, true},
+		{http.StatusForbidden, true},
+		{http.StatusNotFound, true},
+		{http.StatusUnprocessableEntity, true},
+		{http.StatusRequestTimeout, false},
+		{http.StatusConflict, false},
+		{http.StatusTooEarly, false},
+		{http.StatusTooManyRequests, false},
+		{response.StatusClientClosedRequest, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusGatewayTimeout, false},
+	}
+
+	for _, tt := range tests {
+		if got := final(tt.status); got != tt.want {
+			t.Errorf("final(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
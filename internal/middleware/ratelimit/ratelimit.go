@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/lib/ratelimit"
+	"tender_service/internal/lib/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	KeyIP           = "ip"
+	KeyUser         = "user"
+	KeyOrganization = "organization"
+)
+
+// KeyFunc returns the identity a request is counted against.
+type KeyFunc func(r *http.Request) string
+
+// Resolver tells who a request is made by.
+type Resolver interface {
+	UserExists(ctx context.Context, userName string) (bool, error)
+	ActingOrganization(ctx context.Context, userName string, orgID uuid.UUID) (uuid.UUID, error)
+}
+
+// New limits requests of a route group with a token bucket per key.
+func New(l ratelimit.Limiter, group string, limit ratelimit.Limit, key KeyFunc) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			res, err := l.Allow(r.Context(), group+":"+key(r), limit)
+			if err != nil {
+				// an unavailable backend must not take the API down with it
+				slog.Error("rate limiter failed", slog.String("group", group), slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(res.Reset))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", seconds(res.RetryAfter))
+				response.Render(w, r, response.ErrTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Key returns the KeyFunc for one of KeyIP, KeyUser or KeyOrganization.
+func Key(kind string, res Resolver) KeyFunc {
+	switch kind {
+	case KeyUser:
+		return ByUser(res)
+	case KeyOrganization:
+		return ByOrganization(res)
+	default:
+		return ByIP()
+	}
+}
+
+func ByIP() KeyFunc {
+	return func(r *http.Request) string {
+		return KeyIP + ":" + clientIP(r)
+	}
+}
+
+// ByUser counts requests per username, whatever client they come from.
+// Unknown usernames are counted per IP so that making them up gives no
+// fresh buckets.
+func ByUser(res Resolver) KeyFunc {
+	return func(r *http.Request) string {
+		username := username(r)
+		if username == "" {
+			return KeyIP + ":" + clientIP(r)
+		}
+
+		exists, err := res.UserExists(r.Context(), username)
+		if err != nil || !exists {
+			return KeyIP + ":" + clientIP(r)
+		}
+		return KeyUser + ":" + username
+	}
+}
+
+// ByOrganization counts requests per acting organization of the user,
+// falling back to ByUser when it can't be resolved.
+func ByOrganization(res Resolver) KeyFunc {
+	byUser := ByUser(res)
+	return func(r *http.Request) string {
+		username := username(r)
+		if username == "" {
+			return byUser(r)
+		}
+
+		orgID, err := actingorg.FromRequest(r)
+		if err != nil {
+			return byUser(r)
+		}
+
+		orgID, err = res.ActingOrganization(r.Context(), username, orgID)
+		if err != nil {
+			return byUser(r)
+		}
+
+		return KeyOrganization + ":" + orgID.String()
+	}
+}
+
+func username(r *http.Request) string {
+	query := r.URL.Query()
+	if username := query.Get("username"); username != "" {
+		return username
+	}
+	return query.Get("requesterUsername")
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
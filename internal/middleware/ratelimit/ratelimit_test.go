@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"tender_service/internal/lib/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resolver struct {
+	users map[string]bool
+	orgID uuid.UUID
+}
+
+func (r resolver) UserExists(_ context.Context, userName string) (bool, error) {
+	return r.users[userName], nil
+}
+
+func (r resolver) ActingOrganization(_ context.Context, _ string, _ uuid.UUID) (uuid.UUID, error) {
+	if r.orgID == uuid.Nil {
+		return uuid.Nil, errors.New("no organization")
+	}
+	return r.orgID, nil
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("unavailable")
+}
+
+func TestNew(t *testing.T) {
+	h := New(ratelimit.NewMemory(), "tenders", ratelimit.Limit{Rate: 1, Burst: 2}, ByIP())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		status     int
+		remaining  string
+		retryAfter string
+	}{
+		{http.StatusOK, "1", ""},
+		{http.StatusOK, "0", ""},
+		{http.StatusTooManyRequests, "0", "1"},
+	}
+
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/tenders", nil))
+
+		if rec.Code != tt.status {
+			t.Errorf("request %d: status %d, want %d", i+1, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: RateLimit-Limit %q, want 2", i+1, got)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != tt.remaining {
+			t.Errorf("request %d: RateLimit-Remaining %q, want %q", i+1, got, tt.remaining)
+		}
+		if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+			t.Errorf("request %d: Retry-After %q, want %q", i+1, got, tt.retryAfter)
+		}
+	}
+}
+
+func TestNewFailingLimiter(t *testing.T) {
+	h := New(failingLimiter{}, "tenders", ratelimit.Limit{Rate: 1, Burst: 1}, ByIP())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/tenders", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestKey(t *testing.T) {
+	orgID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name   string
+		kind   string
+		res    resolver
+		target string
+		want   string
+	}{
+		{
+			name:   "ip",
+			kind:   KeyIP,
+			target: "/api/tenders?username=alice",
+			want:   "ip:192.0.2.1",
+		},
+		{
+			name:   "known user",
+			kind:   KeyUser,
+			res:    resolver{users: map[string]bool{"alice": true}},
+			target: "/api/tenders/my?username=alice",
+			want:   "user:alice",
+		},
+		{
+			name:   "requester username",
+			kind:   KeyUser,
+			res:    resolver{users: map[string]bool{"alice": true}},
+			target: "/api/bids/reviews?requesterUsername=alice",
+			want:   "user:alice",
+		},
+		{
+			name:   "unknown user",
+			kind:   KeyUser,
+			res:    resolver{users: map[string]bool{"alice": true}},
+			target: "/api/tenders/my?username=mallory",
+			want:   "ip:192.0.2.1",
+		},
+		{
+			name:   "anonymous user",
+			kind:   KeyUser,
+			target: "/api/tenders",
+			want:   "ip:192.0.2.1",
+		},
+		{
+			name:   "organization",
+			kind:   KeyOrganization,
+			res:    resolver{users: map[string]bool{"alice": true}, orgID: orgID},
+			target: "/api/tenders/new?username=alice",
+			want:   "organization:" + orgID.String(),
+		},
+		{
+			name:   "unresolved organization",
+			kind:   KeyOrganization,
+			res:    resolver{users: map[string]bool{"alice": true}},
+			target: "/api/tenders/new?username=alice",
+			want:   "user:alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			if got := Key(tt.kind, tt.res)(r); got != tt.want {
+				t.Errorf("key %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0"},
+		{time.Millisecond, "1"},
+		{time.Second, "1"},
+		{1500 * time.Millisecond, "2"},
+	}
+
+	for _, tt := range tests {
+		if got := seconds(tt.d); got != tt.want {
+			t.Errorf("seconds(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
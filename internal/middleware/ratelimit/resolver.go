@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"tender_service/internal/lib/cache"
+	"tender_service/internal/lib/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errUnresolved is returned for an organization the request was already
+// found not to act on behalf of.
+var errUnresolved = errors.New("organization not resolved")
+
+type cachedResolver struct {
+	res   Resolver
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// Cached keeps what res resolves the requests to for ttl, unknown users and
+// unresolved organizations included, so that a client hammering a route
+// costs no database query per request.
+func Cached(res Resolver, c cache.Cache, ttl time.Duration) Resolver {
+	return cachedResolver{res: res, cache: c, ttl: ttl}
+}
+
+func (r cachedResolver) UserExists(ctx context.Context, userName string) (bool, error) {
+	key := "ratelimit:user:" + userName
+	if value, found := r.get(ctx, key); found {
+		return len(value) > 0, nil
+	}
+
+	exists, err := r.res.UserExists(ctx, userName)
+	if err != nil {
+		return false, err
+	}
+
+	var value []byte
+	if exists {
+		value = []byte{1}
+	}
+	r.set(ctx, key, value)
+	return exists, nil
+}
+
+func (r cachedResolver) ActingOrganization(ctx context.Context, userName string, orgID uuid.UUID) (uuid.UUID, error) {
+	key := "ratelimit:organization:" + userName + ":" + orgID.String()
+	if value, found := r.get(ctx, key); found {
+		if len(value) == 0 {
+			return uuid.Nil, errUnresolved
+		}
+		return uuid.FromBytes(value)
+	}
+
+	resolved, err := r.res.ActingOrganization(ctx, userName, orgID)
+	if err != nil {
+		// only the answers about the user are kept, failures may pass
+		if response.Status(err) < 500 {
+			r.set(ctx, key, nil)
+		}
+		return uuid.Nil, err
+	}
+
+	r.set(ctx, key, resolved[:])
+	return resolved, nil
+}
+
+func (r cachedResolver) get(ctx context.Context, key string) ([]byte, bool) {
+	value, found, err := r.cache.Get(ctx, key)
+	if err != nil {
+		slog.Error("failed to read cache", slog.String("key", key), slog.String("error", err.Error()))
+		return nil, false
+	}
+	return value, found
+}
+
+func (r cachedResolver) set(ctx context.Context, key string, value []byte) {
+	if value == nil {
+		value = []byte{}
+	}
+	if err := r.cache.Set(ctx, key, value, r.ttl); err != nil {
+		slog.Error("failed to write cache", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
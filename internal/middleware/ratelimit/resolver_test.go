@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"tender_service/internal/lib/cache"
+	"tender_service/internal/lib/response"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// countingResolver counts the lookups reaching the database.
+type countingResolver struct {
+	users map[string]bool
+	orgID uuid.UUID
+	err   error
+	// orgErr fails ActingOrganization alone
+	orgErr error
+	calls  int
+}
+
+func (r *countingResolver) UserExists(_ context.Context, userName string) (bool, error) {
+	r.calls++
+	return r.users[userName], r.err
+}
+
+func (r *countingResolver) ActingOrganization(context.Context, string, uuid.UUID) (uuid.UUID, error) {
+	r.calls++
+	if r.err != nil {
+		return uuid.Nil, r.err
+	}
+	if r.orgErr != nil {
+		return uuid.Nil, r.orgErr
+	}
+	return r.orgID, nil
+}
+
+func TestCached(t *testing.T) {
+	orgID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name  string
+		res   *countingResolver
+		kind  string
+		want  string
+		calls int
+	}{
+		{
+			name:  "known user",
+			res:   &countingResolver{users: map[string]bool{"alice": true}},
+			kind:  KeyUser,
+			want:  "user:alice",
+			calls: 1,
+		},
+		{
+			name:  "unknown user",
+			res:   &countingResolver{},
+			kind:  KeyUser,
+			want:  "ip:192.0.2.1",
+			calls: 1,
+		},
+		{
+			name:  "failing lookup",
+			res:   &countingResolver{err: errors.New("connection refused")},
+			kind:  KeyUser,
+			want:  "ip:192.0.2.1",
+			calls: 3,
+		},
+		{
+			name:  "organization",
+			res:   &countingResolver{orgID: orgID},
+			kind:  KeyOrganization,
+			want:  "organization:" + orgID.String(),
+			calls: 1,
+		},
+		{
+			name:  "unresolved organization",
+			res:   &countingResolver{users: map[string]bool{"alice": true}, orgErr: response.ErrOrganizationAmbiguous},
+			kind:  KeyOrganization,
+			want:  "user:alice",
+			calls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := Key(tt.kind, Cached(tt.res, cache.NewLRU(100), time.Minute))
+
+			for i := 0; i < 3; i++ {
+				r := httptest.NewRequest(http.MethodGet, "/api/tenders/my?username=alice", nil)
+				r.RemoteAddr = "192.0.2.1:1234"
+				if got := key(r); got != tt.want {
+					t.Fatalf("request %d: key %q, want %q", i+1, got, tt.want)
+				}
+			}
+
+			if tt.res.calls != tt.calls {
+				t.Errorf("%d lookups, want %d", tt.res.calls, tt.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package timeout
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// New sets a deadline on the request context, so storage queries started by
+// the handler are canceled once it passes. Zero or negative d disables it.
+func New(d time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if d <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Connection gives the connection of a request the deadline set by New in
+// place of the server's read and write timeouts, for routes that take
+// longer than those. The response may be written until grace after the
+// deadline. Requests without a deadline keep the server's timeouts.
+func Connection(grace time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if deadline, ok := r.Context().Deadline(); ok {
+				rc := http.NewResponseController(w)
+				_ = rc.SetReadDeadline(deadline)
+				_ = rc.SetWriteDeadline(deadline.Add(grace))
+			}
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,46 @@
+package timeout
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConnection(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		ok      bool
+	}{
+		{name: "cut at the server timeout", handler: New(time.Second)(slow), ok: false},
+		{name: "lives until the deadline", handler: New(time.Second)(Connection(time.Second)(slow)), ok: true},
+		{name: "keeps the server timeout without a deadline", handler: Connection(time.Second)(slow), ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewUnstartedServer(tt.handler)
+			srv.Config.WriteTimeout = 20 * time.Millisecond
+			srv.Start()
+			defer srv.Close()
+
+			res, err := srv.Client().Get(srv.URL)
+			var body []byte
+			if err == nil {
+				body, err = io.ReadAll(res.Body)
+				res.Body.Close()
+			}
+
+			if ok := err == nil && string(body) == "done"; ok != tt.ok {
+				t.Errorf("response %q with error %v, want complete %v", body, err, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"tender_service/internal/storage/models"
+)
+
+// Relation is what links a user to a bid.
+type Relation string
+
+const (
+	// Author is the employee who created the bid.
+	Author Relation = "author"
+	// AuthorOrganization is any other responsible of the bid's organization.
+	AuthorOrganization Relation = "author_organization"
+	// TenderOrganization is a responsible of the organization owning the tender.
+	TenderOrganization Relation = "tender_organization"
+	// Competitor is anyone else, including authors of other bids.
+	Competitor Relation = "competitor"
+)
+
+type Action string
+
+const (
+	ReadBidStatus      Action = "read_status"
+	ReadBidContents    Action = "read_contents"
+	EditBidContents    Action = "edit"
+	RollbackBidVersion Action = "rollback"
+	ChangeBidStatus    Action = "change_status"
+	DecideBid          Action = "decide"
+	FeedbackBid        Action = "feedback"
+	// DeleteBid covers both deleting the bid and restoring it
+	DeleteBid Action = "delete"
+	// PostBidMessage is posting to the bid's feedback thread
+	PostBidMessage Action = "post_message"
+	// WithdrawBid pulls a published bid back before the decision
+	WithdrawBid Action = "withdraw"
+	// ResubmitBid publishes a withdrawn bid again
+	ResubmitBid Action = "resubmit"
+	// OfferPrice is making an offer in the auction round of the tender
+	OfferPrice Action = "offer"
+)
+
+// Bid is the state of a bid and its tender that rules depend on.
+type Bid struct {
+	Status models.BidStatus
+	// Sealed is set while a sealed-bid tender is still accepting bids.
+	Sealed bool
+	// SubmissionClosed is set once the tender's submission deadline has passed.
+	SubmissionClosed bool
+	// TenderClosed is set once the tender no longer accepts bids.
+	TenderClosed bool
+	// LotClosed is set once the bid's lot is decided or cancelled.
+	LotClosed bool
+	// AuctionPending is set until the auction round of the tender is over.
+	AuctionPending bool
+}
+
+// authorStatuses are the statuses the bid's side sets itself, a published
+// bid is taken back by withdrawing it.
+var authorStatuses = []models.BidStatus{models.BidCreated, models.BidPublished}
+
+// TenderOrganizationStatuses are the statuses of bids the tender owner sees.
+var TenderOrganizationStatuses = []models.BidStatus{models.BidPublished, models.BidApproved, models.BidRejected, models.BidWithdrawn}
+
+// CanBid tells whether a user related to the bid by rel may perform action.
+func CanBid(rel Relation, action Action, bid Bid) bool {
+	switch rel {
+	case Author, AuthorOrganization:
+		switch action {
+		case ReadBidStatus, ReadBidContents:
+			return true
+		case EditBidContents, RollbackBidVersion:
+			return has(authorStatuses, bid.Status) && bid.open()
+		case ChangeBidStatus:
+			return bid.Status == models.BidCreated && bid.open()
+		case DeleteBid:
+			return bid.Status != models.BidApproved
+		case PostBidMessage:
+			return has(TenderOrganizationStatuses, bid.Status)
+		case WithdrawBid:
+			return bid.Status == models.BidPublished && !bid.TenderClosed && !bid.LotClosed
+		case ResubmitBid:
+			return bid.Status == models.BidWithdrawn && bid.open()
+		case OfferPrice:
+			return bid.Status == models.BidPublished && !bid.TenderClosed
+		}
+	case TenderOrganization:
+		if !has(TenderOrganizationStatuses, bid.Status) {
+			return false
+		}
+		switch action {
+		case ReadBidStatus:
+			return true
+		case ReadBidContents, FeedbackBid, PostBidMessage:
+			return !bid.Sealed
+		case DecideBid:
+			return bid.Status == models.BidPublished && !bid.Sealed && !bid.TenderClosed && !bid.LotClosed && !bid.AuctionPending
+		}
+	}
+	return false
+}
+
+// AuthorStatus tells whether the bid's side may set status itself.
+func AuthorStatus(status models.BidStatus) bool {
+	return has(authorStatuses, status)
+}
+
+// open tells whether the bid may still be changed by its side.
+func (b Bid) open() bool {
+	return !b.SubmissionClosed && !b.TenderClosed && !b.LotClosed
+}
+
+// Ranked tells whether a bid in status takes part in the auction ranking.
+func Ranked(status models.BidStatus) bool {
+	return status == models.BidPublished || status == models.BidApproved || status == models.BidRejected
+}
+
+func has(statuses []models.BidStatus, status models.BidStatus) bool {
+	for _, el := range statuses {
+		if el == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"tender_service/internal/storage/models"
+	"testing"
+)
+
+func TestCanBid(t *testing.T) {
+	published := Bid{Status: models.BidPublished}
+
+	tests := []struct {
+		name   string
+		rel    Relation
+		action Action
+		bid    Bid
+		want   bool
+	}{
+		{"author reads a created bid", Author, ReadBidContents, Bid{Status: models.BidCreated}, true},
+		{"author edits a created bid", Author, EditBidContents, Bid{Status: models.BidCreated}, true},
+		{"author edits a published bid", AuthorOrganization, EditBidContents, published, true},
+		{"author edits a decided bid", Author, EditBidContents, Bid{Status: models.BidApproved}, false},
+		{"author edits after the deadline", Author, EditBidContents, Bid{Status: models.BidCreated, SubmissionClosed: true}, false},
+		{"author rolls back in a closed lot", Author, RollbackBidVersion, Bid{Status: models.BidPublished, LotClosed: true}, false},
+		{"author publishes a created bid", Author, ChangeBidStatus, Bid{Status: models.BidCreated}, true},
+		{"author changes a published bid", Author, ChangeBidStatus, published, false},
+		{"author deletes a rejected bid", Author, DeleteBid, Bid{Status: models.BidRejected}, true},
+		{"author deletes an approved bid", Author, DeleteBid, Bid{Status: models.BidApproved}, false},
+		{"author withdraws a published bid", Author, WithdrawBid, published, true},
+		{"author withdraws after the deadline", Author, WithdrawBid, Bid{Status: models.BidPublished, SubmissionClosed: true}, true},
+		{"author withdraws from a closed tender", Author, WithdrawBid, Bid{Status: models.BidPublished, TenderClosed: true}, false},
+		{"author withdraws a created bid", Author, WithdrawBid, Bid{Status: models.BidCreated}, false},
+		{"author resubmits a withdrawn bid", Author, ResubmitBid, Bid{Status: models.BidWithdrawn}, true},
+		{"author resubmits after the deadline", Author, ResubmitBid, Bid{Status: models.BidWithdrawn, SubmissionClosed: true}, false},
+		{"author offers on a published bid", Author, OfferPrice, published, true},
+		{"author offers on a created bid", Author, OfferPrice, Bid{Status: models.BidCreated}, false},
+		{"author decides", Author, DecideBid, published, false},
+		{"author posts on a created bid", Author, PostBidMessage, Bid{Status: models.BidCreated}, false},
+		{"author posts on a published bid", Author, PostBidMessage, published, true},
+		{"owner reads the status of a published bid", TenderOrganization, ReadBidStatus, published, true},
+		{"owner reads a created bid", TenderOrganization, ReadBidStatus, Bid{Status: models.BidCreated}, false},
+		{"owner reads a canceled bid", TenderOrganization, ReadBidStatus, Bid{Status: models.BidCanceled}, false},
+		{"owner reads a published bid", TenderOrganization, ReadBidContents, published, true},
+		{"owner reads a sealed bid", TenderOrganization, ReadBidContents, Bid{Status: models.BidPublished, Sealed: true}, false},
+		{"owner reads the status of a sealed bid", TenderOrganization, ReadBidStatus, Bid{Status: models.BidPublished, Sealed: true}, true},
+		{"owner decides a published bid", TenderOrganization, DecideBid, published, true},
+		{"owner decides a sealed bid", TenderOrganization, DecideBid, Bid{Status: models.BidPublished, Sealed: true}, false},
+		{"owner decides in a closed tender", TenderOrganization, DecideBid, Bid{Status: models.BidPublished, TenderClosed: true}, false},
+		{"owner decides in a closed lot", TenderOrganization, DecideBid, Bid{Status: models.BidPublished, LotClosed: true}, false},
+		{"owner decides during the auction", TenderOrganization, DecideBid, Bid{Status: models.BidPublished, AuctionPending: true}, false},
+		{"owner decides a decided bid", TenderOrganization, DecideBid, Bid{Status: models.BidRejected}, false},
+		{"owner decides a withdrawn bid", TenderOrganization, DecideBid, Bid{Status: models.BidWithdrawn}, false},
+		{"owner leaves feedback", TenderOrganization, FeedbackBid, Bid{Status: models.BidApproved}, true},
+		{"owner edits", TenderOrganization, EditBidContents, published, false},
+		{"competitor reads the status", Competitor, ReadBidStatus, published, false},
+		{"competitor reads", Competitor, ReadBidContents, Bid{Status: models.BidApproved}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanBid(tt.rel, tt.action, tt.bid); got != tt.want {
+				t.Errorf("CanBid(%s, %s, %+v) = %v, want %v", tt.rel, tt.action, tt.bid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		role models.OrganizationRole
+		perm Permission
+		want bool
+	}{
+		{models.ViewerRole, CreateTender, false},
+		{models.ViewerRole, CreateBid, false},
+		{models.EditorRole, CreateTender, true},
+		{models.EditorRole, EditBid, true},
+		{models.EditorRole, PublishTender, false},
+		{models.EditorRole, DecideOnBid, false},
+		{models.ApproverRole, PublishTender, true},
+		{models.ApproverRole, DecideOnBid, true},
+		{models.ApproverRole, EditTender, true},
+		{models.ApproverRole, ManageMembers, false},
+		{models.AdminRole, ManageMembers, true},
+		{models.AdminRole, DeleteTender, true},
+		{models.AdminRole, CreateBid, true},
+		{"", CreateTender, false},
+	}
+
+	for _, tt := range tests {
+		if got := Allows(tt.role, tt.perm); got != tt.want {
+			t.Errorf("Allows(%q, %s) = %v, want %v", tt.role, tt.perm, got, tt.want)
+		}
+	}
+}
+
+func TestBidPermission(t *testing.T) {
+	tests := []struct {
+		rel    Relation
+		action Action
+		want   Permission
+		ok     bool
+	}{
+		{Author, ReadBidContents, "", false},
+		{Author, EditBidContents, EditBid, true},
+		{Author, WithdrawBid, EditBid, true},
+		{Author, OfferPrice, EditBid, true},
+		{Author, RollbackBidVersion, RollbackBid, true},
+		{Author, PostBidMessage, EditBid, true},
+		{TenderOrganization, PostBidMessage, LeaveFeedback, true},
+		{TenderOrganization, DecideBid, DecideOnBid, true},
+		{TenderOrganization, FeedbackBid, LeaveFeedback, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := BidPermission(tt.rel, tt.action)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("BidPermission(%s, %s) = %q, %v, want %q, %v", tt.rel, tt.action, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTenderStatusPermission(t *testing.T) {
+	tests := []struct {
+		status models.TenderStatus
+		want   Permission
+	}{
+		{models.TenderCreated, EditTender},
+		{models.TenderPublished, PublishTender},
+		{models.TenderClosed, PublishTender},
+	}
+
+	for _, tt := range tests {
+		if got := TenderStatusPermission(tt.status); got != tt.want {
+			t.Errorf("TenderStatusPermission(%s) = %s, want %s", tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"tender_service/internal/storage/models"
+)
+
+// Permission is an action a member of an organization may be allowed to
+// perform on behalf of it.
+type Permission string
+
+const (
+	CreateTender      Permission = "tender.create"
+	EditTender        Permission = "tender.edit"
+	PublishTender     Permission = "tender.publish"
+	RollbackTender    Permission = "tender.rollback"
+	DeleteTender      Permission = "tender.delete"
+	ManageInvitations Permission = "tender.invitations"
+	CreateBid         Permission = "bid.create"
+	EditBid           Permission = "bid.edit"
+	RollbackBid       Permission = "bid.rollback"
+	DecideOnBid       Permission = "bid.decision"
+	LeaveFeedback     Permission = "bid.feedback"
+	ManageMembers     Permission = "organization.members"
+)
+
+var editorPermissions = []Permission{
+	CreateTender, EditTender, RollbackTender, ManageInvitations,
+	CreateBid, EditBid, RollbackBid,
+}
+
+var approverPermissions = append([]Permission{PublishTender, DeleteTender, DecideOnBid, LeaveFeedback}, editorPermissions...)
+
+var rolePermissions = map[models.OrganizationRole][]Permission{
+	models.ViewerRole:   nil,
+	models.EditorRole:   editorPermissions,
+	models.ApproverRole: approverPermissions,
+	models.AdminRole:    append([]Permission{ManageMembers}, approverPermissions...),
+}
+
+// Allows tells whether role grants perm.
+func Allows(role models.OrganizationRole, perm Permission) bool {
+	for _, el := range rolePermissions[role] {
+		if el == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// TenderStatusPermission is the permission needed to move a tender to status.
+func TenderStatusPermission(status models.TenderStatus) Permission {
+	if status == models.TenderPublished || status == models.TenderClosed {
+		return PublishTender
+	}
+	return EditTender
+}
+
+// BidPermission is the permission an action on a bid needs in the acting
+// organization of a user related to the bid by rel. Reading needs none.
+func BidPermission(rel Relation, action Action) (Permission, bool) {
+	switch action {
+	case PostBidMessage:
+		if rel == TenderOrganization {
+			return LeaveFeedback, true
+		}
+		return EditBid, true
+	case EditBidContents, ChangeBidStatus, DeleteBid, WithdrawBid, ResubmitBid, OfferPrice:
+		return EditBid, true
+	case RollbackBidVersion:
+		return RollbackBid, true
+	case DecideBid:
+		return DecideOnBid, true
+	case FeedbackBid:
+		return LeaveFeedback, true
+	}
+	return "", false
+}
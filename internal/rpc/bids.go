@@ -0,0 +1,282 @@
+package rpc
+
+import (
+	"context"
+	"tender_service/internal/rpc/tenderpb"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type bidService struct {
+	tenderpb.UnimplementedBidServiceServer
+	svc *service.Service
+}
+
+// decisions are the bid statuses the decisions stand for.
+var decisions = map[tenderpb.Decision]models.BidStatus{
+	tenderpb.Decision_DECISION_APPROVED: models.BidApproved,
+	tenderpb.Decision_DECISION_REJECTED: models.BidRejected,
+}
+
+// callerActor returns the caller and the organization they act on behalf of.
+func callerActor(ctx context.Context) (service.Actor, error) {
+	username, err := caller(ctx)
+	if err != nil {
+		return service.Actor{}, err
+	}
+	orgID, err := actingOrganization(ctx)
+	if err != nil {
+		return service.Actor{}, err
+	}
+	return service.Actor{Username: username, OrganizationID: orgID}, nil
+}
+
+func (s *bidService) CreateBid(ctx context.Context, in *tenderpb.CreateBidRequest) (*tenderpb.Bid, error) {
+	actor, err := callerActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenderID, err := parseID("tenderId", in.GetTenderId())
+	if err != nil {
+		return nil, err
+	}
+	// The author is the caller rather than an id the client may pick.
+	bid, err := s.svc.SubmitBid(ctx, service.NewBid{
+		TenderID:       tenderID,
+		Name:           in.GetName(),
+		Description:    in.GetDescription(),
+		AuthorType:     models.BidAuthorType(in.GetAuthorType()),
+		AuthorUsername: actor.Username,
+		OrganizationID: actor.OrganizationID,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return bidOf(bid), nil
+}
+
+func (s *bidService) ListBids(in *tenderpb.ListBidsRequest, stream tenderpb.BidService_ListBidsServer) error {
+	actor, err := callerActor(stream.Context())
+	if err != nil {
+		return err
+	}
+	tenderID, err := parseID("tenderId", in.GetTenderId())
+	if err != nil {
+		return err
+	}
+
+	var p service.Page
+	p.Limit, p.Offset = page(in.GetPage())
+
+	err = s.svc.Bids(stream.Context(), actor, tenderID, p, func(el service.Bid) error {
+		return stream.Send(bidOf(el))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *bidService) ListMyBids(in *tenderpb.Page, stream tenderpb.BidService_ListMyBidsServer) error {
+	username, err := caller(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	var p service.Page
+	p.Limit, p.Offset = page(in)
+
+	err = s.svc.MyBids(stream.Context(), username, p, func(el service.Bid) error {
+		return stream.Send(bidOf(el))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *bidService) GetBidStatus(ctx context.Context, in *tenderpb.BidRef) (*tenderpb.StatusReply, error) {
+	actor, err := callerActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bidID, err := parseID("bidId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.svc.BidStatus(ctx, actor, bidID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &tenderpb.StatusReply{Status: string(status)}, nil
+}
+
+func (s *bidService) SetBidStatus(ctx context.Context, in *tenderpb.SetStatusRequest) (*tenderpb.Bid, error) {
+	actor, err := callerActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bidID, err := parseID("bidId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.svc.SetBidStatus(ctx, actor, bidID, models.BidStatus(in.GetStatus()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return bidOf(bid), nil
+}
+
+func (s *bidService) EditBid(ctx context.Context, in *tenderpb.EditBidRequest) (*tenderpb.Bid, error) {
+	actor, err := callerActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bidID, err := parseID("bidId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.svc.EditBid(ctx, actor, service.BidPatch{
+		BidID:       bidID,
+		Name:        in.GetName(),
+		Description: in.GetDescription(),
+		Status:      models.BidStatus(in.GetStatus()),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return bidOf(bid), nil
+}
+
+func (s *bidService) SubmitDecision(ctx context.Context, in *tenderpb.SubmitDecisionRequest) (*tenderpb.Bid, error) {
+	actor, err := callerActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bidID, err := parseID("bidId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.svc.Decide(ctx, actor, bidID, decisions[in.GetDecision()])
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return bidOf(bid), nil
+}
+
+func (s *bidService) SendFeedback(ctx context.Context, in *tenderpb.SendFeedbackRequest) (*tenderpb.Bid, error) {
+	actor, err := callerActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bidID, err := parseID("bidId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := service.Feedback{Text: in.GetFeedback()}
+	if in.Rating != nil {
+		rating := int(in.GetRating())
+		feedback.Rating = &rating
+	}
+
+	bid, err := s.svc.LeaveFeedback(ctx, actor, bidID, feedback)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return bidOf(bid), nil
+}
+
+func (s *bidService) ListReviews(in *tenderpb.ListReviewsRequest, stream tenderpb.BidService_ListReviewsServer) error {
+	actor, err := callerActor(stream.Context())
+	if err != nil {
+		return err
+	}
+	tenderID, err := parseID("tenderId", in.GetTenderId())
+	if err != nil {
+		return err
+	}
+	query := service.ReviewsQuery{
+		TenderID:       tenderID,
+		AuthorUsername: in.GetAuthorUsername(),
+	}
+	query.Page.Limit, query.Page.Offset = page(in.GetPage())
+
+	err = s.svc.Reviews(stream.Context(), actor, query, func(el service.Message) error {
+		feedback := &tenderpb.Feedback{
+			Id:               el.ID.String(),
+			BidId:            el.BidID.String(),
+			Description:      el.Text,
+			AuthorUsername:   el.AuthorUsername,
+			AuthorName:       el.AuthorName,
+			OrganizationId:   el.OrganizationID.String(),
+			OrganizationName: el.OrganizationName,
+			CreatedAt:        timestamppb.New(el.CreatedAt),
+			EditedAt:         timestamp(el.EditedAt),
+		}
+		if el.ParentID != nil {
+			feedback.ParentId = el.ParentID.String()
+		}
+		if el.Rating != nil {
+			rating := int32(*el.Rating)
+			feedback.Rating = &rating
+		}
+		return stream.Send(feedback)
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *bidService) RollbackBid(ctx context.Context, in *tenderpb.RollbackRequest) (*tenderpb.Bid, error) {
+	actor, err := callerActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bidID, err := parseID("bidId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	bid, err := s.svc.RollbackBid(ctx, actor, bidID, uint(in.GetVersion()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return bidOf(bid), nil
+}
+
+func (s *bidService) ListBidVersions(in *tenderpb.ListVersionsRequest, stream tenderpb.BidService_ListBidVersionsServer) error {
+	actor, err := callerActor(stream.Context())
+	if err != nil {
+		return err
+	}
+	bidID, err := parseID("bidId", in.GetId())
+	if err != nil {
+		return err
+	}
+
+	var p service.Page
+	p.Limit, p.Offset = page(in.GetPage())
+
+	err = s.svc.BidVersions(stream.Context(), actor, bidID, p, func(el service.BidVersion) error {
+		return stream.Send(&tenderpb.Version{
+			Version:     uint32(el.Version),
+			CreatedAt:   timestamppb.New(el.CreatedAt),
+			Name:        el.Name,
+			Description: el.Description,
+			Status:      string(el.Status),
+			AuthorType:  string(el.AuthorType),
+		})
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"tender_service/internal/rpc/tenderpb"
+	"tender_service/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// limitDefault is the page size when the limit is not set, like in REST.
+const limitDefault = 5
+
+func page(p *tenderpb.Page) (limit, offset uint) {
+	if p == nil || p.Limit == nil {
+		return limitDefault, uint(p.GetOffset())
+	}
+	return uint(p.GetLimit()), uint(p.GetOffset())
+}
+
+// parseID parses the id named field, which must be set.
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, invalid("invalid " + field)
+	}
+	return id, nil
+}
+
+func parseIDs(field string, values []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, el := range values {
+		id, err := parseID(field, el)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func idStrings(ids []uuid.UUID) []string {
+	var values []string
+	for _, el := range ids {
+		values = append(values, el.String())
+	}
+	return values
+}
+
+// timestamp converts an optional time, leaving it unset when it is nil.
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func tenderOf(el service.Tender) *tenderpb.Tender {
+	return &tenderpb.Tender{
+		Id:                 el.ID.String(),
+		Name:               el.Name,
+		Description:        el.Description,
+		ServiceType:        string(el.ServiceType),
+		Status:             string(el.Status),
+		Version:            uint32(el.Version),
+		CreatedAt:          timestamppb.New(el.CreatedAt),
+		Categories:         idStrings(el.Categories),
+		Visibility:         string(el.Visibility),
+		SealedBids:         el.SealedBids,
+		SubmissionDeadline: timestamp(el.SubmissionDeadline),
+	}
+}
+
+func bidOf(el service.Bid) *tenderpb.Bid {
+	return &tenderpb.Bid{
+		Id:          el.ID.String(),
+		Name:        el.Name,
+		Description: el.Description,
+		Status:      string(el.Status),
+		AuthorType:  string(el.AuthorType),
+		AuthorId:    el.AuthorID.String(),
+		Version:     uint32(el.Version),
+		CreatedAt:   timestamppb.New(el.CreatedAt),
+		Sealed:      el.Sealed,
+	}
+}
@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"errors"
+	"tender_service/internal/lib/response"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codesByError translates the service errors the way the chi handlers
+// translate them to HTTP statuses.
+var codesByError = []struct {
+	err  error
+	code codes.Code
+}{
+	{response.ErrUserNotExists, codes.Unauthenticated},
+	{response.ErrNoRights, codes.PermissionDenied},
+	{response.ErrTenderNotExists, codes.NotFound},
+	{response.ErrBidNotExists, codes.NotFound},
+	{response.ErrMessageNotExists, codes.NotFound},
+	{response.ErrOrganizationNotExists, codes.NotFound},
+	{response.ErrMemberNotExists, codes.NotFound},
+	{response.ErrInvitationNotExists, codes.NotFound},
+	{response.ErrLotNotExists, codes.NotFound},
+	{response.ErrAuctionNotExists, codes.NotFound},
+	{response.ErrQuestionNotExists, codes.NotFound},
+	{response.ErrTemplateNotExists, codes.NotFound},
+	{response.ErrCategoryNotExists, codes.InvalidArgument},
+	{response.ErrEmptyBody, codes.InvalidArgument},
+	{response.ErrInvalidRequest, codes.InvalidArgument},
+	{response.ErrInvalidID, codes.InvalidArgument},
+	{response.ErrUnsupportedFormat, codes.InvalidArgument},
+	{response.ErrOrganizationAmbiguous, codes.InvalidArgument},
+	{response.ErrIncorrectValue, codes.InvalidArgument},
+	{response.ErrEditWindowExpired, codes.FailedPrecondition},
+	{response.ErrLastAdmin, codes.FailedPrecondition},
+	{response.ErrCategoryInUse, codes.FailedPrecondition},
+	{response.ErrTooManyRequests, codes.ResourceExhausted},
+	{response.ErrRequestCanceled, codes.Canceled},
+	{response.ErrDeadlineExceeded, codes.DeadlineExceeded},
+}
+
+// statusError returns err as a gRPC status error.
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	for _, el := range codesByError {
+		if errors.Is(err, el.err) {
+			return status.Error(el.code, err.Error())
+		}
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// invalid reports a request that failed validation.
+func invalid(msg string) error {
+	return status.Error(codes.InvalidArgument, msg)
+}
@@ -0,0 +1,98 @@
+// Package rpc serves the tender and bid operations over gRPC. It calls the
+// same service use cases as the chi handlers, so both APIs share validation,
+// rights checks and errors.
+package rpc
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=tender_service --go-grpc_out=../.. --go-grpc_opt=module=tender_service tender/v1/tender.proto
+
+import (
+	"context"
+	"strings"
+	"tender_service/internal/config"
+	"tender_service/internal/lib/actingorg"
+	"tender_service/internal/rpc/tenderpb"
+	"tender_service/internal/service"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys identifying the caller.
+const (
+	UsernameKey     = "username"
+	OrganizationKey = "organization-id"
+)
+
+// New returns a gRPC server with the tender and bid services. Calls get the
+// query deadlines configured for the "grpc" group, like "grpc.ListBids".
+func New(svc *service.Service, timeouts config.Timeouts) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeouts.For(route(info.FullMethod)))
+			defer cancel()
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, cancel := context.WithTimeout(ss.Context(), timeouts.For(route(info.FullMethod)))
+			defer cancel()
+			return handler(srv, &deadlineStream{ServerStream: ss, ctx: ctx})
+		}),
+	)
+
+	tenderpb.RegisterTenderServiceServer(srv, &tenderService{svc: svc})
+	tenderpb.RegisterBidServiceServer(srv, &bidService{svc: svc})
+	return srv
+}
+
+// route turns "/tender.v1.BidService/ListBids" into "grpc.ListBids".
+func route(fullMethod string) string {
+	return "grpc." + fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+type deadlineStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *deadlineStream) Context() context.Context {
+	return s.ctx
+}
+
+// caller returns the username from the metadata, failing when it is not
+// set.
+func caller(ctx context.Context) (string, error) {
+	username := metadataValue(ctx, UsernameKey)
+	if username == "" {
+		return "", status.Error(codes.Unauthenticated, "username metadata is required")
+	}
+	return username, nil
+}
+
+// actingOrganization returns the organization the caller acts on behalf
+// of, or uuid.Nil when it is not set.
+func actingOrganization(ctx context.Context) (uuid.UUID, error) {
+	value := metadataValue(ctx, OrganizationKey)
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	orgID, err := uuid.Parse(value)
+	if err != nil || orgID == uuid.Nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, actingorg.ErrInvalid.Error())
+	}
+	return orgID, nil
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
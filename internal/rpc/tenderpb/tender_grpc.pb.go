@@ -0,0 +1,1044 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: tender/v1/tender.proto
+
+package tenderpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	TenderService_CreateTender_FullMethodName       = "/tender.v1.TenderService/CreateTender"
+	TenderService_ListTenders_FullMethodName        = "/tender.v1.TenderService/ListTenders"
+	TenderService_ListMyTenders_FullMethodName      = "/tender.v1.TenderService/ListMyTenders"
+	TenderService_GetTenderStatus_FullMethodName    = "/tender.v1.TenderService/GetTenderStatus"
+	TenderService_SetTenderStatus_FullMethodName    = "/tender.v1.TenderService/SetTenderStatus"
+	TenderService_EditTender_FullMethodName         = "/tender.v1.TenderService/EditTender"
+	TenderService_RollbackTender_FullMethodName     = "/tender.v1.TenderService/RollbackTender"
+	TenderService_ListTenderVersions_FullMethodName = "/tender.v1.TenderService/ListTenderVersions"
+)
+
+// TenderServiceClient is the client API for TenderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TenderServiceClient interface {
+	CreateTender(ctx context.Context, in *CreateTenderRequest, opts ...grpc.CallOption) (*Tender, error)
+	// ListTenders works without "username" too, listing public tenders only.
+	ListTenders(ctx context.Context, in *ListTendersRequest, opts ...grpc.CallOption) (TenderService_ListTendersClient, error)
+	ListMyTenders(ctx context.Context, in *Page, opts ...grpc.CallOption) (TenderService_ListMyTendersClient, error)
+	GetTenderStatus(ctx context.Context, in *TenderRef, opts ...grpc.CallOption) (*StatusReply, error)
+	SetTenderStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*Tender, error)
+	EditTender(ctx context.Context, in *EditTenderRequest, opts ...grpc.CallOption) (*Tender, error)
+	RollbackTender(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*Tender, error)
+	ListTenderVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (TenderService_ListTenderVersionsClient, error)
+}
+
+type tenderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTenderServiceClient(cc grpc.ClientConnInterface) TenderServiceClient {
+	return &tenderServiceClient{cc}
+}
+
+func (c *tenderServiceClient) CreateTender(ctx context.Context, in *CreateTenderRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_CreateTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) ListTenders(ctx context.Context, in *ListTendersRequest, opts ...grpc.CallOption) (TenderService_ListTendersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TenderService_ServiceDesc.Streams[0], TenderService_ListTenders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tenderServiceListTendersClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TenderService_ListTendersClient interface {
+	Recv() (*Tender, error)
+	grpc.ClientStream
+}
+
+type tenderServiceListTendersClient struct {
+	grpc.ClientStream
+}
+
+func (x *tenderServiceListTendersClient) Recv() (*Tender, error) {
+	m := new(Tender)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tenderServiceClient) ListMyTenders(ctx context.Context, in *Page, opts ...grpc.CallOption) (TenderService_ListMyTendersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TenderService_ServiceDesc.Streams[1], TenderService_ListMyTenders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tenderServiceListMyTendersClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TenderService_ListMyTendersClient interface {
+	Recv() (*Tender, error)
+	grpc.ClientStream
+}
+
+type tenderServiceListMyTendersClient struct {
+	grpc.ClientStream
+}
+
+func (x *tenderServiceListMyTendersClient) Recv() (*Tender, error) {
+	m := new(Tender)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tenderServiceClient) GetTenderStatus(ctx context.Context, in *TenderRef, opts ...grpc.CallOption) (*StatusReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusReply)
+	err := c.cc.Invoke(ctx, TenderService_GetTenderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) SetTenderStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_SetTenderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) EditTender(ctx context.Context, in *EditTenderRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_EditTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) RollbackTender(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_RollbackTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) ListTenderVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (TenderService_ListTenderVersionsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TenderService_ServiceDesc.Streams[2], TenderService_ListTenderVersions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tenderServiceListTenderVersionsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TenderService_ListTenderVersionsClient interface {
+	Recv() (*Version, error)
+	grpc.ClientStream
+}
+
+type tenderServiceListTenderVersionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *tenderServiceListTenderVersionsClient) Recv() (*Version, error) {
+	m := new(Version)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TenderServiceServer is the server API for TenderService service.
+// All implementations must embed UnimplementedTenderServiceServer
+// for forward compatibility
+type TenderServiceServer interface {
+	CreateTender(context.Context, *CreateTenderRequest) (*Tender, error)
+	// ListTenders works without "username" too, listing public tenders only.
+	ListTenders(*ListTendersRequest, TenderService_ListTendersServer) error
+	ListMyTenders(*Page, TenderService_ListMyTendersServer) error
+	GetTenderStatus(context.Context, *TenderRef) (*StatusReply, error)
+	SetTenderStatus(context.Context, *SetStatusRequest) (*Tender, error)
+	EditTender(context.Context, *EditTenderRequest) (*Tender, error)
+	RollbackTender(context.Context, *RollbackRequest) (*Tender, error)
+	ListTenderVersions(*ListVersionsRequest, TenderService_ListTenderVersionsServer) error
+	mustEmbedUnimplementedTenderServiceServer()
+}
+
+// UnimplementedTenderServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTenderServiceServer struct {
+}
+
+func (UnimplementedTenderServiceServer) CreateTender(context.Context, *CreateTenderRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTender not implemented")
+}
+func (UnimplementedTenderServiceServer) ListTenders(*ListTendersRequest, TenderService_ListTendersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListTenders not implemented")
+}
+func (UnimplementedTenderServiceServer) ListMyTenders(*Page, TenderService_ListMyTendersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListMyTenders not implemented")
+}
+func (UnimplementedTenderServiceServer) GetTenderStatus(context.Context, *TenderRef) (*StatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenderStatus not implemented")
+}
+func (UnimplementedTenderServiceServer) SetTenderStatus(context.Context, *SetStatusRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTenderStatus not implemented")
+}
+func (UnimplementedTenderServiceServer) EditTender(context.Context, *EditTenderRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditTender not implemented")
+}
+func (UnimplementedTenderServiceServer) RollbackTender(context.Context, *RollbackRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackTender not implemented")
+}
+func (UnimplementedTenderServiceServer) ListTenderVersions(*ListVersionsRequest, TenderService_ListTenderVersionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListTenderVersions not implemented")
+}
+func (UnimplementedTenderServiceServer) mustEmbedUnimplementedTenderServiceServer() {}
+
+// UnsafeTenderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TenderServiceServer will
+// result in compilation errors.
+type UnsafeTenderServiceServer interface {
+	mustEmbedUnimplementedTenderServiceServer()
+}
+
+func RegisterTenderServiceServer(s grpc.ServiceRegistrar, srv TenderServiceServer) {
+	s.RegisterService(&TenderService_ServiceDesc, srv)
+}
+
+func _TenderService_CreateTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).CreateTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_CreateTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).CreateTender(ctx, req.(*CreateTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_ListTenders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListTendersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TenderServiceServer).ListTenders(m, &tenderServiceListTendersServer{ServerStream: stream})
+}
+
+type TenderService_ListTendersServer interface {
+	Send(*Tender) error
+	grpc.ServerStream
+}
+
+type tenderServiceListTendersServer struct {
+	grpc.ServerStream
+}
+
+func (x *tenderServiceListTendersServer) Send(m *Tender) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TenderService_ListMyTenders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Page)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TenderServiceServer).ListMyTenders(m, &tenderServiceListMyTendersServer{ServerStream: stream})
+}
+
+type TenderService_ListMyTendersServer interface {
+	Send(*Tender) error
+	grpc.ServerStream
+}
+
+type tenderServiceListMyTendersServer struct {
+	grpc.ServerStream
+}
+
+func (x *tenderServiceListMyTendersServer) Send(m *Tender) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TenderService_GetTenderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TenderRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).GetTenderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_GetTenderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).GetTenderStatus(ctx, req.(*TenderRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_SetTenderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).SetTenderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_SetTenderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).SetTenderStatus(ctx, req.(*SetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_EditTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).EditTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_EditTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).EditTender(ctx, req.(*EditTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_RollbackTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).RollbackTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_RollbackTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).RollbackTender(ctx, req.(*RollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_ListTenderVersions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListVersionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TenderServiceServer).ListTenderVersions(m, &tenderServiceListTenderVersionsServer{ServerStream: stream})
+}
+
+type TenderService_ListTenderVersionsServer interface {
+	Send(*Version) error
+	grpc.ServerStream
+}
+
+type tenderServiceListTenderVersionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *tenderServiceListTenderVersionsServer) Send(m *Version) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TenderService_ServiceDesc is the grpc.ServiceDesc for TenderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TenderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tender.v1.TenderService",
+	HandlerType: (*TenderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTender",
+			Handler:    _TenderService_CreateTender_Handler,
+		},
+		{
+			MethodName: "GetTenderStatus",
+			Handler:    _TenderService_GetTenderStatus_Handler,
+		},
+		{
+			MethodName: "SetTenderStatus",
+			Handler:    _TenderService_SetTenderStatus_Handler,
+		},
+		{
+			MethodName: "EditTender",
+			Handler:    _TenderService_EditTender_Handler,
+		},
+		{
+			MethodName: "RollbackTender",
+			Handler:    _TenderService_RollbackTender_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListTenders",
+			Handler:       _TenderService_ListTenders_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListMyTenders",
+			Handler:       _TenderService_ListMyTenders_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListTenderVersions",
+			Handler:       _TenderService_ListTenderVersions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tender/v1/tender.proto",
+}
+
+const (
+	BidService_CreateBid_FullMethodName       = "/tender.v1.BidService/CreateBid"
+	BidService_ListBids_FullMethodName        = "/tender.v1.BidService/ListBids"
+	BidService_ListMyBids_FullMethodName      = "/tender.v1.BidService/ListMyBids"
+	BidService_GetBidStatus_FullMethodName    = "/tender.v1.BidService/GetBidStatus"
+	BidService_SetBidStatus_FullMethodName    = "/tender.v1.BidService/SetBidStatus"
+	BidService_EditBid_FullMethodName         = "/tender.v1.BidService/EditBid"
+	BidService_SubmitDecision_FullMethodName  = "/tender.v1.BidService/SubmitDecision"
+	BidService_SendFeedback_FullMethodName    = "/tender.v1.BidService/SendFeedback"
+	BidService_ListReviews_FullMethodName     = "/tender.v1.BidService/ListReviews"
+	BidService_RollbackBid_FullMethodName     = "/tender.v1.BidService/RollbackBid"
+	BidService_ListBidVersions_FullMethodName = "/tender.v1.BidService/ListBidVersions"
+)
+
+// BidServiceClient is the client API for BidService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BidServiceClient interface {
+	CreateBid(ctx context.Context, in *CreateBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	ListBids(ctx context.Context, in *ListBidsRequest, opts ...grpc.CallOption) (BidService_ListBidsClient, error)
+	ListMyBids(ctx context.Context, in *Page, opts ...grpc.CallOption) (BidService_ListMyBidsClient, error)
+	GetBidStatus(ctx context.Context, in *BidRef, opts ...grpc.CallOption) (*StatusReply, error)
+	SetBidStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*Bid, error)
+	EditBid(ctx context.Context, in *EditBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	SubmitDecision(ctx context.Context, in *SubmitDecisionRequest, opts ...grpc.CallOption) (*Bid, error)
+	SendFeedback(ctx context.Context, in *SendFeedbackRequest, opts ...grpc.CallOption) (*Bid, error)
+	// ListReviews streams the feedback on an author's bids, oldest first,
+	// with replies pointing at their parent.
+	ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (BidService_ListReviewsClient, error)
+	RollbackBid(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*Bid, error)
+	ListBidVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (BidService_ListBidVersionsClient, error)
+}
+
+type bidServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBidServiceClient(cc grpc.ClientConnInterface) BidServiceClient {
+	return &bidServiceClient{cc}
+}
+
+func (c *bidServiceClient) CreateBid(ctx context.Context, in *CreateBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_CreateBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListBids(ctx context.Context, in *ListBidsRequest, opts ...grpc.CallOption) (BidService_ListBidsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BidService_ServiceDesc.Streams[0], BidService_ListBids_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bidServiceListBidsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BidService_ListBidsClient interface {
+	Recv() (*Bid, error)
+	grpc.ClientStream
+}
+
+type bidServiceListBidsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bidServiceListBidsClient) Recv() (*Bid, error) {
+	m := new(Bid)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bidServiceClient) ListMyBids(ctx context.Context, in *Page, opts ...grpc.CallOption) (BidService_ListMyBidsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BidService_ServiceDesc.Streams[1], BidService_ListMyBids_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bidServiceListMyBidsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BidService_ListMyBidsClient interface {
+	Recv() (*Bid, error)
+	grpc.ClientStream
+}
+
+type bidServiceListMyBidsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bidServiceListMyBidsClient) Recv() (*Bid, error) {
+	m := new(Bid)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bidServiceClient) GetBidStatus(ctx context.Context, in *BidRef, opts ...grpc.CallOption) (*StatusReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusReply)
+	err := c.cc.Invoke(ctx, BidService_GetBidStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) SetBidStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_SetBidStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) EditBid(ctx context.Context, in *EditBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_EditBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) SubmitDecision(ctx context.Context, in *SubmitDecisionRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_SubmitDecision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) SendFeedback(ctx context.Context, in *SendFeedbackRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_SendFeedback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (BidService_ListReviewsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BidService_ServiceDesc.Streams[2], BidService_ListReviews_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bidServiceListReviewsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BidService_ListReviewsClient interface {
+	Recv() (*Feedback, error)
+	grpc.ClientStream
+}
+
+type bidServiceListReviewsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bidServiceListReviewsClient) Recv() (*Feedback, error) {
+	m := new(Feedback)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bidServiceClient) RollbackBid(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_RollbackBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListBidVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (BidService_ListBidVersionsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BidService_ServiceDesc.Streams[3], BidService_ListBidVersions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bidServiceListBidVersionsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BidService_ListBidVersionsClient interface {
+	Recv() (*Version, error)
+	grpc.ClientStream
+}
+
+type bidServiceListBidVersionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bidServiceListBidVersionsClient) Recv() (*Version, error) {
+	m := new(Version)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BidServiceServer is the server API for BidService service.
+// All implementations must embed UnimplementedBidServiceServer
+// for forward compatibility
+type BidServiceServer interface {
+	CreateBid(context.Context, *CreateBidRequest) (*Bid, error)
+	ListBids(*ListBidsRequest, BidService_ListBidsServer) error
+	ListMyBids(*Page, BidService_ListMyBidsServer) error
+	GetBidStatus(context.Context, *BidRef) (*StatusReply, error)
+	SetBidStatus(context.Context, *SetStatusRequest) (*Bid, error)
+	EditBid(context.Context, *EditBidRequest) (*Bid, error)
+	SubmitDecision(context.Context, *SubmitDecisionRequest) (*Bid, error)
+	SendFeedback(context.Context, *SendFeedbackRequest) (*Bid, error)
+	// ListReviews streams the feedback on an author's bids, oldest first,
+	// with replies pointing at their parent.
+	ListReviews(*ListReviewsRequest, BidService_ListReviewsServer) error
+	RollbackBid(context.Context, *RollbackRequest) (*Bid, error)
+	ListBidVersions(*ListVersionsRequest, BidService_ListBidVersionsServer) error
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+// UnimplementedBidServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBidServiceServer struct {
+}
+
+func (UnimplementedBidServiceServer) CreateBid(context.Context, *CreateBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBid not implemented")
+}
+func (UnimplementedBidServiceServer) ListBids(*ListBidsRequest, BidService_ListBidsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBids not implemented")
+}
+func (UnimplementedBidServiceServer) ListMyBids(*Page, BidService_ListMyBidsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListMyBids not implemented")
+}
+func (UnimplementedBidServiceServer) GetBidStatus(context.Context, *BidRef) (*StatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBidStatus not implemented")
+}
+func (UnimplementedBidServiceServer) SetBidStatus(context.Context, *SetStatusRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBidStatus not implemented")
+}
+func (UnimplementedBidServiceServer) EditBid(context.Context, *EditBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditBid not implemented")
+}
+func (UnimplementedBidServiceServer) SubmitDecision(context.Context, *SubmitDecisionRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitDecision not implemented")
+}
+func (UnimplementedBidServiceServer) SendFeedback(context.Context, *SendFeedbackRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendFeedback not implemented")
+}
+func (UnimplementedBidServiceServer) ListReviews(*ListReviewsRequest, BidService_ListReviewsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListReviews not implemented")
+}
+func (UnimplementedBidServiceServer) RollbackBid(context.Context, *RollbackRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackBid not implemented")
+}
+func (UnimplementedBidServiceServer) ListBidVersions(*ListVersionsRequest, BidService_ListBidVersionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBidVersions not implemented")
+}
+func (UnimplementedBidServiceServer) mustEmbedUnimplementedBidServiceServer() {}
+
+// UnsafeBidServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BidServiceServer will
+// result in compilation errors.
+type UnsafeBidServiceServer interface {
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+func RegisterBidServiceServer(s grpc.ServiceRegistrar, srv BidServiceServer) {
+	s.RegisterService(&BidService_ServiceDesc, srv)
+}
+
+func _BidService_CreateBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).CreateBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_CreateBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).CreateBid(ctx, req.(*CreateBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListBids_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBidsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BidServiceServer).ListBids(m, &bidServiceListBidsServer{ServerStream: stream})
+}
+
+type BidService_ListBidsServer interface {
+	Send(*Bid) error
+	grpc.ServerStream
+}
+
+type bidServiceListBidsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bidServiceListBidsServer) Send(m *Bid) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BidService_ListMyBids_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Page)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BidServiceServer).ListMyBids(m, &bidServiceListMyBidsServer{ServerStream: stream})
+}
+
+type BidService_ListMyBidsServer interface {
+	Send(*Bid) error
+	grpc.ServerStream
+}
+
+type bidServiceListMyBidsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bidServiceListMyBidsServer) Send(m *Bid) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BidService_GetBidStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BidRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).GetBidStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_GetBidStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).GetBidStatus(ctx, req.(*BidRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_SetBidStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).SetBidStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_SetBidStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).SetBidStatus(ctx, req.(*SetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_EditBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).EditBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_EditBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).EditBid(ctx, req.(*EditBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_SubmitDecision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitDecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).SubmitDecision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_SubmitDecision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).SubmitDecision(ctx, req.(*SubmitDecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_SendFeedback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendFeedbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).SendFeedback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_SendFeedback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).SendFeedback(ctx, req.(*SendFeedbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListReviews_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListReviewsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BidServiceServer).ListReviews(m, &bidServiceListReviewsServer{ServerStream: stream})
+}
+
+type BidService_ListReviewsServer interface {
+	Send(*Feedback) error
+	grpc.ServerStream
+}
+
+type bidServiceListReviewsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bidServiceListReviewsServer) Send(m *Feedback) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BidService_RollbackBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).RollbackBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_RollbackBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).RollbackBid(ctx, req.(*RollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListBidVersions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListVersionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BidServiceServer).ListBidVersions(m, &bidServiceListBidVersionsServer{ServerStream: stream})
+}
+
+type BidService_ListBidVersionsServer interface {
+	Send(*Version) error
+	grpc.ServerStream
+}
+
+type bidServiceListBidVersionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bidServiceListBidVersionsServer) Send(m *Version) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BidService_ServiceDesc is the grpc.ServiceDesc for BidService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BidService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tender.v1.BidService",
+	HandlerType: (*BidServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBid",
+			Handler:    _BidService_CreateBid_Handler,
+		},
+		{
+			MethodName: "GetBidStatus",
+			Handler:    _BidService_GetBidStatus_Handler,
+		},
+		{
+			MethodName: "SetBidStatus",
+			Handler:    _BidService_SetBidStatus_Handler,
+		},
+		{
+			MethodName: "EditBid",
+			Handler:    _BidService_EditBid_Handler,
+		},
+		{
+			MethodName: "SubmitDecision",
+			Handler:    _BidService_SubmitDecision_Handler,
+		},
+		{
+			MethodName: "SendFeedback",
+			Handler:    _BidService_SendFeedback_Handler,
+		},
+		{
+			MethodName: "RollbackBid",
+			Handler:    _BidService_RollbackBid_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListBids",
+			Handler:       _BidService_ListBids_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListMyBids",
+			Handler:       _BidService_ListMyBids_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListReviews",
+			Handler:       _BidService_ListReviews_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListBidVersions",
+			Handler:       _BidService_ListBidVersions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tender/v1/tender.proto",
+}
@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"context"
+	"tender_service/internal/rpc/tenderpb"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type tenderService struct {
+	tenderpb.UnimplementedTenderServiceServer
+	svc *service.Service
+}
+
+func (s *tenderService) CreateTender(ctx context.Context, in *tenderpb.CreateTenderRequest) (*tenderpb.Tender, error) {
+	username, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := service.NewTender{
+		Name:               in.GetName(),
+		Description:        in.GetDescription(),
+		ServiceType:        models.TenderServiceType(in.GetServiceType()),
+		CreatorUsername:    username,
+		Visibility:         models.TenderVisibility(in.GetVisibility()),
+		SealedBids:         in.GetSealedBids(),
+		SubmissionDeadline: optionalTime(in.GetSubmissionDeadline()),
+	}
+	if in.GetOrganizationId() != "" {
+		req.OrganizationID, err = parseID("organizationId", in.GetOrganizationId())
+	} else {
+		req.OrganizationID, err = actingOrganization(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Categories, err = parseIDs("categories", in.GetCategories())
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.svc.CreateTender(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tenderOf(tender), nil
+}
+
+func (s *tenderService) ListTenders(in *tenderpb.ListTendersRequest, stream tenderpb.TenderService_ListTendersServer) error {
+	// Anonymous callers see public tenders only.
+	query := service.TendersQuery{
+		Username:     metadataValue(stream.Context(), UsernameKey),
+		ServiceTypes: in.GetServiceTypes(),
+	}
+	query.Page.Limit, query.Page.Offset = page(in.GetPage())
+
+	err := s.svc.Tenders(stream.Context(), query, func(el service.Tender) error {
+		return stream.Send(tenderOf(el))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *tenderService) ListMyTenders(in *tenderpb.Page, stream tenderpb.TenderService_ListMyTendersServer) error {
+	username, err := caller(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	var p service.Page
+	p.Limit, p.Offset = page(in)
+
+	err = s.svc.MyTenders(stream.Context(), username, p, func(el service.Tender) error {
+		return stream.Send(tenderOf(el))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *tenderService) GetTenderStatus(ctx context.Context, in *tenderpb.TenderRef) (*tenderpb.StatusReply, error) {
+	username, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenderID, err := parseID("tenderId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.svc.TenderStatus(ctx, service.Actor{Username: username}, tenderID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &tenderpb.StatusReply{Status: string(status)}, nil
+}
+
+func (s *tenderService) SetTenderStatus(ctx context.Context, in *tenderpb.SetStatusRequest) (*tenderpb.Tender, error) {
+	username, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenderID, err := parseID("tenderId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.svc.SetTenderStatus(ctx, service.Actor{Username: username}, tenderID, models.TenderStatus(in.GetStatus()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tenderOf(tender), nil
+}
+
+func (s *tenderService) EditTender(ctx context.Context, in *tenderpb.EditTenderRequest) (*tenderpb.Tender, error) {
+	username, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenderID, err := parseID("tenderId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	patch := service.TenderPatch{
+		TenderID:           tenderID,
+		Name:               in.GetName(),
+		Description:        in.GetDescription(),
+		ServiceType:        models.TenderServiceType(in.GetServiceType()),
+		Status:             models.TenderStatus(in.GetStatus()),
+		Visibility:         models.TenderVisibility(in.GetVisibility()),
+		SealedBids:         in.SealedBids,
+		SubmissionDeadline: optionalTime(in.GetSubmissionDeadline()),
+	}
+	patch.Categories, err = parseIDs("categories", in.GetCategories())
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.svc.EditTender(ctx, service.Actor{Username: username}, patch)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tenderOf(tender), nil
+}
+
+func (s *tenderService) RollbackTender(ctx context.Context, in *tenderpb.RollbackRequest) (*tenderpb.Tender, error) {
+	username, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenderID, err := parseID("tenderId", in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	tender, err := s.svc.RollbackTender(ctx, service.Actor{Username: username}, tenderID, uint(in.GetVersion()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tenderOf(tender), nil
+}
+
+func (s *tenderService) ListTenderVersions(in *tenderpb.ListVersionsRequest, stream tenderpb.TenderService_ListTenderVersionsServer) error {
+	username, err := caller(stream.Context())
+	if err != nil {
+		return err
+	}
+	tenderID, err := parseID("tenderId", in.GetId())
+	if err != nil {
+		return err
+	}
+
+	var p service.Page
+	p.Limit, p.Offset = page(in.GetPage())
+
+	err = s.svc.TenderVersions(stream.Context(), service.Actor{Username: username}, tenderID, p, func(el service.TenderVersion) error {
+		return stream.Send(&tenderpb.Version{
+			Version:     uint32(el.Version),
+			CreatedAt:   timestamppb.New(el.CreatedAt),
+			Name:        el.Name,
+			Description: el.Description,
+			Status:      string(el.Status),
+			ServiceType: string(el.ServiceType),
+		})
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) isAdmin(userName string) bool {
+	_, ok := s.admins[userName]
+	return ok
+}
+
+// checkAdmin checks that the user exists and is a service admin.
+func (s *Service) checkAdmin(ctx context.Context, userName string) error {
+	_, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	if !s.isAdmin(userName) {
+		return response.ErrNoRights
+	}
+	return nil
+}
+
+// checkResponsible checks that the user is a member of the organization.
+func (s *Service) checkResponsible(ctx context.Context, userID uuid.UUID, orgID uuid.UUID) error {
+	_, err := s.repo.MemberRole(ctx, userID, orgID)
+	if errors.Is(err, response.ErrMemberNotExists) {
+		return response.ErrNoRights
+	}
+	return err
+}
+
+// checkPermission checks that the user's role in the organization grants perm.
+func (s *Service) checkPermission(ctx context.Context, userID uuid.UUID, orgID uuid.UUID, perm policy.Permission) error {
+	role, err := s.repo.MemberRole(ctx, userID, orgID)
+	if errors.Is(err, response.ErrMemberNotExists) {
+		return response.ErrNoRights
+	}
+	if err != nil {
+		return err
+	}
+
+	if !policy.Allows(role, perm) {
+		return response.ErrNoRights
+	}
+	return nil
+}
+
+// ActingOrganization resolves the organization the user acts on behalf of.
+// An explicit orgID must be one of the user's organizations; without it the
+// user must be responsible for exactly one.
+func (s *Service) ActingOrganization(ctx context.Context, userName string, orgID uuid.UUID) (uuid.UUID, error) {
+	user, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return s.actingOrganization(ctx, user.ID, orgID)
+}
+
+// UserExists tells whether there is an employee with the username.
+func (s *Service) UserExists(ctx context.Context, userName string) (bool, error) {
+	_, err := s.repo.GetUser(ctx, userName)
+	if errors.Is(err, response.ErrUserNotExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Service) actingOrganization(ctx context.Context, userID uuid.UUID, orgID uuid.UUID) (uuid.UUID, error) {
+	if userID == uuid.Nil {
+		return uuid.Nil, response.ErrUserNotExists
+	}
+
+	orgIDs, err := s.repo.UserOrganizations(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if len(orgIDs) == 0 {
+		return uuid.Nil, response.ErrUserNotExists
+	}
+
+	if orgID != uuid.Nil {
+		if !hasID(orgIDs, orgID) {
+			return uuid.Nil, response.ErrNoRights
+		}
+		return orgID, nil
+	}
+
+	if len(orgIDs) > 1 {
+		return uuid.Nil, response.ErrOrganizationAmbiguous
+	}
+	return orgIDs[0], nil
+}
+
+// actingOrganizations narrows the user's organizations down to orgID when it
+// is given.
+func (s *Service) actingOrganizations(ctx context.Context, userID uuid.UUID, orgID uuid.UUID) ([]uuid.UUID, error) {
+	orgIDs, err := s.repo.UserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if orgID == uuid.Nil {
+		return orgIDs, nil
+	}
+
+	if !hasID(orgIDs, orgID) {
+		return nil, response.ErrNoRights
+	}
+	return []uuid.UUID{orgID}, nil
+}
+
+// viewer describes the user for the tender visibility rules, a nil user is
+// an anonymous one.
+func (s *Service) viewer(ctx context.Context, user *models.Employee) (*Viewer, error) {
+	if user == nil {
+		return &Viewer{}, nil
+	}
+
+	orgIDs, err := s.repo.UserOrganizations(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Viewer{UserID: user.ID, OrganizationIDs: orgIDs}, nil
+}
+
+// canSeeTender tells whether the user may see the tender at all. A nil user
+// is an anonymous one and only sees public tenders.
+func (s *Service) canSeeTender(ctx context.Context, tender *models.Tender, user *models.Employee) (bool, error) {
+	if tender.Visibility == models.PublicVisibility || tender.Visibility == "" {
+		return true, nil
+	}
+
+	if user == nil {
+		return false, nil
+	}
+
+	orgIDs, err := s.repo.UserOrganizations(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if hasID(orgIDs, tender.OrganizationID) {
+		return true, nil
+	}
+
+	switch tender.Visibility {
+	case models.OrganizationsVisibility:
+		return len(orgIDs) > 0, nil
+	case models.InvitationVisibility:
+		return s.repo.Invited(ctx, tender.ID, user.ID, orgIDs)
+	}
+
+	return false, nil
+}
+
+// visibleTender returns the tender when the user may see it and
+// ErrTenderNotExists otherwise.
+func (s *Service) visibleTender(ctx context.Context, tenderID uuid.UUID, user *models.Employee) (*models.Tender, error) {
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := s.canSeeTender(ctx, tender, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if !visible {
+		return nil, response.ErrTenderNotExists
+	}
+	return tender, nil
+}
+
+// openTender returns the tender when the user may see it and it accepts
+// bids.
+func (s *Service) openTender(ctx context.Context, tenderID uuid.UUID, user *models.Employee) (*models.Tender, error) {
+	tender, err := s.visibleTender(ctx, tenderID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if tender.Status != models.TenderPublished || submissionClosed(tender) {
+		return nil, response.ErrNoRights
+	}
+	return tender, nil
+}
+
+// authorizeBid checks that the user acting on behalf of orgID (or any of
+// their organizations when it is uuid.Nil) may perform action on the bid and
+// returns the bid's tender and how the user is related to the bid.
+func (s *Service) authorizeBid(ctx context.Context, user *models.Employee, orgID uuid.UUID, bid *models.Bid, action policy.Action) (*models.Tender, policy.Relation, error) {
+	tender, err := s.repo.GetTender(ctx, bid.TenderID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	orgIDs, err := s.actingOrganizations(ctx, user.ID, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A responsible of both the bidder and the tender owner has to tell which
+	// side they are on.
+	if orgID == uuid.Nil && bid.OrganizationID != tender.OrganizationID &&
+		hasID(orgIDs, bid.OrganizationID) && hasID(orgIDs, tender.OrganizationID) {
+		return nil, "", response.ErrOrganizationAmbiguous
+	}
+
+	state := bidState(bid, tender)
+	if bid.LotID != nil {
+		lot, err := s.repo.GetTenderLot(ctx, tender.ID, *bid.LotID)
+		if err != nil {
+			return nil, "", err
+		}
+		state.LotClosed = lot.Status != models.LotOpen
+	}
+
+	state.AuctionPending, err = s.auctionPending(ctx, tender)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rel := bidRelation(user, orgIDs, bid, tender)
+	if !policy.CanBid(rel, action, state) {
+		return nil, "", response.ErrNoRights
+	}
+
+	if perm, ok := policy.BidPermission(rel, action); ok {
+		actingID := bid.OrganizationID
+		if rel == policy.TenderOrganization {
+			actingID = tender.OrganizationID
+		}
+
+		err = s.checkPermission(ctx, user.ID, actingID, perm)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return tender, rel, nil
+}
+
+// actorBid looks up the actor and the bid and authorizes action on it.
+func (s *Service) actorBid(ctx context.Context, actor Actor, bidID uuid.UUID, action policy.Action) (*models.Employee, *models.Bid, *models.Tender, policy.Relation, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+
+	bid, err := s.repo.GetBid(ctx, bidID)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+
+	tender, rel, err := s.authorizeBid(ctx, user, actor.OrganizationID, bid, action)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+	return user, bid, tender, rel, nil
+}
+
+func bidRelation(user *models.Employee, orgIDs []uuid.UUID, bid *models.Bid, tender *models.Tender) policy.Relation {
+	if hasID(orgIDs, bid.OrganizationID) {
+		if bid.EmployeeUsername == user.Username {
+			return policy.Author
+		}
+		return policy.AuthorOrganization
+	}
+	if hasID(orgIDs, tender.OrganizationID) {
+		return policy.TenderOrganization
+	}
+	if bid.EmployeeUsername == user.Username {
+		return policy.Author
+	}
+	return policy.Competitor
+}
+
+func bidState(bid *models.Bid, tender *models.Tender) policy.Bid {
+	closed := submissionClosed(tender)
+	return policy.Bid{
+		Status:           bid.Status,
+		Sealed:           tender.SealedBids && !closed,
+		SubmissionClosed: closed,
+		TenderClosed:     tender.Status != models.TenderPublished,
+	}
+}
+
+func submissionClosed(tender *models.Tender) bool {
+	return tender.SubmissionDeadline != nil && !time.Now().Before(*tender.SubmissionDeadline)
+}
+
+func hasID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, el := range ids {
+		if el == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionSettings are the rules of the auction round of a tender.
+type AuctionSettings struct {
+	StartPrice   float64 `validate:"gt=0"`
+	MinDecrement float64 `validate:"gt=0"`
+	Duration     time.Duration
+	// Extension is how long the round lasts after an offer made at its very
+	// end, late offers don't move the end when it is zero
+	Extension time.Duration
+}
+
+// SetAuction makes the bidders of a tender that is not published yet
+// compete on price once its submission closes.
+func (s *Service) SetAuction(ctx context.Context, actor Actor, tenderID uuid.UUID, in AuctionSettings) (Auction, error) {
+	if err := check(in); err != nil {
+		return Auction{}, err
+	}
+	if in.Duration < time.Minute {
+		return Auction{}, invalid("auction duration must be at least a minute")
+	}
+	if in.Extension < 0 || in.Extension > in.Duration {
+		return Auction{}, invalid("auction extension must be between zero and the duration")
+	}
+	if roundPrice(in.MinDecrement) >= roundPrice(in.StartPrice) {
+		return Auction{}, invalid("minimum decrement must be less than the start price")
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.EditTender)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	if tender.Status != models.TenderCreated {
+		return Auction{}, response.ErrNoRights
+	}
+
+	if tender.ServiceType != models.Delivery {
+		return Auction{}, invalid("only delivery tenders can have an auction")
+	}
+
+	if tender.SubmissionDeadline == nil {
+		return Auction{}, invalid("auction requires submission deadline")
+	}
+
+	lots, err := s.repo.TenderLots(ctx, tender.ID)
+	if err != nil {
+		return Auction{}, err
+	}
+	if len(lots) > 0 {
+		return Auction{}, invalid("tenders with lots can't have an auction")
+	}
+
+	auction := models.TenderAuction{
+		TenderID:     tender.ID,
+		StartPrice:   roundPrice(in.StartPrice),
+		MinDecrement: roundPrice(in.MinDecrement),
+		Duration:     in.Duration,
+		Extension:    in.Extension,
+	}
+
+	err = s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		err := repo.SaveAuction(ctx, &auction)
+		if err != nil {
+			return err
+		}
+
+		if tender.Auction {
+			return nil
+		}
+		tender.Auction = true
+		return repo.UpdateTender(ctx, tender)
+	})
+	if err != nil {
+		return Auction{}, err
+	}
+
+	orgIDs, err := s.repo.UserOrganizations(ctx, user.ID)
+	if err != nil {
+		return Auction{}, err
+	}
+	return s.auctionOf(ctx, tender, &auction, orgIDs)
+}
+
+// Auction returns the auction round of a tender the user may see, with the
+// ranking when they are its participant or the tender's organization.
+func (s *Service) Auction(ctx context.Context, actor Actor, tenderID uuid.UUID) (Auction, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	tender, err := s.visibleTender(ctx, tenderID, user)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	if !tender.Auction {
+		return Auction{}, response.ErrAuctionNotExists
+	}
+
+	auction, err := s.repo.GetAuction(ctx, tender.ID)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	orgIDs, err := s.repo.UserOrganizations(ctx, user.ID)
+	if err != nil {
+		return Auction{}, err
+	}
+	return s.auctionOf(ctx, tender, auction, orgIDs)
+}
+
+// PlaceOffer offers a price for the bid in the running auction round of its
+// tender. The price must be at least the minimum decrement below the bid's
+// previous offer, or below the start price for the first one, and an offer
+// within the last extension of the round moves its end.
+func (s *Service) PlaceOffer(ctx context.Context, actor Actor, bidID uuid.UUID, price float64) (Auction, error) {
+	if price <= 0 {
+		return Auction{}, invalid("price must be positive")
+	}
+	price = roundPrice(price)
+
+	user, bid, tender, _, err := s.actorBid(ctx, actor, bidID, policy.OfferPrice)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	if !tender.Auction {
+		return Auction{}, response.ErrAuctionNotExists
+	}
+
+	var auction *models.TenderAuction
+	err = s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		txs := s.withRepo(repo)
+
+		var err error
+		auction, err = repo.LockAuction(ctx, tender.ID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if auctionStatus(tender, auction, now) != AuctionRunning {
+			return response.ErrNoRights
+		}
+
+		offers, err := repo.AuctionOffers(ctx, tender.ID)
+		if err != nil {
+			return err
+		}
+
+		last := auction.StartPrice
+		for _, el := range offers {
+			if el.BidID == bid.ID {
+				last = el.Price
+			}
+		}
+
+		highest := roundPrice(last - auction.MinDecrement)
+		if price > highest {
+			return invalid(fmt.Sprintf("offer must be at most %.2f", highest))
+		}
+
+		err = repo.CreateOffer(ctx, &models.AuctionOffer{
+			TenderID:         tender.ID,
+			BidID:            bid.ID,
+			EmployeeUsername: user.Username,
+			Price:            price,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, end := auctionWindow(tender, auction)
+		if end.Sub(now) < auction.Extension {
+			extended := now.Add(auction.Extension)
+			auction.ExtendedUntil = &extended
+
+			err = repo.SaveAuction(ctx, auction)
+			if err != nil {
+				return err
+			}
+		}
+
+		return txs.record(ctx, models.AuctionOfferEvent, tender, nil, "")
+	})
+	if err != nil {
+		return Auction{}, err
+	}
+
+	orgIDs, err := s.repo.UserOrganizations(ctx, user.ID)
+	if err != nil {
+		return Auction{}, err
+	}
+	return s.auctionOf(ctx, tender, auction, orgIDs)
+}
+
+// auctionPending tells whether the auction round of the tender, if it has
+// one, is not over yet.
+func (s *Service) auctionPending(ctx context.Context, tender *models.Tender) (bool, error) {
+	if !tender.Auction {
+		return false, nil
+	}
+
+	auction, err := s.repo.GetAuction(ctx, tender.ID)
+	if err != nil {
+		return false, err
+	}
+	return auctionStatus(tender, auction, time.Now()) != AuctionFinished, nil
+}
+
+// auctionLeader returns the bid with the best offer of the tender's auction
+// among the bids still waiting for a decision, uuid.Nil when none of them
+// made an offer. Offers only go down, so the best one is the latest of its
+// bid, and of equal offers the earlier one wins.
+func (s *Service) auctionLeader(ctx context.Context, tender *models.Tender) (uuid.UUID, error) {
+	published := make(map[uuid.UUID]bool)
+	filter := BidFilter{TenderID: tender.ID, Statuses: []models.BidStatus{models.BidPublished}, Page: unpaged}
+	err := s.repo.EachBid(ctx, filter, func(el models.Bid) error {
+		published[el.ID] = true
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	offers, err := s.repo.AuctionOffers(ctx, tender.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var best *models.AuctionOffer
+	for i, el := range offers {
+		if !published[el.BidID] {
+			continue
+		}
+		if best == nil || el.Price < best.Price {
+			best = &offers[i]
+		}
+	}
+
+	if best == nil {
+		return uuid.Nil, nil
+	}
+	return best.BidID, nil
+}
+
+// auctionOf is the auction as a viewer responsible for orgIDs sees it. The
+// ranking is shown to the tender's organization and to the organizations
+// bidding on the tender, the bids of others stay anonymous to the latter.
+func (s *Service) auctionOf(ctx context.Context, tender *models.Tender, auction *models.TenderAuction, orgIDs []uuid.UUID) (Auction, error) {
+	start, end := auctionWindow(tender, auction)
+	res := Auction{
+		TenderID:     tender.ID,
+		StartPrice:   auction.StartPrice,
+		MinDecrement: auction.MinDecrement,
+		Duration:     auction.Duration,
+		Extension:    auction.Extension,
+		StartsAt:     start,
+		EndsAt:       end,
+		Status:       auctionStatus(tender, auction, time.Now()),
+	}
+
+	owner := hasID(orgIDs, tender.OrganizationID)
+	participant := owner
+
+	bids := make(map[uuid.UUID]models.Bid)
+	err := s.repo.EachBid(ctx, BidFilter{TenderID: tender.ID, Page: unpaged}, func(el models.Bid) error {
+		bids[el.ID] = el
+		if el.Status == models.BidPublished && hasID(orgIDs, el.OrganizationID) {
+			participant = true
+		}
+		return nil
+	})
+	if err != nil {
+		return Auction{}, err
+	}
+
+	if !participant {
+		return res, nil
+	}
+
+	offers, err := s.repo.AuctionOffers(ctx, tender.ID)
+	if err != nil {
+		return Auction{}, err
+	}
+
+	latest := make(map[uuid.UUID]*AuctionRank)
+	var ranking []*AuctionRank
+	for _, el := range offers {
+		bid, ok := bids[el.BidID]
+		// withdrawn and canceled bids drop out of the ranking
+		if !ok || !policy.Ranked(bid.Status) {
+			continue
+		}
+
+		rank, ok := latest[el.BidID]
+		if !ok {
+			rank = &AuctionRank{}
+			latest[el.BidID] = rank
+			ranking = append(ranking, rank)
+
+			rank.Own = hasID(orgIDs, bid.OrganizationID)
+			if owner || rank.Own {
+				bidID := bid.ID
+				rank.BidID = &bidID
+			}
+		}
+		rank.Price = el.Price
+		rank.OfferedAt = el.CreatedAt
+		rank.Offers++
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].Price != ranking[j].Price {
+			return ranking[i].Price < ranking[j].Price
+		}
+		return ranking[i].OfferedAt.Before(ranking[j].OfferedAt)
+	})
+
+	res.Ranking = make([]AuctionRank, 0, len(ranking))
+	for i, el := range ranking {
+		el.Rank = i + 1
+		res.Ranking = append(res.Ranking, *el)
+	}
+	return res, nil
+}
+
+// auctionWindow returns when the auction round of the tender starts and
+// ends, both are zero while the tender has no submission deadline.
+func auctionWindow(tender *models.Tender, auction *models.TenderAuction) (time.Time, time.Time) {
+	if tender.SubmissionDeadline == nil {
+		return time.Time{}, time.Time{}
+	}
+
+	start := *tender.SubmissionDeadline
+	end := start.Add(auction.Duration)
+	if auction.ExtendedUntil != nil && auction.ExtendedUntil.After(end) {
+		end = *auction.ExtendedUntil
+	}
+	return start, end
+}
+
+func auctionStatus(tender *models.Tender, auction *models.TenderAuction, now time.Time) AuctionStatus {
+	start, end := auctionWindow(tender, auction)
+	switch {
+	case start.IsZero() || now.Before(start):
+		return AuctionPending
+	case now.Before(end):
+		return AuctionRunning
+	}
+	return AuctionFinished
+}
+
+// roundPrice rounds the price to kopecks, the way it is stored.
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
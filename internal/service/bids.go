@@ -0,0 +1,659 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NewBid struct {
+	TenderID    uuid.UUID `validate:"required"`
+	Name        string    `validate:"required,max=100"`
+	Description string    `validate:"required,max=500"`
+	// LotID is required on tenders with lots
+	LotID uuid.UUID
+	// AuthorType is the kind of author the client claims, the bid is
+	// saved as the user's one
+	AuthorType models.BidAuthorType `validate:"required"`
+	// AuthorID is the author, AuthorUsername is used when it is unset
+	AuthorID       uuid.UUID `validate:"required_without=AuthorUsername"`
+	AuthorUsername string
+	// OrganizationID is the organization the author bids on behalf of
+	OrganizationID uuid.UUID
+}
+
+// BidPatch is a change of a bid, fields left zero are kept.
+type BidPatch struct {
+	BidID uuid.UUID
+	// TenderID moves the bid to another open tender
+	TenderID    uuid.UUID
+	Name        string `validate:"max=100"`
+	Description string `validate:"max=500"`
+	Status      models.BidStatus
+	// LotID moves the bid to another open lot, it is required when the bid
+	// moves to a tender with lots
+	LotID uuid.UUID
+}
+
+// Feedback is a review of a bid, optionally rated from 1 to 5.
+type Feedback struct {
+	Text   string `json:"bidFeedback" validate:"required,max=1000"`
+	Rating *int   `validate:"omitempty,min=1,max=5"`
+}
+
+// ReviewsQuery selects the reviews of an author's bids on a tender.
+type ReviewsQuery struct {
+	TenderID       uuid.UUID
+	AuthorUsername string `validate:"required"`
+	Page           Page
+}
+
+// SubmitBid saves a new bid of the author's organization on an open tender.
+func (s *Service) SubmitBid(ctx context.Context, in NewBid) (Bid, error) {
+	if err := check(in); err != nil {
+		return Bid{}, err
+	}
+
+	if !models.ValidateBidAuthorType(in.AuthorType) {
+		return Bid{}, invalid("invalid authorType")
+	}
+
+	var user *models.Employee
+	var err error
+	if in.AuthorID != uuid.Nil {
+		user, err = s.repo.GetUserById(ctx, in.AuthorID)
+	} else {
+		user, err = s.repo.GetUser(ctx, in.AuthorUsername)
+	}
+	if err != nil {
+		return Bid{}, err
+	}
+
+	orgID, err := s.actingOrganization(ctx, user.ID, in.OrganizationID)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, orgID, policy.CreateBid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	tender, err := s.openTender(ctx, in.TenderID, user)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	lotID, err := s.bidLot(ctx, tender.ID, in.LotID)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	bid := models.Bid{
+		Name:             in.Name,
+		Description:      in.Description,
+		AuthorType:       models.BidAuthorUser,
+		Status:           models.BidCreated,
+		TenderID:         in.TenderID,
+		LotID:            lotID,
+		EmployeeUsername: user.Username,
+		OrganizationID:   orgID,
+	}
+
+	err = s.repo.CreateBid(ctx, &bid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.record(ctx, models.BidCreatedEvent, tender, &bid, string(bid.Status))
+	if err != nil {
+		return Bid{}, err
+	}
+	return bidOf(&bid, user.ID), nil
+}
+
+func (s *Service) BidStatus(ctx context.Context, actor Actor, bidID uuid.UUID) (models.BidStatus, error) {
+	_, bid, _, _, err := s.actorBid(ctx, actor, bidID, policy.ReadBidStatus)
+	if err != nil {
+		return "", err
+	}
+	return bid.Status, nil
+}
+
+func (s *Service) SetBidStatus(ctx context.Context, actor Actor, bidID uuid.UUID, status models.BidStatus) (Bid, error) {
+	if !policy.AuthorStatus(status) {
+		return Bid{}, invalid("bid status must be Created or Published, a published bid is withdrawn")
+	}
+
+	_, bid, tender, _, err := s.actorBid(ctx, actor, bidID, policy.ChangeBidStatus)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	previous, submittedAt := bid.Status, bid.SubmittedAt
+	bid.Status = status
+
+	err = s.repo.UpdateBid(ctx, bid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.bidStatusChanged(ctx, tender, bid, previous, submittedAt)
+	if err != nil {
+		return Bid{}, err
+	}
+	return s.bidOf(ctx, bid)
+}
+
+// EditBid changes the bid as the next version.
+func (s *Service) EditBid(ctx context.Context, actor Actor, patch BidPatch) (Bid, error) {
+	if patch.Status != "" && !policy.AuthorStatus(patch.Status) {
+		return Bid{}, invalid("bid status must be Created or Published, a published bid is withdrawn")
+	}
+
+	if err := check(patch); err != nil {
+		return Bid{}, err
+	}
+
+	user, bid, tender, _, err := s.actorBid(ctx, actor, patch.BidID, policy.EditBidContents)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	// only a draft is published this way, a published bid is withdrawn
+	if patch.Status != "" && patch.Status != bid.Status && bid.Status != models.BidCreated {
+		return Bid{}, response.ErrNoRights
+	}
+
+	if patch.TenderID != uuid.Nil && patch.TenderID != bid.TenderID {
+		tender, err = s.openTender(ctx, patch.TenderID, user)
+		if err != nil {
+			return Bid{}, err
+		}
+
+		bid.LotID, err = s.bidLot(ctx, tender.ID, patch.LotID)
+		if err != nil {
+			return Bid{}, err
+		}
+	} else if patch.LotID != uuid.Nil {
+		bid.LotID, err = s.bidLot(ctx, tender.ID, patch.LotID)
+		if err != nil {
+			return Bid{}, err
+		}
+	}
+
+	previous, submittedAt := bid.Status, bid.SubmittedAt
+
+	if patch.Description != "" {
+		bid.Description = patch.Description
+	}
+	if patch.Name != "" {
+		bid.Name = patch.Name
+	}
+	if patch.Status != "" {
+		bid.Status = patch.Status
+	}
+	if patch.TenderID != uuid.Nil {
+		bid.TenderID = patch.TenderID
+	}
+
+	err = s.repo.UpdateBid(ctx, bid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.bidStatusChanged(ctx, tender, bid, previous, submittedAt)
+	if err != nil {
+		return Bid{}, err
+	}
+	return s.bidOf(ctx, bid)
+}
+
+// Decide approves or rejects the bid. A lot is decided once it has all of
+// its winners, and the tender is closed once none of its lots is open, or
+// on the first decision when it has no lots.
+func (s *Service) Decide(ctx context.Context, actor Actor, bidID uuid.UUID, decision models.BidStatus) (Bid, error) {
+	if !models.ValidateBidDecision(decision) {
+		return Bid{}, invalid("invalid bid decision")
+	}
+
+	var res Bid
+	err := s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		txs := s.withRepo(repo)
+
+		_, bid, tender, _, err := txs.actorBid(ctx, actor, bidID, policy.DecideBid)
+		if err != nil {
+			return err
+		}
+
+		// Decisions on the tender wait for each other here, so the tender
+		// and the bid are read again after the earlier ones.
+		tender, err = repo.LockTender(ctx, tender.ID)
+		if err != nil {
+			return err
+		}
+		bid, err = repo.GetBid(ctx, bid.ID)
+		if err != nil {
+			return err
+		}
+		if tender.Status != models.TenderPublished || bid.TenderID != tender.ID || bid.Status != models.BidPublished {
+			return response.ErrNoRights
+		}
+
+		if tender.Auction && decision == models.BidApproved {
+			leader, err := txs.auctionLeader(ctx, tender)
+			if err != nil {
+				return err
+			}
+			if leader != uuid.Nil && leader != bid.ID {
+				return fmt.Errorf("%w: only the bid with the best auction offer can be approved", response.ErrNoRights)
+			}
+		}
+
+		lots, err := repo.LockTenderLots(ctx, tender.ID)
+		if err != nil {
+			return err
+		}
+
+		var lot *models.TenderLot
+		if bid.LotID != nil {
+			lot = findLot(lots, *bid.LotID)
+			// another decision may have settled the lot in the meantime
+			if lot == nil || lot.Status != models.LotOpen {
+				return response.ErrNoRights
+			}
+		}
+
+		bid.Status = decision
+
+		err = repo.UpdateBid(ctx, bid)
+		if err != nil {
+			return err
+		}
+
+		delta := ReputationDelta{Rejected: 1}
+		if bid.Status == models.BidApproved {
+			delta = ReputationDelta{Approved: 1}
+		}
+
+		err = repo.RecordReputation(ctx, bid, delta)
+		if err != nil {
+			return err
+		}
+
+		err = txs.record(ctx, models.BidDecisionEvent, tender, bid, string(bid.Status))
+		if err != nil {
+			return err
+		}
+
+		err = txs.notify(ctx, models.DecisionNotification, bid.EmployeeUsername, bidNotificationData(tender, bid), "")
+		if err != nil {
+			return err
+		}
+
+		if lot != nil && bid.Status == models.BidApproved {
+			err = txs.settleLot(ctx, lot)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = txs.closeSettledTender(ctx, tender, lots)
+		if err != nil {
+			return err
+		}
+
+		res, err = txs.bidOf(ctx, bid)
+		return err
+	})
+
+	if err != nil {
+		return Bid{}, err
+	}
+	return res, nil
+}
+
+// LeaveFeedback posts a review of the bid from the tender organization.
+func (s *Service) LeaveFeedback(ctx context.Context, actor Actor, bidID uuid.UUID, feedback Feedback) (Bid, error) {
+	if err := check(feedback); err != nil {
+		return Bid{}, err
+	}
+
+	user, bid, tender, _, err := s.actorBid(ctx, actor, bidID, policy.FeedbackBid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.repo.CreateBidMessage(ctx, &models.BidFeedback{
+		Feedback:         feedback.Text,
+		Rating:           feedback.Rating,
+		BidID:            bid.ID,
+		EmployeeUsername: user.Username,
+		OrganizationID:   tender.OrganizationID,
+	})
+	if err != nil {
+		return Bid{}, err
+	}
+
+	if feedback.Rating != nil {
+		err = s.repo.RecordReputation(ctx, bid, ratingDelta(nil, feedback.Rating))
+		if err != nil {
+			return Bid{}, err
+		}
+	}
+
+	err = s.record(ctx, models.BidFeedbackEvent, tender, bid, "")
+	if err != nil {
+		return Bid{}, err
+	}
+
+	data := bidNotificationData(tender, bid)
+	data.Message = feedback.Text
+	err = s.notify(ctx, models.FeedbackNotification, bid.EmployeeUsername, data, "")
+	if err != nil {
+		return Bid{}, err
+	}
+	return s.bidOf(ctx, bid)
+}
+
+// RollbackBid saves the contents of an earlier version as the next one.
+func (s *Service) RollbackBid(ctx context.Context, actor Actor, bidID uuid.UUID, version uint) (Bid, error) {
+	if version == 0 {
+		return Bid{}, invalid("version must be positive")
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	bidVersion, err := s.repo.GetBidVersion(ctx, bidID, version)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	bid, err := s.repo.GetBid(ctx, bidVersion.BidID)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	_, _, err = s.authorizeBid(ctx, user, actor.OrganizationID, bid, policy.RollbackBidVersion)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	// the lot of the version must still be open for bids, the versions
+	// saved before lots were kept stay on the current one
+	lotID := uuid.Nil
+	if bidVersion.LotID != nil {
+		lotID = *bidVersion.LotID
+	} else if bid.LotID != nil {
+		lotID = *bid.LotID
+	}
+	bid.LotID, err = s.bidLot(ctx, bidVersion.TenderID, lotID)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	// the status is kept, it only changes through publishing, withdrawal
+	// and resubmission
+	bid.Name = bidVersion.Name
+	bid.Description = bidVersion.Description
+	bid.TenderID = bidVersion.TenderID
+	bid.AuthorType = bidVersion.AuthorType
+
+	err = s.repo.UpdateBid(ctx, bid)
+	if err != nil {
+		return Bid{}, err
+	}
+	return s.bidOf(ctx, bid)
+}
+
+// WithdrawBid pulls the published bid back with the reason as its next
+// version. The tender owner keeps seeing it, withdrawn, and can't decide on
+// it unless it is resubmitted.
+func (s *Service) WithdrawBid(ctx context.Context, actor Actor, bidID uuid.UUID, reason string) (Bid, error) {
+	if reason == "" {
+		return Bid{}, invalid("reason must be not empty")
+	}
+	if len([]rune(reason)) > 500 {
+		return Bid{}, invalid("reason must be at most 500 characters")
+	}
+
+	_, bid, tender, _, err := s.actorBid(ctx, actor, bidID, policy.WithdrawBid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	previous, submittedAt := bid.Status, bid.SubmittedAt
+	bid.Status = models.BidWithdrawn
+	bid.WithdrawalReason = reason
+
+	err = s.repo.UpdateBid(ctx, bid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.bidStatusChanged(ctx, tender, bid, previous, submittedAt)
+	if err != nil {
+		return Bid{}, err
+	}
+	return s.bidOf(ctx, bid)
+}
+
+// ResubmitBid publishes the withdrawn bid again as its next version, while
+// the tender still accepts bids.
+func (s *Service) ResubmitBid(ctx context.Context, actor Actor, bidID uuid.UUID) (Bid, error) {
+	_, bid, tender, _, err := s.actorBid(ctx, actor, bidID, policy.ResubmitBid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	previous, submittedAt := bid.Status, bid.SubmittedAt
+	bid.Status = models.BidPublished
+	bid.WithdrawalReason = ""
+
+	err = s.repo.UpdateBid(ctx, bid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.bidStatusChanged(ctx, tender, bid, previous, submittedAt)
+	if err != nil {
+		return Bid{}, err
+	}
+	return s.bidOf(ctx, bid)
+}
+
+// bidStatusChanged records the event of the bid's new status and tells the
+// tender's creator when the bid has just been published for the first
+// time, submittedAt being when it was before.
+func (s *Service) bidStatusChanged(ctx context.Context, tender *models.Tender, bid *models.Bid, previous models.BidStatus, submittedAt *time.Time) error {
+	if bid.Status == previous {
+		return nil
+	}
+
+	err := s.record(ctx, models.BidStatusEvent, tender, bid, string(bid.Status))
+	if err != nil {
+		return err
+	}
+
+	if submittedAt == nil && bid.SubmittedAt != nil {
+		return s.notify(ctx, models.NewBidNotification, tender.EmployeeUsername, bidNotificationData(tender, bid), "")
+	}
+	return nil
+}
+
+// Bids passes the bids of the tender the user may see to fn. The tender
+// owner sees every bid put up for a decision; anyone else sees only the bids
+// of their own organizations. Sealed bids come without their contents.
+func (s *Service) Bids(ctx context.Context, actor Actor, tenderID uuid.UUID, page Page, fn func(el Bid) error) error {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return err
+	}
+
+	tender, err := s.visibleTender(ctx, tenderID, user)
+	if err != nil {
+		return err
+	}
+
+	orgIDs, err := s.actingOrganizations(ctx, user.ID, actor.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	filter := BidFilter{TenderID: tender.ID, Author: user.Username, OrganizationIDs: orgIDs, Page: page}
+	if hasID(orgIDs, tender.OrganizationID) {
+		filter.Statuses = policy.TenderOrganizationStatuses
+	}
+
+	authors := make(map[string]uuid.UUID)
+
+	return s.repo.EachBid(ctx, filter, func(el models.Bid) error {
+		authorID, ok := authors[el.EmployeeUsername]
+		if !ok {
+			author, err := s.repo.GetUser(ctx, el.EmployeeUsername)
+			if err != nil {
+				return err
+			}
+			authorID = author.ID
+			authors[el.EmployeeUsername] = authorID
+		}
+
+		bid := bidOf(&el, authorID)
+
+		rel := bidRelation(user, orgIDs, &el, tender)
+		if !policy.CanBid(rel, policy.ReadBidContents, bidState(&el, tender)) {
+			bid.Name = ""
+			bid.Description = ""
+			bid.WithdrawalReason = ""
+			bid.Sealed = true
+		}
+		return fn(bid)
+	})
+}
+
+// MyBids passes the bids authored by the user to fn.
+func (s *Service) MyBids(ctx context.Context, userName string, page Page, fn func(el Bid) error) error {
+	user, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	filter := BidFilter{Author: user.Username, Page: page}
+
+	return s.repo.EachBid(ctx, filter, func(el models.Bid) error {
+		return fn(bidOf(&el, user.ID))
+	})
+}
+
+// BidVersions passes the bid's versions to fn, oldest first, to those who
+// may read the bid's contents.
+func (s *Service) BidVersions(ctx context.Context, actor Actor, bidID uuid.UUID, page Page, fn func(el BidVersion) error) error {
+	_, bid, _, _, err := s.actorBid(ctx, actor, bidID, policy.ReadBidContents)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.EachBidVersion(ctx, bid.ID, page, func(el models.BidVersion) error {
+		return fn(BidVersion{
+			Version:     uint(el.Version),
+			CreatedAt:   el.CreatedAt,
+			Name:        el.Name,
+			Description: el.Description,
+			AuthorType:  el.AuthorType,
+			Status:      el.Status,
+
+			WithdrawalReason: el.WithdrawalReason,
+		})
+	})
+}
+
+// ReviewThreads returns the reviews of the author's bids on the tender as
+// threads, to the members of the tender organization.
+func (s *Service) ReviewThreads(ctx context.Context, actor Actor, query ReviewsQuery) ([]Message, error) {
+	var bidIDs []uuid.UUID
+
+	filter, err := s.reviewedBids(ctx, actor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.EachBid(ctx, filter, func(el models.Bid) error {
+		bidIDs = append(bidIDs, el.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.threads(ctx, bidIDs)
+}
+
+// Reviews passes the reviews ReviewThreads returns to fn one by one, oldest
+// first, without nesting them.
+func (s *Service) Reviews(ctx context.Context, actor Actor, query ReviewsQuery, fn func(el Message) error) error {
+	filter, err := s.reviewedBids(ctx, actor, query)
+	if err != nil {
+		return err
+	}
+
+	names := newNames()
+
+	return s.repo.EachReview(ctx, filter, func(el models.BidFeedback) error {
+		err := s.lookUpNames(ctx, names, []models.BidFeedback{el})
+		if err != nil {
+			return err
+		}
+		return fn(messageOf(&el, names))
+	})
+}
+
+// reviewedBids checks that the actor may read the reviews and returns the
+// filter of the author's bids they are about.
+func (s *Service) reviewedBids(ctx context.Context, actor Actor, query ReviewsQuery) (BidFilter, error) {
+	if err := check(query); err != nil {
+		return BidFilter{}, err
+	}
+
+	requester, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return BidFilter{}, err
+	}
+
+	_, err = s.repo.GetUser(ctx, query.AuthorUsername)
+	if err != nil {
+		return BidFilter{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, query.TenderID)
+	if err != nil {
+		return BidFilter{}, err
+	}
+
+	orgIDs, err := s.actingOrganizations(ctx, requester.ID, actor.OrganizationID)
+	if err != nil {
+		return BidFilter{}, err
+	}
+
+	if !hasID(orgIDs, tender.OrganizationID) {
+		return BidFilter{}, response.ErrNoRights
+	}
+
+	return BidFilter{TenderID: tender.ID, Author: query.AuthorUsername, Page: query.Page}, nil
+}
+
+// bidOf converts the bid with the ID of its author.
+func (s *Service) bidOf(ctx context.Context, bid *models.Bid) (Bid, error) {
+	author, err := s.repo.GetUser(ctx, bid.EmployeeUsername)
+	if err != nil {
+		return Bid{}, err
+	}
+	return bidOf(bid, author.ID), nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+)
+
+type NewCategory struct {
+	Name string `validate:"required,max=100"`
+	// ParentID is uuid.Nil for a root category
+	ParentID uuid.UUID
+}
+
+// CategoryPatch is a change of a category, fields left zero are kept.
+type CategoryPatch struct {
+	CategoryID uuid.UUID
+	Name       string `validate:"max=100"`
+	// ParentID moves the category under another one
+	ParentID uuid.UUID
+	// DetachParent makes the category a root one
+	DetachParent bool
+}
+
+// Categories returns the subcategories of parentID, all of them when it is
+// uuid.Nil, ordered by name.
+func (s *Service) Categories(ctx context.Context, parentID uuid.UUID) ([]Category, error) {
+	if parentID != uuid.Nil {
+		if _, err := s.repo.GetCategory(ctx, parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	categories, err := s.repo.Categories(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Category, 0, len(categories))
+	for _, el := range categories {
+		res = append(res, categoryOf(&el))
+	}
+	return res, nil
+}
+
+// CreateCategory adds a category, only service admins may do it.
+func (s *Service) CreateCategory(ctx context.Context, userName string, in NewCategory) (Category, error) {
+	if err := check(in); err != nil {
+		return Category{}, err
+	}
+
+	err := s.checkAdmin(ctx, userName)
+	if err != nil {
+		return Category{}, err
+	}
+
+	category := models.TenderCategory{Name: in.Name}
+
+	if in.ParentID != uuid.Nil {
+		if _, err := s.repo.GetCategory(ctx, in.ParentID); err != nil {
+			return Category{}, response.Referenced(err)
+		}
+		category.ParentID = &in.ParentID
+	}
+
+	err = s.repo.CreateCategory(ctx, &category)
+	if err != nil {
+		return Category{}, err
+	}
+	return categoryOf(&category), nil
+}
+
+// EditCategory renames or moves a category, only service admins may do it.
+// A category can not be moved under one of its own subcategories.
+func (s *Service) EditCategory(ctx context.Context, userName string, patch CategoryPatch) (Category, error) {
+	if patch.DetachParent && patch.ParentID != uuid.Nil {
+		return Category{}, invalid("parentId and detachParent can't be used together")
+	}
+
+	if err := check(patch); err != nil {
+		return Category{}, err
+	}
+
+	err := s.checkAdmin(ctx, userName)
+	if err != nil {
+		return Category{}, err
+	}
+
+	category, err := s.repo.GetCategory(ctx, patch.CategoryID)
+	if err != nil {
+		return Category{}, err
+	}
+
+	if patch.ParentID != uuid.Nil {
+		descendants, err := s.repo.CategoryDescendants(ctx, []uuid.UUID{category.ID})
+		if err != nil {
+			return Category{}, err
+		}
+
+		if hasID(descendants, patch.ParentID) {
+			return Category{}, response.ErrIncorrectValue
+		}
+
+		if _, err := s.repo.GetCategory(ctx, patch.ParentID); err != nil {
+			return Category{}, response.Referenced(err)
+		}
+		category.ParentID = &patch.ParentID
+	}
+
+	if patch.DetachParent {
+		category.ParentID = nil
+	}
+
+	if patch.Name != "" && patch.Name != category.Name {
+		if _, err := s.repo.GetCategoryByName(ctx, patch.Name); err == nil {
+			return Category{}, response.ErrIncorrectValue
+		}
+		category.Name = patch.Name
+	}
+
+	err = s.repo.SaveCategory(ctx, category)
+	if err != nil {
+		return Category{}, err
+	}
+	return categoryOf(category), nil
+}
+
+// DeleteCategory removes a category that has no subcategories and no
+// tenders, only service admins may do it.
+func (s *Service) DeleteCategory(ctx context.Context, userName string, categoryID uuid.UUID) (Category, error) {
+	err := s.checkAdmin(ctx, userName)
+	if err != nil {
+		return Category{}, err
+	}
+
+	category, err := s.repo.GetCategory(ctx, categoryID)
+	if err != nil {
+		return Category{}, err
+	}
+
+	usage, err := s.repo.CategoryUsage(ctx, category.ID)
+	if err != nil {
+		return Category{}, err
+	}
+
+	if usage > 0 {
+		return Category{}, response.ErrCategoryInUse
+	}
+
+	err = s.repo.DeleteCategory(ctx, category)
+	if err != nil {
+		return Category{}, err
+	}
+	return categoryOf(category), nil
+}
+
+func categoryOf(el *models.TenderCategory) Category {
+	return Category{
+		ID:        el.ID,
+		Name:      el.Name,
+		ParentID:  el.ParentID,
+		CreatedAt: el.CreatedAt,
+	}
+}
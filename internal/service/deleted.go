@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/policy"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeleteTender soft-deletes the tender together with its bids. The bids get
+// the same deletion time as the tender so that RestoreTender brings back
+// exactly them and not the ones deleted on their own.
+func (s *Service) DeleteTender(ctx context.Context, actor Actor, tenderID uuid.UUID) (Deletion, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Deletion{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Deletion{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.DeleteTender)
+	if err != nil {
+		return Deletion{}, err
+	}
+
+	now := time.Now()
+
+	bids, err := s.repo.DeleteTender(ctx, tender.ID, now)
+	if err != nil {
+		return Deletion{}, err
+	}
+
+	return Deletion{ID: tender.ID, DeletedAt: now, Bids: bids}, nil
+}
+
+func (s *Service) RestoreTender(ctx context.Context, actor Actor, tenderID uuid.UUID) (Tender, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	tender, err := s.repo.GetDeletedTender(ctx, tenderID)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.DeleteTender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.repo.RestoreTender(ctx, tender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	res := tenderOf(tender, nil)
+	res.DeletedAt = nil
+	return res, nil
+}
+
+func (s *Service) DeleteBid(ctx context.Context, actor Actor, bidID uuid.UUID) (Deletion, error) {
+	_, bid, _, _, err := s.actorBid(ctx, actor, bidID, policy.DeleteBid)
+	if err != nil {
+		return Deletion{}, err
+	}
+
+	now := time.Now()
+
+	err = s.repo.DeleteBid(ctx, bid.ID, now)
+	if err != nil {
+		return Deletion{}, err
+	}
+
+	return Deletion{ID: bid.ID, DeletedAt: now}, nil
+}
+
+func (s *Service) RestoreBid(ctx context.Context, actor Actor, bidID uuid.UUID) (Bid, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	bid, err := s.repo.GetDeletedBid(ctx, bidID)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	// a bid of a deleted tender comes back only with the tender
+	_, _, err = s.authorizeBid(ctx, user, actor.OrganizationID, bid, policy.DeleteBid)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	err = s.repo.RestoreBid(ctx, bid.ID)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	res := bidOf(bid, uuid.Nil)
+	res.DeletedAt = nil
+	return res, nil
+}
+
+// DeletedTenders lists the deleted tenders to service admins, the latest
+// deleted first.
+func (s *Service) DeletedTenders(ctx context.Context, userName string, page Page) ([]Tender, error) {
+	err := s.checkAdmin(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	tenders, err := s.repo.DeletedTenders(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Tender, 0, len(tenders))
+	for _, el := range tenders {
+		res = append(res, tenderOf(&el, nil))
+	}
+	return res, nil
+}
+
+// DeletedBids lists the deleted bids to service admins, the latest deleted
+// first.
+func (s *Service) DeletedBids(ctx context.Context, userName string, page Page) ([]Bid, error) {
+	err := s.checkAdmin(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	bids, err := s.repo.DeletedBids(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Bid, 0, len(bids))
+	for _, el := range bids {
+		res = append(res, bidOf(&el, uuid.Nil))
+	}
+	return res, nil
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"strings"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tender struct {
+	ID              uuid.UUID
+	Version         uint
+	CreatedAt       time.Time
+	Name            string
+	Description     string
+	ServiceType     models.TenderServiceType
+	Status          models.TenderStatus
+	OrganizationID  uuid.UUID
+	CreatorUsername string
+	// Categories are only set by the use cases that deal with them, the
+	// category of the service type goes first
+	Categories         []uuid.UUID
+	Visibility         models.TenderVisibility
+	SealedBids         bool
+	SubmissionDeadline *time.Time
+	DeletedAt          *time.Time
+}
+
+type TenderVersion struct {
+	Version     uint
+	CreatedAt   time.Time
+	Name        string
+	Description string
+	ServiceType models.TenderServiceType
+	Status      models.TenderStatus
+}
+
+// Lot is a part of a tender decided on its own.
+type Lot struct {
+	ID          uuid.UUID
+	TenderID    uuid.UUID
+	Name        string
+	Description string
+	Quantity    int
+	// Winners is how many bids may be approved in the lot
+	Winners   int
+	Status    models.LotStatus
+	CreatedAt time.Time
+}
+
+// Template is a tender an organization saved to issue again.
+type Template struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Description    string
+	ServiceType    models.TenderServiceType
+	Categories     []uuid.UUID
+	Visibility     models.TenderVisibility
+	SealedBids     bool
+	CreatedAt      time.Time
+}
+
+// Question is a clarification about a tender, Author and OrganizationID are
+// empty when the viewer may not know who asked.
+type Question struct {
+	ID             uuid.UUID
+	TenderID       uuid.UUID
+	Question       string
+	Author         string
+	OrganizationID *uuid.UUID
+	Answer         string
+	Public         bool
+	AnsweredBy     string
+	AnsweredAt     *time.Time
+	// TenderVersion is the tender version the answer amended
+	TenderVersion *uint
+	CreatedAt     time.Time
+}
+
+type AuctionStatus string
+
+const (
+	AuctionPending  AuctionStatus = "Pending"
+	AuctionRunning  AuctionStatus = "Running"
+	AuctionFinished AuctionStatus = "Finished"
+)
+
+// Auction is the auction round of a tender with the ranking its viewer may
+// see.
+type Auction struct {
+	TenderID     uuid.UUID
+	StartPrice   float64
+	MinDecrement float64
+	Duration     time.Duration
+	Extension    time.Duration
+	// StartsAt and EndsAt are zero while the tender has no submission
+	// deadline, EndsAt includes the extensions
+	StartsAt time.Time
+	EndsAt   time.Time
+	Status   AuctionStatus
+	// Ranking is only set for the tender organization and the bidders
+	Ranking []AuctionRank
+}
+
+// AuctionRank is the place of a bid in the auction, ranked by its latest
+// offer with the earlier one first on a tie.
+type AuctionRank struct {
+	Rank int
+	// BidID is only told to the tender organization and the bid's side
+	BidID     *uuid.UUID
+	Price     float64
+	Offers    int
+	OfferedAt time.Time
+	// Own is set on the bids of the viewer's organizations
+	Own bool
+}
+
+type Bid struct {
+	ID             uuid.UUID
+	Version        uint
+	CreatedAt      time.Time
+	Name           string
+	Description    string
+	AuthorType     models.BidAuthorType
+	Status         models.BidStatus
+	TenderID       uuid.UUID
+	LotID          *uuid.UUID
+	OrganizationID uuid.UUID
+	AuthorUsername string
+	AuthorID       uuid.UUID
+	// WithdrawalReason is set while the bid is withdrawn
+	WithdrawalReason string
+	// Sealed is set when the name, description and withdrawal reason are
+	// hidden until the tender's submission deadline
+	Sealed    bool
+	DeletedAt *time.Time
+}
+
+type BidVersion struct {
+	Version     uint
+	CreatedAt   time.Time
+	Name        string
+	Description string
+	AuthorType  models.BidAuthorType
+	Status      models.BidStatus
+	// WithdrawalReason is set on the versions of withdrawn bids
+	WithdrawalReason string
+}
+
+// Message is a message of a bid's feedback thread, feedback left with
+// LeaveFeedback included.
+type Message struct {
+	ID               uuid.UUID
+	BidID            uuid.UUID
+	ParentID         *uuid.UUID
+	Text             string
+	Rating           *int
+	AuthorUsername   string
+	AuthorName       string
+	OrganizationID   uuid.UUID
+	OrganizationName string
+	CreatedAt        time.Time
+	EditedAt         *time.Time
+	// Replies are only set by the use cases returning threads, oldest first
+	Replies []Message
+}
+
+type Invitation struct {
+	ID               uuid.UUID
+	TenderID         uuid.UUID
+	OrganizationID   *uuid.UUID
+	EmployeeUsername string
+	CreatedAt        time.Time
+}
+
+type Category struct {
+	ID        uuid.UUID
+	Name      string
+	ParentID  *uuid.UUID
+	CreatedAt time.Time
+}
+
+type Member struct {
+	UserID    uuid.UUID
+	Username  string
+	FirstName string
+	LastName  string
+	Role      models.OrganizationRole
+}
+
+// Membership is an organization of an employee and their role in it.
+type Membership struct {
+	OrganizationID uuid.UUID
+	Name           string
+	Description    string
+	Type           models.OrganizationType
+	Role           models.OrganizationRole
+}
+
+// Deletion is the result of deleting a tender or a bid.
+type Deletion struct {
+	ID        uuid.UUID
+	DeletedAt time.Time
+	// Bids is the number of bids deleted along with a tender
+	Bids int64
+}
+
+type ReputationStats struct {
+	BidsSubmitted int64
+	BidsApproved  int64
+	BidsRejected  int64
+	AverageRating *float64
+	WinRate       float64
+}
+
+type ServiceTypeReputation struct {
+	ServiceType string
+	ReputationStats
+}
+
+type EmployeeReputation struct {
+	Username string
+	ReputationStats
+	ServiceTypes []ServiceTypeReputation
+}
+
+type Reputation struct {
+	OrganizationID uuid.UUID
+	Name           string
+	ReputationStats
+	ServiceTypes []ServiceTypeReputation
+	Employees    []EmployeeReputation
+}
+
+// Event is a change of a tender or, when BidID is set, of one of its bids.
+type Event struct {
+	ID                uint64
+	Type              models.EventType
+	TenderID          uuid.UUID
+	OrganizationID    uuid.UUID
+	BidID             *uuid.UUID
+	BidOrganizationID *uuid.UUID
+	// Status is the new status, empty for feedback
+	Status    string
+	CreatedAt time.Time
+}
+
+func tenderOf(el *models.Tender, categoryIDs []uuid.UUID) Tender {
+	tender := Tender{
+		ID:                 el.ID,
+		Version:            el.Version,
+		CreatedAt:          el.CreatedAt,
+		Name:               el.Name,
+		Description:        el.Description,
+		ServiceType:        el.ServiceType,
+		Status:             el.Status,
+		OrganizationID:     el.OrganizationID,
+		CreatorUsername:    el.EmployeeUsername,
+		Categories:         categoryIDs,
+		Visibility:         el.Visibility,
+		SealedBids:         el.SealedBids,
+		SubmissionDeadline: el.SubmissionDeadline,
+	}
+	if el.DeletedAt.Valid {
+		deletedAt := el.DeletedAt.Time
+		tender.DeletedAt = &deletedAt
+	}
+	return tender
+}
+
+func bidOf(el *models.Bid, authorID uuid.UUID) Bid {
+	bid := Bid{
+		ID:             el.ID,
+		Version:        uint(el.Version),
+		CreatedAt:      el.CreatedAt,
+		Name:           el.Name,
+		Description:    el.Description,
+		AuthorType:     el.AuthorType,
+		Status:         el.Status,
+		TenderID:       el.TenderID,
+		LotID:          el.LotID,
+		OrganizationID: el.OrganizationID,
+		AuthorUsername: el.EmployeeUsername,
+		AuthorID:       authorID,
+
+		WithdrawalReason: el.WithdrawalReason,
+	}
+	if el.DeletedAt.Valid {
+		deletedAt := el.DeletedAt.Time
+		bid.DeletedAt = &deletedAt
+	}
+	return bid
+}
+
+func eventOf(el *models.Event) Event {
+	return Event{
+		ID:                el.ID,
+		Type:              el.Type,
+		TenderID:          el.TenderID,
+		OrganizationID:    el.OrganizationID,
+		BidID:             el.BidID,
+		BidOrganizationID: el.BidOrganizationID,
+		Status:            el.Status,
+		CreatedAt:         el.CreatedAt,
+	}
+}
+
+// messageOf converts a message with the display names of authors and
+// organizations looked up by names.
+func messageOf(el *models.BidFeedback, names *names) Message {
+	message := Message{
+		ID:               el.ID,
+		BidID:            el.BidID,
+		ParentID:         el.ParentID,
+		Text:             el.Feedback,
+		Rating:           el.Rating,
+		AuthorUsername:   el.EmployeeUsername,
+		AuthorName:       names.authors[el.EmployeeUsername],
+		OrganizationID:   el.OrganizationID,
+		OrganizationName: names.organizations[el.OrganizationID],
+		CreatedAt:        el.CreatedAt,
+	}
+	// saving the new message itself moves updated_at a little
+	if el.UpdatedAt.Sub(el.CreatedAt) >= time.Second {
+		editedAt := el.UpdatedAt
+		message.EditedAt = &editedAt
+	}
+	return message
+}
+
+// displayName is the full name of the employee, or the username when it is
+// not known.
+func displayName(el *models.Employee) string {
+	name := strings.TrimSpace(el.FirstName + " " + el.LastName)
+	if name == "" {
+		return el.Username
+	}
+	return name
+}
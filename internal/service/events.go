@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// eventsBatch is how many events a subscription reads at a time
+	eventsBatch = 100
+	// eventsPollInterval is how often a subscription looks for events
+	// without being woken up, which also keeps idle streams alive
+	eventsPollInterval = 15 * time.Second
+)
+
+// Notifier wakes the subscriptions up when new events may have been saved,
+// on any replica.
+type Notifier interface {
+	Subscribe() (<-chan struct{}, func())
+}
+
+// EventsQuery selects the events of a subscription, fields left zero don't
+// restrict.
+type EventsQuery struct {
+	TenderID       uuid.UUID
+	OrganizationID uuid.UUID
+	// LastEventID is the last event the subscriber has seen, the
+	// subscription starts with the new events when it is nil
+	LastEventID *uint64
+}
+
+// Subscription reads the events its subscriber may see, in order.
+type Subscription struct {
+	svc   *Service
+	user  *models.Employee
+	query EventsQuery
+	last  uint64
+	wake  <-chan struct{}
+	stop  func()
+}
+
+// Subscribe starts reading the events of the tenders and bids the user may
+// see. Filtering by an organization needs to be its member, by a tender to
+// see it. The subscription must be closed.
+func (s *Service) Subscribe(ctx context.Context, actor Actor, query EventsQuery) (*Subscription, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.TenderID != uuid.Nil {
+		_, err = s.visibleTender(ctx, query.TenderID, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if query.OrganizationID != uuid.Nil {
+		err = s.checkResponsible(ctx, user.ID, query.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	last, err := s.repo.LastEventID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if query.LastEventID != nil && *query.LastEventID < last {
+		last = *query.LastEventID
+	}
+
+	sub := &Subscription{svc: s, user: user, query: query, last: last, stop: func() {}}
+	if s.notifier != nil {
+		sub.wake, sub.stop = s.notifier.Subscribe()
+	}
+	return sub, nil
+}
+
+// Next waits for the next events the subscriber may see. It returns no
+// events when none came within the poll interval, so the caller can tell
+// the subscriber it is still there.
+func (sub *Subscription) Next(ctx context.Context) ([]Event, error) {
+	for {
+		events, err := sub.svc.repo.Events(ctx, sub.last, eventsBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		// Memberships may change while the stream is open, so they are
+		// looked up again for every batch.
+		orgIDs, err := sub.svc.repo.UserOrganizations(ctx, sub.user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var res []Event
+		for _, el := range events {
+			// Hidden events are skipped for good, the subscriber resumes
+			// after them too.
+			sub.last = el.ID
+
+			visible, err := sub.visible(ctx, &el, orgIDs)
+			if err != nil {
+				return nil, err
+			}
+			if visible {
+				res = append(res, eventOf(&el))
+			}
+		}
+
+		if len(res) > 0 {
+			return res, nil
+		}
+		if len(events) == eventsBatch {
+			continue
+		}
+
+		timer := time.NewTimer(eventsPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, response.ErrRequestCanceled
+		case <-sub.wake:
+			timer.Stop()
+		case <-timer.C:
+			return nil, nil
+		}
+	}
+}
+
+// Close stops the wake-ups of the subscription.
+func (sub *Subscription) Close() {
+	sub.stop()
+}
+
+// visible tells whether the event passes the filters and the subscriber,
+// responsible for orgIDs, may see it. Bid events are told to the bid's
+// organization and, once the bid is put up for a decision, to the tender's
+// one; tender events to whoever may see the tender, apart from the ones of
+// unpublished tenders which stay within its organization.
+func (sub *Subscription) visible(ctx context.Context, event *models.Event, orgIDs []uuid.UUID) (bool, error) {
+	if sub.query.TenderID != uuid.Nil && event.TenderID != sub.query.TenderID {
+		return false, nil
+	}
+
+	bidOrgID := uuid.Nil
+	if event.BidOrganizationID != nil {
+		bidOrgID = *event.BidOrganizationID
+	}
+
+	if sub.query.OrganizationID != uuid.Nil &&
+		event.OrganizationID != sub.query.OrganizationID && bidOrgID != sub.query.OrganizationID {
+		return false, nil
+	}
+
+	if event.BidID != nil {
+		if hasID(orgIDs, bidOrgID) {
+			return true, nil
+		}
+		return hasID(orgIDs, event.OrganizationID) && event.Status != string(models.BidCreated), nil
+	}
+
+	if hasID(orgIDs, event.OrganizationID) {
+		return true, nil
+	}
+	if event.Status == string(models.TenderCreated) {
+		return false, nil
+	}
+
+	tender, err := sub.svc.repo.GetTender(ctx, event.TenderID)
+	if errors.Is(err, response.ErrTenderNotExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return sub.svc.canSeeTender(ctx, tender, sub.user)
+}
+
+// record saves an event of the tender or, when bid is set, of the bid.
+func (s *Service) record(ctx context.Context, eventType models.EventType, tender *models.Tender, bid *models.Bid, status string) error {
+	event := models.Event{
+		Type:           eventType,
+		TenderID:       tender.ID,
+		OrganizationID: tender.OrganizationID,
+		Status:         status,
+	}
+	if bid != nil {
+		event.BidID = &bid.ID
+		event.BidOrganizationID = &bid.OrganizationID
+	}
+	return s.repo.CreateEvent(ctx, &event)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tender_service/internal/lib/response"
+)
+
+// ImportRow is one imported tender. Error is set when the row could not be
+// decoded.
+type ImportRow struct {
+	Tender NewTender
+	Error  error
+}
+
+type Import struct {
+	Rows []ImportRow
+	// DryRun checks every row, including the creators' rights, and rolls
+	// the import back
+	DryRun bool
+}
+
+// ImportError is the problem with a row, rows are numbered from 1. Error
+// is what creating the row's tender alone would have failed with.
+type ImportError struct {
+	Row   int
+	Error error
+}
+
+type ImportResult struct {
+	DryRun   bool
+	Rows     int
+	Imported int
+	Errors   []ImportError
+	Tenders  []Tender
+}
+
+// errRolledBack makes a transaction roll back after a dry run or a failed
+// row without failing the import itself.
+var errRolledBack = errors.New("rolled back")
+
+// ImportTenders creates the tenders one by one with CreateTender in a single
+// transaction, so every row goes through the same rights and category
+// checks. The import is committed only if all rows are created.
+func (s *Service) ImportTenders(ctx context.Context, in Import) (ImportResult, error) {
+	res := ImportResult{
+		DryRun: in.DryRun,
+		Rows:   len(in.Rows),
+	}
+
+	err := s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		txs := s.withRepo(repo)
+
+		for i, row := range in.Rows {
+			if row.Error != nil {
+				res.Errors = append(res.Errors, ImportError{Row: i + 1, Error: row.Error})
+				continue
+			}
+
+			tender, err := txs.CreateTender(ctx, row.Tender)
+			if err != nil {
+				if errors.Is(err, response.ErrRequestCanceled) || errors.Is(err, response.ErrDeadlineExceeded) ||
+					errors.Is(err, response.ErrInternalError) {
+					return err
+				}
+				res.Errors = append(res.Errors, ImportError{Row: i + 1, Error: err})
+				continue
+			}
+			res.Tenders = append(res.Tenders, tender)
+		}
+
+		if in.DryRun || len(res.Errors) > 0 {
+			return errRolledBack
+		}
+		return nil
+	})
+
+	if errors.Is(err, errRolledBack) {
+		// The tenders of a rolled back import were never created.
+		res.Tenders = nil
+		return res, nil
+	}
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	res.Imported = len(res.Tenders)
+	return res, nil
+}
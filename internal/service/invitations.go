@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+)
+
+// Invite invites an organization, or an employee when orgID is uuid.Nil, to
+// a tender. Inviting someone twice returns the existing invitation.
+func (s *Service) Invite(ctx context.Context, actor Actor, tenderID uuid.UUID, orgID uuid.UUID, employeeUsername string) (Invitation, error) {
+	if (orgID == uuid.Nil) == (employeeUsername == "") {
+		return Invitation{}, invalid("exactly one of organizationId and employeeUsername must be set")
+	}
+	if len([]rune(employeeUsername)) > 50 {
+		return Invitation{}, invalid("employeeUsername must be at most 50 characters")
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.ManageInvitations)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	invitation := models.TenderInvitation{TenderID: tender.ID, CreatedBy: user.Username}
+
+	if orgID != uuid.Nil {
+		organization, err := s.repo.GetOrganization(ctx, orgID)
+		if errors.Is(err, response.ErrOrganizationNotExists) {
+			return Invitation{}, response.ErrIncorrectValue
+		}
+		if err != nil {
+			return Invitation{}, err
+		}
+		invitation.OrganizationID = &organization.ID
+	} else {
+		invitee, err := s.repo.GetUser(ctx, employeeUsername)
+		if errors.Is(err, response.ErrUserNotExists) {
+			return Invitation{}, response.ErrIncorrectValue
+		}
+		if err != nil {
+			return Invitation{}, err
+		}
+		invitation.EmployeeID = &invitee.ID
+	}
+
+	existing, err := s.repo.FindInvitation(ctx, tender.ID, invitation.OrganizationID, invitation.EmployeeID)
+	if err == nil {
+		return invitationOf(existing, employeeUsername), nil
+	}
+	if !errors.Is(err, response.ErrInvitationNotExists) {
+		return Invitation{}, err
+	}
+
+	err = s.repo.CreateInvitation(ctx, &invitation)
+	if err != nil {
+		return Invitation{}, err
+	}
+	return invitationOf(&invitation, employeeUsername), nil
+}
+
+// Invitations returns the invitations to the tender to the members of its
+// organization.
+func (s *Service) Invitations(ctx context.Context, actor Actor, tenderID uuid.UUID) ([]Invitation, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkResponsible(ctx, user.ID, tender.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Invitations(ctx, tender.ID)
+}
+
+func (s *Service) DeleteInvitation(ctx context.Context, actor Actor, tenderID uuid.UUID, invitationID uuid.UUID) error {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.ManageInvitations)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteInvitation(ctx, tender.ID, invitationID)
+}
+
+func invitationOf(el *models.TenderInvitation, employeeUsername string) Invitation {
+	return Invitation{
+		ID:               el.ID,
+		TenderID:         el.TenderID,
+		OrganizationID:   el.OrganizationID,
+		EmployeeUsername: employeeUsername,
+		CreatedAt:        el.CreatedAt,
+	}
+}
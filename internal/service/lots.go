@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+)
+
+// NewLot is a lot added to a tender, Winners defaults to one.
+type NewLot struct {
+	Name        string `validate:"required,max=100"`
+	Description string `validate:"max=500"`
+	Quantity    int    `validate:"min=1"`
+	Winners     int    `validate:"omitempty,min=1"`
+}
+
+// AddLot adds a lot to a tender that is not published yet, so every bid of
+// a tender with lots is made for one of them.
+func (s *Service) AddLot(ctx context.Context, actor Actor, tenderID uuid.UUID, in NewLot) (Lot, error) {
+	if err := check(in); err != nil {
+		return Lot{}, err
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Lot{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Lot{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.EditTender)
+	if err != nil {
+		return Lot{}, err
+	}
+
+	if tender.Status != models.TenderCreated {
+		return Lot{}, response.ErrNoRights
+	}
+
+	if tender.Auction {
+		return Lot{}, invalid("tenders with an auction can't have lots")
+	}
+
+	lot := models.TenderLot{
+		TenderID:    tender.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		Quantity:    in.Quantity,
+		Winners:     in.Winners,
+		Status:      models.LotOpen,
+	}
+	if lot.Winners == 0 {
+		lot.Winners = 1
+	}
+
+	err = s.repo.CreateTenderLot(ctx, &lot)
+	if err != nil {
+		return Lot{}, err
+	}
+	return lotOf(&lot), nil
+}
+
+// Lots returns the lots of a tender the user may see.
+func (s *Service) Lots(ctx context.Context, actor Actor, tenderID uuid.UUID) ([]Lot, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.visibleTender(ctx, tenderID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := s.repo.TenderLots(ctx, tender.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Lot, 0, len(lots))
+	for _, el := range lots {
+		res = append(res, lotOf(&el))
+	}
+	return res, nil
+}
+
+// SetLotStatus decides a lot with fewer winners than it allows, or cancels
+// it, and closes the tender when no lot is left open.
+func (s *Service) SetLotStatus(ctx context.Context, actor Actor, tenderID uuid.UUID, lotID uuid.UUID, status models.LotStatus) (Lot, error) {
+	if !models.ValidateLotDecision(status) {
+		return Lot{}, invalid("incorrect lot status")
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Lot{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Lot{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.DecideOnBid)
+	if err != nil {
+		return Lot{}, err
+	}
+
+	var res Lot
+	err = s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		txs := s.withRepo(repo)
+
+		// the tender is locked before its lots, in the order Decide takes
+		// them, and read again under the lock
+		tender, err := repo.LockTender(ctx, tender.ID)
+		if err != nil {
+			return err
+		}
+
+		lots, err := repo.LockTenderLots(ctx, tender.ID)
+		if err != nil {
+			return err
+		}
+
+		lot := findLot(lots, lotID)
+		if lot == nil {
+			return response.ErrLotNotExists
+		}
+
+		if tender.Status != models.TenderPublished || lot.Status != models.LotOpen {
+			return response.ErrNoRights
+		}
+
+		lot.Status = status
+
+		err = repo.SaveTenderLot(ctx, lot)
+		if err != nil {
+			return err
+		}
+
+		err = txs.closeSettledTender(ctx, tender, lots)
+		if err != nil {
+			return err
+		}
+
+		res = lotOf(lot)
+		return nil
+	})
+
+	if err != nil {
+		return Lot{}, err
+	}
+	return res, nil
+}
+
+// bidLot checks that lotID may be bid on in the tender and returns what
+// goes to the bid's LotID. A tender with lots needs one of its open lots, a
+// tender without takes none.
+func (s *Service) bidLot(ctx context.Context, tenderID uuid.UUID, lotID uuid.UUID) (*uuid.UUID, error) {
+	lots, err := s.repo.TenderLots(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lots) == 0 {
+		if lotID != uuid.Nil {
+			return nil, invalid("tender has no lots")
+		}
+		return nil, nil
+	}
+
+	if lotID == uuid.Nil {
+		return nil, invalid("lotId is required for tenders with lots")
+	}
+
+	lot := findLot(lots, lotID)
+	if lot == nil {
+		return nil, invalid("lot not exists")
+	}
+
+	if lot.Status != models.LotOpen {
+		return nil, response.ErrNoRights
+	}
+	return &lot.ID, nil
+}
+
+// settleLot marks the lot decided once it has all of its winners.
+func (s *Service) settleLot(ctx context.Context, lot *models.TenderLot) error {
+	winners, err := s.repo.LotWinners(ctx, lot.ID)
+	if err != nil {
+		return err
+	}
+
+	if winners < int64(lot.Winners) {
+		return nil
+	}
+
+	lot.Status = models.LotDecided
+	return s.repo.SaveTenderLot(ctx, lot)
+}
+
+// closeSettledTender closes the tender once none of its lots, locked by the
+// caller, is open. A tender without lots has a single outcome and closes
+// on its first decision.
+func (s *Service) closeSettledTender(ctx context.Context, tender *models.Tender, lots []models.TenderLot) error {
+	for _, el := range lots {
+		if el.Status == models.LotOpen {
+			return nil
+		}
+	}
+
+	if tender.Status == models.TenderClosed {
+		return nil
+	}
+	tender.Status = models.TenderClosed
+
+	err := s.repo.UpdateTender(ctx, tender)
+	if err != nil {
+		return err
+	}
+	return s.record(ctx, models.TenderStatusEvent, tender, nil, string(tender.Status))
+}
+
+func findLot(lots []models.TenderLot, lotID uuid.UUID) *models.TenderLot {
+	for i := range lots {
+		if lots[i].ID == lotID {
+			return &lots[i]
+		}
+	}
+	return nil
+}
+
+func lotOf(el *models.TenderLot) Lot {
+	return Lot{
+		ID:          el.ID,
+		TenderID:    el.TenderID,
+		Name:        el.Name,
+		Description: el.Description,
+		Quantity:    el.Quantity,
+		Winners:     el.Winners,
+		Status:      el.Status,
+		CreatedAt:   el.CreatedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+)
+
+// Members returns the members of the organization to its members and to
+// service admins.
+func (s *Service) Members(ctx context.Context, userName string, orgID uuid.UUID) ([]Member, error) {
+	user, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isAdmin(user.Username) {
+		err = s.checkResponsible(ctx, user.ID, orgID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	members, err := s.repo.Members(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Member, 0, len(members))
+	for _, el := range members {
+		res = append(res, Member{
+			UserID:    el.EmployeeID,
+			Username:  el.Employee.Username,
+			FirstName: el.Employee.FirstName,
+			LastName:  el.Employee.LastName,
+			Role:      el.Role,
+		})
+	}
+	return res, nil
+}
+
+// SetMemberRole changes the role of an organization member. Only admins of
+// the organization and service admins may do it, and the last admin of an
+// organization can not be demoted.
+func (s *Service) SetMemberRole(ctx context.Context, userName string, orgID uuid.UUID, memberID uuid.UUID, role models.OrganizationRole) error {
+	if !models.ValidateOrganizationRole(role) {
+		return invalid("incorrect role")
+	}
+
+	user, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	if !s.isAdmin(user.Username) {
+		err = s.checkPermission(ctx, user.ID, orgID, policy.ManageMembers)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		admins, err := repo.LockAdmins(ctx, orgID)
+		if err != nil {
+			return err
+		}
+
+		current, err := repo.MemberRole(ctx, memberID, orgID)
+		if err != nil {
+			return err
+		}
+
+		if current == models.AdminRole && role != models.AdminRole && admins <= 1 {
+			return response.ErrLastAdmin
+		}
+
+		return repo.UpdateMemberRole(ctx, orgID, memberID, role)
+	})
+}
+
+// MyOrganizations returns the organizations of the user with their role in
+// each.
+func (s *Service) MyOrganizations(ctx context.Context, userName string) ([]Membership, error) {
+	user, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.Memberships(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Membership, 0, len(members))
+	for _, el := range members {
+		res = append(res, Membership{
+			OrganizationID: el.OrganizationID,
+			Name:           el.Organization.Name,
+			Description:    el.Organization.Description,
+			Type:           el.Organization.Type,
+			Role:           el.Role,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NewMessage struct {
+	BidID uuid.UUID
+	// ParentID is the message replied to
+	ParentID *uuid.UUID
+	Text     string `json:"message" validate:"required,max=1000"`
+	// Rating may only be set by the tender organization
+	Rating *int `validate:"omitempty,min=1,max=5"`
+}
+
+// MessagePatch is a change of a message, fields left zero are kept.
+type MessagePatch struct {
+	BidID     uuid.UUID
+	MessageID uuid.UUID
+	Text      string `json:"message" validate:"max=1000"`
+	Rating    *int   `validate:"omitempty,min=1,max=5"`
+}
+
+// PostMessage posts to the feedback thread of a bid. Both the tender
+// organization and the bid's side may post, but only the former may rate.
+func (s *Service) PostMessage(ctx context.Context, actor Actor, in NewMessage) (Message, error) {
+	if err := check(in); err != nil {
+		return Message{}, err
+	}
+
+	user, bid, tender, rel, err := s.actorBid(ctx, actor, in.BidID, policy.PostBidMessage)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if in.Rating != nil && rel != policy.TenderOrganization {
+		return Message{}, response.ErrIncorrectValue
+	}
+
+	if in.ParentID != nil {
+		_, err = s.repo.GetBidMessage(ctx, bid.ID, *in.ParentID)
+		if err != nil {
+			return Message{}, err
+		}
+	}
+
+	orgID := bid.OrganizationID
+	if rel == policy.TenderOrganization {
+		orgID = tender.OrganizationID
+	}
+
+	message := models.BidFeedback{
+		Feedback:         in.Text,
+		BidID:            bid.ID,
+		ParentID:         in.ParentID,
+		Rating:           in.Rating,
+		EmployeeUsername: user.Username,
+		OrganizationID:   orgID,
+	}
+
+	err = s.repo.CreateBidMessage(ctx, &message)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if message.Rating != nil {
+		err = s.repo.RecordReputation(ctx, bid, ratingDelta(nil, message.Rating))
+		if err != nil {
+			return Message{}, err
+		}
+	}
+
+	err = s.record(ctx, models.BidFeedbackEvent, tender, bid, "")
+	if err != nil {
+		return Message{}, err
+	}
+
+	// The bid's author hears about the messages of the tender's side.
+	if rel == policy.TenderOrganization {
+		data := bidNotificationData(tender, bid)
+		data.Message = message.Feedback
+		err = s.notify(ctx, models.FeedbackNotification, bid.EmployeeUsername, data, "")
+		if err != nil {
+			return Message{}, err
+		}
+	}
+
+	return s.messageOf(ctx, &message)
+}
+
+// EditMessage lets the author change their message within the edit window
+// while they still have the right to post to the thread.
+func (s *Service) EditMessage(ctx context.Context, actor Actor, patch MessagePatch) (Message, error) {
+	if err := check(patch); err != nil {
+		return Message{}, err
+	}
+
+	user, bid, tender, _, err := s.actorBid(ctx, actor, patch.BidID, policy.PostBidMessage)
+	if err != nil {
+		return Message{}, err
+	}
+
+	message, err := s.repo.GetBidMessage(ctx, bid.ID, patch.MessageID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if message.EmployeeUsername != user.Username {
+		return Message{}, response.ErrNoRights
+	}
+
+	if time.Since(message.CreatedAt) > s.feedbackEditWindow {
+		return Message{}, response.ErrEditWindowExpired
+	}
+
+	if patch.Rating != nil && message.OrganizationID != tender.OrganizationID {
+		return Message{}, response.ErrIncorrectValue
+	}
+
+	previous := message.Rating
+
+	if patch.Text != "" {
+		message.Feedback = patch.Text
+	}
+	if patch.Rating != nil {
+		message.Rating = patch.Rating
+	}
+
+	err = s.repo.SaveBidMessage(ctx, message)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if patch.Rating != nil {
+		err = s.repo.RecordReputation(ctx, bid, ratingDelta(previous, patch.Rating))
+		if err != nil {
+			return Message{}, err
+		}
+	}
+
+	return s.messageOf(ctx, message)
+}
+
+// BidMessages returns the feedback threads of the bid to those who may read
+// its contents.
+func (s *Service) BidMessages(ctx context.Context, actor Actor, bidID uuid.UUID) ([]Message, error) {
+	_, bid, _, _, err := s.actorBid(ctx, actor, bidID, policy.ReadBidContents)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.threads(ctx, []uuid.UUID{bid.ID})
+}
+
+// names are the display names of message authors and the names of their
+// organizations.
+type names struct {
+	authors       map[string]string
+	organizations map[uuid.UUID]string
+}
+
+func newNames() *names {
+	return &names{
+		authors:       make(map[string]string),
+		organizations: make(map[uuid.UUID]string),
+	}
+}
+
+// lookUpNames adds the names of the messages' authors and organizations
+// that are not known yet.
+func (s *Service) lookUpNames(ctx context.Context, names *names, messages []models.BidFeedback) error {
+	var usernames []string
+	var orgIDs []uuid.UUID
+	for _, el := range messages {
+		if _, ok := names.authors[el.EmployeeUsername]; !ok {
+			usernames = append(usernames, el.EmployeeUsername)
+		}
+		if _, ok := names.organizations[el.OrganizationID]; !ok {
+			orgIDs = append(orgIDs, el.OrganizationID)
+		}
+	}
+
+	if len(usernames) > 0 {
+		employees, err := s.repo.Employees(ctx, usernames)
+		if err != nil {
+			return err
+		}
+		for _, el := range employees {
+			names.authors[el.Username] = displayName(&el)
+		}
+		// unknown authors are not looked up again
+		for _, el := range usernames {
+			if _, ok := names.authors[el]; !ok {
+				names.authors[el] = ""
+			}
+		}
+	}
+
+	if len(orgIDs) > 0 {
+		organizations, err := s.repo.Organizations(ctx, orgIDs)
+		if err != nil {
+			return err
+		}
+		for _, el := range organizations {
+			names.organizations[el.ID] = el.Name
+		}
+		for _, el := range orgIDs {
+			if _, ok := names.organizations[el]; !ok {
+				names.organizations[el] = ""
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) messageOf(ctx context.Context, message *models.BidFeedback) (Message, error) {
+	names := newNames()
+
+	err := s.lookUpNames(ctx, names, []models.BidFeedback{*message})
+	if err != nil {
+		return Message{}, err
+	}
+	return messageOf(message, names), nil
+}
+
+// threads returns the messages of the bids nested under the ones they reply
+// to, keeping their order. Replies to messages missing from the list become
+// roots.
+func (s *Service) threads(ctx context.Context, bidIDs []uuid.UUID) ([]Message, error) {
+	messages, err := s.repo.BidMessages(ctx, bidIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	names := newNames()
+
+	err = s.lookUpNames(ctx, names, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uuid.UUID]struct{}, len(messages))
+	for _, el := range messages {
+		ids[el.ID] = struct{}{}
+	}
+
+	children := make(map[uuid.UUID][]models.BidFeedback)
+	var roots []models.BidFeedback
+	for _, el := range messages {
+		if el.ParentID != nil {
+			if _, ok := ids[*el.ParentID]; ok {
+				children[*el.ParentID] = append(children[*el.ParentID], el)
+				continue
+			}
+		}
+		roots = append(roots, el)
+	}
+
+	var build func(el models.BidFeedback) Message
+	build = func(el models.BidFeedback) Message {
+		message := messageOf(&el, names)
+		message.Replies = make([]Message, 0, len(children[el.ID]))
+		for _, child := range children[el.ID] {
+			message.Replies = append(message.Replies, build(child))
+		}
+		return message
+	}
+
+	threads := make([]Message, 0, len(roots))
+	for _, el := range roots {
+		threads = append(threads, build(el))
+	}
+	return threads, nil
+}
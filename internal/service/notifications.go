@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationSettings is where and which emails an employee wants.
+type NotificationSettings struct {
+	Email string `validate:"omitempty,email,max=255"`
+	// Locale is the language of the emails, the default one when empty
+	Locale        string `validate:"omitempty,oneof=en ru"`
+	NewBid        bool
+	Decision      bool
+	Feedback      bool
+	TenderClosing bool
+}
+
+// NotificationSettings returns the user's settings, every kind of email
+// being on until they turn it off.
+func (s *Service) NotificationSettings(ctx context.Context, userName string) (NotificationSettings, error) {
+	user, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return NotificationSettings{}, err
+	}
+
+	settings, err := s.notificationSettings(ctx, user.ID)
+	if err != nil {
+		return NotificationSettings{}, err
+	}
+	return notificationSettingsOf(settings), nil
+}
+
+func (s *Service) SetNotificationSettings(ctx context.Context, userName string, in NotificationSettings) (NotificationSettings, error) {
+	if err := check(in); err != nil {
+		return NotificationSettings{}, err
+	}
+
+	user, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return NotificationSettings{}, err
+	}
+
+	settings := models.NotificationSettings{
+		EmployeeID:    user.ID,
+		Email:         in.Email,
+		Locale:        in.Locale,
+		NewBid:        in.NewBid,
+		Decision:      in.Decision,
+		Feedback:      in.Feedback,
+		TenderClosing: in.TenderClosing,
+	}
+
+	err = s.repo.SaveNotificationSettings(ctx, &settings)
+	if err != nil {
+		return NotificationSettings{}, err
+	}
+	return notificationSettingsOf(&settings), nil
+}
+
+func (s *Service) notificationSettings(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error) {
+	settings, err := s.repo.GetNotificationSettings(ctx, userID)
+	if errors.Is(err, response.ErrNotificationSettingsNotExists) {
+		return &models.NotificationSettings{
+			EmployeeID:    userID,
+			NewBid:        true,
+			Decision:      true,
+			Feedback:      true,
+			TenderClosing: true,
+		}, nil
+	}
+	return settings, err
+}
+
+// notify queues an email of the kind to the employee when they have an
+// email and want that kind. A non-empty dedupeKey keeps it from being
+// queued more than once.
+func (s *Service) notify(ctx context.Context, kind models.NotificationKind, userName string, data models.NotificationData, dedupeKey string) error {
+	user, err := s.repo.GetUser(ctx, userName)
+	if errors.Is(err, response.ErrUserNotExists) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	settings, err := s.notificationSettings(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if settings.Email == "" || !settings.Wants(kind) {
+		return nil
+	}
+
+	notification := models.Notification{
+		Kind:             kind,
+		EmployeeUsername: user.Username,
+		Email:            settings.Email,
+		Locale:           settings.Locale,
+		Data:             data,
+	}
+	if dedupeKey != "" {
+		notification.DedupeKey = &dedupeKey
+	}
+	return s.repo.EnqueueNotification(ctx, &notification)
+}
+
+// NotifyClosingTenders reminds the authors of the bids on the tenders whose
+// submission deadline comes within window, once per tender and author. It
+// returns how many tenders are closing.
+func (s *Service) NotifyClosingTenders(ctx context.Context, window time.Duration) (int, error) {
+	now := time.Now()
+	tenders, err := s.repo.ClosingTenders(ctx, now, now.Add(window))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, tender := range tenders {
+		authors := make(map[string]struct{})
+		filter := BidFilter{TenderID: tender.ID, Statuses: []models.BidStatus{models.BidCreated, models.BidPublished}, Page: unpaged}
+		err = s.repo.EachBid(ctx, filter, func(el models.Bid) error {
+			authors[el.EmployeeUsername] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		data := models.NotificationData{
+			TenderID:   tender.ID,
+			TenderName: tender.Name,
+			Deadline:   tender.SubmissionDeadline,
+		}
+		for author := range authors {
+			key := fmt.Sprintf("%s:%s:%s", models.TenderClosingNotification, tender.ID, author)
+			err = s.notify(ctx, models.TenderClosingNotification, author, data, key)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(tenders), nil
+}
+
+// bidNotificationData is what the emails about the bid are rendered with.
+// The name of a sealed bid is left out until the submission deadline.
+func bidNotificationData(tender *models.Tender, bid *models.Bid) models.NotificationData {
+	data := models.NotificationData{
+		TenderID:   tender.ID,
+		TenderName: tender.Name,
+		BidID:      &bid.ID,
+		BidName:    bid.Name,
+		Status:     string(bid.Status),
+	}
+	if tender.SealedBids && !submissionClosed(tender) {
+		data.BidName = ""
+	}
+	return data
+}
+
+func notificationSettingsOf(el *models.NotificationSettings) NotificationSettings {
+	return NotificationSettings{
+		Email:         el.Email,
+		Locale:        el.Locale,
+		NewBid:        el.NewBid,
+		Decision:      el.Decision,
+		Feedback:      el.Feedback,
+		TenderClosing: el.TenderClosing,
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewQuestion is a clarification asked on behalf of the author's
+// organization.
+type NewQuestion struct {
+	TenderID uuid.UUID
+	Question string `validate:"required,max=1000"`
+}
+
+// Answer replies to a question. A non-empty Description also amends the
+// tender's description as its next version.
+type Answer struct {
+	Text        string `json:"answer" validate:"required,max=1000"`
+	Public      bool
+	Description string `validate:"max=500"`
+}
+
+// AskQuestion saves a question about a published tender by a bidder that
+// may bid on it.
+func (s *Service) AskQuestion(ctx context.Context, actor Actor, in NewQuestion) (Question, error) {
+	if err := check(in); err != nil {
+		return Question{}, err
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Question{}, err
+	}
+
+	orgID, err := s.actingOrganization(ctx, user.ID, actor.OrganizationID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, orgID, policy.CreateBid)
+	if err != nil {
+		return Question{}, err
+	}
+
+	tender, err := s.openTender(ctx, in.TenderID, user)
+	if err != nil {
+		return Question{}, err
+	}
+
+	if tender.OrganizationID == orgID {
+		return Question{}, response.ErrNoRights
+	}
+
+	question := models.TenderQuestion{
+		TenderID:         tender.ID,
+		Question:         in.Question,
+		EmployeeUsername: user.Username,
+		OrganizationID:   orgID,
+	}
+
+	err = s.repo.CreateQuestion(ctx, &question)
+	if err != nil {
+		return Question{}, err
+	}
+	return questionOf(&question, true), nil
+}
+
+// AnswerQuestion answers a question about a published tender once.
+func (s *Service) AnswerQuestion(ctx context.Context, actor Actor, tenderID uuid.UUID, questionID uuid.UUID, in Answer) (Question, error) {
+	if err := check(in); err != nil {
+		return Question{}, err
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Question{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.EditTender)
+	if err != nil {
+		return Question{}, err
+	}
+
+	question, err := s.repo.GetQuestion(ctx, tender.ID, questionID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	if tender.Status != models.TenderPublished || question.AnsweredAt != nil {
+		return Question{}, response.ErrNoRights
+	}
+
+	err = s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		if in.Description != "" {
+			amended, err := s.withRepo(repo).EditTender(ctx, actor, TenderPatch{
+				TenderID:    tender.ID,
+				Description: in.Description,
+			})
+			if err != nil {
+				return err
+			}
+			question.TenderVersion = &amended.Version
+		}
+
+		now := time.Now()
+		question.Answer = in.Text
+		question.Public = in.Public
+		question.AnsweredBy = user.Username
+		question.AnsweredAt = &now
+
+		return repo.SaveQuestion(ctx, question)
+	})
+
+	if err != nil {
+		return Question{}, err
+	}
+	return questionOf(question, true), nil
+}
+
+// Questions returns the questions about a tender the user may see. The
+// tender's organization sees all of them, other users the public answers
+// without who asked and the questions of their own organizations.
+func (s *Service) Questions(ctx context.Context, actor Actor, tenderID uuid.UUID) ([]Question, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.visibleTender(ctx, tenderID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	orgIDs, err := s.repo.UserOrganizations(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.repo.TenderQuestions(ctx, tender.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := hasID(orgIDs, tender.OrganizationID)
+
+	res := make([]Question, 0, len(questions))
+	for _, el := range questions {
+		own := owner || hasID(orgIDs, el.OrganizationID)
+		if !own && (!el.Public || el.AnsweredAt == nil) {
+			continue
+		}
+		res = append(res, questionOf(&el, own))
+	}
+	return res, nil
+}
+
+// questionOf hides who asked the question unless the viewer is on the
+// tender's or the asker's side.
+func questionOf(el *models.TenderQuestion, own bool) Question {
+	res := Question{
+		ID:            el.ID,
+		TenderID:      el.TenderID,
+		Question:      el.Question,
+		Answer:        el.Answer,
+		Public:        el.Public,
+		AnsweredAt:    el.AnsweredAt,
+		TenderVersion: el.TenderVersion,
+		CreatedAt:     el.CreatedAt,
+	}
+
+	if own {
+		res.Author = el.EmployeeUsername
+		res.OrganizationID = &el.OrganizationID
+		res.AnsweredBy = el.AnsweredBy
+	}
+	return res
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository is the data the use cases work with, implemented by the gorm
+// storage. It holds no rules: lookups of missing rows fail with the
+// response.Err*NotExists errors and everything else is done as asked.
+type Repository interface {
+	// WithinTransaction runs fn with a repository working inside a single
+	// transaction, committed when fn returns no error.
+	WithinTransaction(ctx context.Context, fn func(repo Repository) error) error
+
+	GetUser(ctx context.Context, userName string) (*models.Employee, error)
+	GetUserById(ctx context.Context, userID uuid.UUID) (*models.Employee, error)
+	Employees(ctx context.Context, userNames []string) ([]models.Employee, error)
+
+	GetOrganization(ctx context.Context, orgID uuid.UUID) (*models.Organization, error)
+	Organizations(ctx context.Context, orgIDs []uuid.UUID) ([]models.Organization, error)
+	// UserOrganizations returns the organizations the user is responsible for.
+	UserOrganizations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	// MemberRole fails with ErrMemberNotExists when the user is not a
+	// member of the organization.
+	MemberRole(ctx context.Context, userID uuid.UUID, orgID uuid.UUID) (models.OrganizationRole, error)
+	// Members returns the members of the organization with their employees.
+	Members(ctx context.Context, orgID uuid.UUID) ([]models.OrganizationResponsible, error)
+	// Memberships returns the memberships of the user with their
+	// organizations.
+	Memberships(ctx context.Context, userID uuid.UUID) ([]models.OrganizationResponsible, error)
+	// LockAdmins locks the admins of the organization until the end of the
+	// transaction and returns how many there are.
+	LockAdmins(ctx context.Context, orgID uuid.UUID) (int, error)
+	UpdateMemberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role models.OrganizationRole) error
+
+	GetTender(ctx context.Context, tenderID uuid.UUID) (*models.Tender, error)
+	// LockTender is GetTender locking the tender row until the end of the
+	// transaction, which serializes the decisions on the tender.
+	LockTender(ctx context.Context, tenderID uuid.UUID) (*models.Tender, error)
+	GetDeletedTender(ctx context.Context, tenderID uuid.UUID) (*models.Tender, error)
+	// CreateTender saves a new tender with its first version.
+	CreateTender(ctx context.Context, tender *models.Tender) error
+	// UpdateTender saves the tender as its next version.
+	UpdateTender(ctx context.Context, tender *models.Tender) error
+	GetTenderVersion(ctx context.Context, tenderID uuid.UUID, version uint) (*models.TenderVersion, error)
+	EachTender(ctx context.Context, filter TenderFilter, fn func(el models.Tender) error) error
+	EachTenderVersion(ctx context.Context, tenderID uuid.UUID, page Page, fn func(el models.TenderVersion) error) error
+	// DeleteTender soft-deletes the tender and its bids at the same time
+	// and returns how many bids were deleted.
+	DeleteTender(ctx context.Context, tenderID uuid.UUID, at time.Time) (int64, error)
+	// RestoreTender brings back the deleted tender with the bids deleted
+	// together with it.
+	RestoreTender(ctx context.Context, tender *models.Tender) error
+	DeletedTenders(ctx context.Context, page Page) ([]models.Tender, error)
+
+	GetCategory(ctx context.Context, categoryID uuid.UUID) (*models.TenderCategory, error)
+	GetCategoryByName(ctx context.Context, name string) (*models.TenderCategory, error)
+	// Categories returns the subcategories of parentID, all of them when it
+	// is uuid.Nil, ordered by name.
+	Categories(ctx context.Context, parentID uuid.UUID) ([]models.TenderCategory, error)
+	// CategoryDescendants returns the given categories together with all of
+	// their subcategories.
+	CategoryDescendants(ctx context.Context, categoryIDs []uuid.UUID) ([]uuid.UUID, error)
+	// CreateCategory fails with ErrIncorrectValue when the name is taken.
+	CreateCategory(ctx context.Context, category *models.TenderCategory) error
+	SaveCategory(ctx context.Context, category *models.TenderCategory) error
+	DeleteCategory(ctx context.Context, category *models.TenderCategory) error
+	// CategoryUsage counts the subcategories and the tenders of a category.
+	CategoryUsage(ctx context.Context, categoryID uuid.UUID) (int64, error)
+	SetTenderCategories(ctx context.Context, tenderID uuid.UUID, categoryIDs []uuid.UUID) error
+	// ExtraTenderCategories returns the categories linked to the tender
+	// besides the one of its service type.
+	ExtraTenderCategories(ctx context.Context, tenderID uuid.UUID, serviceType string) ([]uuid.UUID, error)
+
+	// FindInvitation looks the invitation of an organization or of an
+	// employee to the tender up.
+	FindInvitation(ctx context.Context, tenderID uuid.UUID, orgID *uuid.UUID, employeeID *uuid.UUID) (*models.TenderInvitation, error)
+	CreateInvitation(ctx context.Context, invitation *models.TenderInvitation) error
+	Invitations(ctx context.Context, tenderID uuid.UUID) ([]Invitation, error)
+	// Invited tells whether the user or one of the organizations is invited
+	// to the tender.
+	Invited(ctx context.Context, tenderID uuid.UUID, userID uuid.UUID, orgIDs []uuid.UUID) (bool, error)
+	DeleteInvitation(ctx context.Context, tenderID uuid.UUID, invitationID uuid.UUID) error
+
+	// GetTenderLot fails with ErrLotNotExists when the lot is not one of
+	// the tender's.
+	GetTenderLot(ctx context.Context, tenderID uuid.UUID, lotID uuid.UUID) (*models.TenderLot, error)
+	// TenderLots returns the lots of the tender in the order they were
+	// added.
+	TenderLots(ctx context.Context, tenderID uuid.UUID) ([]models.TenderLot, error)
+	// LockTenderLots is TenderLots locking the lots until the end of the
+	// transaction.
+	LockTenderLots(ctx context.Context, tenderID uuid.UUID) ([]models.TenderLot, error)
+	CreateTenderLot(ctx context.Context, lot *models.TenderLot) error
+	SaveTenderLot(ctx context.Context, lot *models.TenderLot) error
+	// LotWinners counts the approved bids of the lot.
+	LotWinners(ctx context.Context, lotID uuid.UUID) (int64, error)
+
+	// GetAuction fails with ErrAuctionNotExists when the tender has no
+	// auction.
+	GetAuction(ctx context.Context, tenderID uuid.UUID) (*models.TenderAuction, error)
+	// LockAuction is GetAuction locking the auction until the end of the
+	// transaction, which serializes the offers on the tender.
+	LockAuction(ctx context.Context, tenderID uuid.UUID) (*models.TenderAuction, error)
+	// SaveAuction creates the auction of the tender or replaces its
+	// settings.
+	SaveAuction(ctx context.Context, auction *models.TenderAuction) error
+	CreateOffer(ctx context.Context, offer *models.AuctionOffer) error
+	// AuctionOffers returns the offers made on the tender, oldest first.
+	AuctionOffers(ctx context.Context, tenderID uuid.UUID) ([]models.AuctionOffer, error)
+
+	// GetQuestion fails with ErrQuestionNotExists when the question is not
+	// one of the tender's.
+	GetQuestion(ctx context.Context, tenderID uuid.UUID, questionID uuid.UUID) (*models.TenderQuestion, error)
+	// TenderQuestions returns the questions about the tender, oldest first.
+	TenderQuestions(ctx context.Context, tenderID uuid.UUID) ([]models.TenderQuestion, error)
+	CreateQuestion(ctx context.Context, question *models.TenderQuestion) error
+	SaveQuestion(ctx context.Context, question *models.TenderQuestion) error
+
+	GetTemplate(ctx context.Context, templateID uuid.UUID) (*models.TenderTemplate, error)
+	// OrganizationTemplates returns the organization's templates by name.
+	OrganizationTemplates(ctx context.Context, orgID uuid.UUID) ([]models.TenderTemplate, error)
+	CreateTemplate(ctx context.Context, template *models.TenderTemplate) error
+	DeleteTemplate(ctx context.Context, templateID uuid.UUID) error
+
+	GetBid(ctx context.Context, bidID uuid.UUID) (*models.Bid, error)
+	GetDeletedBid(ctx context.Context, bidID uuid.UUID) (*models.Bid, error)
+	// CreateBid saves a new bid with its first version.
+	CreateBid(ctx context.Context, bid *models.Bid) error
+	// UpdateBid saves the bid as its next version, marking the time it was
+	// first published.
+	UpdateBid(ctx context.Context, bid *models.Bid) error
+	GetBidVersion(ctx context.Context, bidID uuid.UUID, version uint) (*models.BidVersion, error)
+	EachBid(ctx context.Context, filter BidFilter, fn func(el models.Bid) error) error
+	EachBidVersion(ctx context.Context, bidID uuid.UUID, page Page, fn func(el models.BidVersion) error) error
+	DeleteBid(ctx context.Context, bidID uuid.UUID, at time.Time) error
+	RestoreBid(ctx context.Context, bidID uuid.UUID) error
+	DeletedBids(ctx context.Context, page Page) ([]models.Bid, error)
+
+	GetBidMessage(ctx context.Context, bidID uuid.UUID, messageID uuid.UUID) (*models.BidFeedback, error)
+	// BidMessages returns the messages of the bids, oldest first.
+	BidMessages(ctx context.Context, bidIDs []uuid.UUID) ([]models.BidFeedback, error)
+	// EachReview passes the messages of the bids selected by filter to fn,
+	// oldest first.
+	EachReview(ctx context.Context, filter BidFilter, fn func(el models.BidFeedback) error) error
+	CreateBidMessage(ctx context.Context, message *models.BidFeedback) error
+	SaveBidMessage(ctx context.Context, message *models.BidFeedback) error
+
+	// RecordReputation adds delta to the counters of the bid's organization
+	// and author on the service type of the bid's tender.
+	RecordReputation(ctx context.Context, bid *models.Bid, delta ReputationDelta) error
+	ReputationStats(ctx context.Context, orgID uuid.UUID) ([]models.ReputationStat, error)
+
+	// CreateEvent saves the event under the next ID of the event sequence
+	// and announces it to the streams of every replica.
+	CreateEvent(ctx context.Context, event *models.Event) error
+	// Events returns up to limit events following afterID, oldest first.
+	Events(ctx context.Context, afterID uint64, limit int) ([]models.Event, error)
+	LastEventID(ctx context.Context) (uint64, error)
+
+	// GetNotificationSettings fails with ErrNotificationSettingsNotExists
+	// when the employee has not saved any.
+	GetNotificationSettings(ctx context.Context, employeeID uuid.UUID) (*models.NotificationSettings, error)
+	SaveNotificationSettings(ctx context.Context, settings *models.NotificationSettings) error
+	// EnqueueNotification queues the email, unless one with the same
+	// dedupe key has been queued before.
+	EnqueueNotification(ctx context.Context, notification *models.Notification) error
+	// ClosingTenders returns the published tenders whose submission
+	// deadline falls between from and to.
+	ClosingTenders(ctx context.Context, from time.Time, to time.Time) ([]models.Tender, error)
+}
+
+// TenderFilter selects tenders for EachTender, fields left zero don't
+// restrict.
+type TenderFilter struct {
+	// Viewer keeps the tenders the viewer may see
+	Viewer *Viewer
+	Status models.TenderStatus
+	// CategoryIDs keeps the tenders linked to any of the categories
+	CategoryIDs []uuid.UUID
+	Creator     string
+	Page        Page
+}
+
+// Viewer is who a list of tenders is shown to. Everyone sees public
+// tenders; a user also sees the tenders of their organizations, the ones
+// for organizations when they have any and the ones they or their
+// organizations are invited to. UserID is uuid.Nil for anonymous viewers.
+type Viewer struct {
+	UserID          uuid.UUID
+	OrganizationIDs []uuid.UUID
+}
+
+// BidFilter selects bids of a tender, or of all tenders when TenderID is
+// uuid.Nil, ordered by creation. A bid is selected when it is by Author, of
+// one of OrganizationIDs or in one of Statuses, of those that are set.
+type BidFilter struct {
+	TenderID        uuid.UUID
+	Author          string
+	OrganizationIDs []uuid.UUID
+	Statuses        []models.BidStatus
+	Page            Page
+}
+
+// ReputationDelta is a change of the reputation counters caused by one
+// event.
+type ReputationDelta struct {
+	Submitted   int64
+	Approved    int64
+	Rejected    int64
+	RatingSum   int64
+	RatingCount int64
+}
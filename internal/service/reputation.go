@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+)
+
+// ratingDelta is the change of the rating counters when a feedback rating
+// goes from before to after, either of which may be unset.
+func ratingDelta(before *int, after *int) ReputationDelta {
+	var delta ReputationDelta
+	if before != nil {
+		delta.RatingSum -= int64(*before)
+		delta.RatingCount--
+	}
+	if after != nil {
+		delta.RatingSum += int64(*after)
+		delta.RatingCount++
+	}
+	return delta
+}
+
+// Reputation sums the counters of the organization up per service type and
+// per employee.
+func (s *Service) Reputation(ctx context.Context, userName string, orgID uuid.UUID) (Reputation, error) {
+	_, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return Reputation{}, err
+	}
+
+	organization, err := s.repo.GetOrganization(ctx, orgID)
+	if err != nil {
+		return Reputation{}, err
+	}
+
+	rows, err := s.repo.ReputationStats(ctx, organization.ID)
+	if err != nil {
+		return Reputation{}, err
+	}
+
+	res := Reputation{
+		OrganizationID: organization.ID,
+		Name:           organization.Name,
+		ServiceTypes:   make([]ServiceTypeReputation, 0),
+		Employees:      make([]EmployeeReputation, 0),
+	}
+
+	var total models.ReputationStat
+	employees := make(map[string]*EmployeeReputation)
+	employeeTotals := make(map[string]*models.ReputationStat)
+	var usernames []string
+
+	for _, el := range rows {
+		stats := ServiceTypeReputation{ServiceType: el.ServiceType, ReputationStats: reputationStats(el)}
+
+		if el.EmployeeUsername == "" {
+			res.ServiceTypes = append(res.ServiceTypes, stats)
+			addReputation(&total, el)
+			continue
+		}
+
+		employee, ok := employees[el.EmployeeUsername]
+		if !ok {
+			employee = &EmployeeReputation{Username: el.EmployeeUsername}
+			employees[el.EmployeeUsername] = employee
+			employeeTotals[el.EmployeeUsername] = &models.ReputationStat{}
+			usernames = append(usernames, el.EmployeeUsername)
+		}
+		employee.ServiceTypes = append(employee.ServiceTypes, stats)
+		addReputation(employeeTotals[el.EmployeeUsername], el)
+	}
+
+	res.ReputationStats = reputationStats(total)
+
+	sort.Strings(usernames)
+	for _, el := range usernames {
+		employee := employees[el]
+		employee.ReputationStats = reputationStats(*employeeTotals[el])
+		res.Employees = append(res.Employees, *employee)
+	}
+
+	return res, nil
+}
+
+func addReputation(total *models.ReputationStat, el models.ReputationStat) {
+	total.BidsSubmitted += el.BidsSubmitted
+	total.BidsApproved += el.BidsApproved
+	total.BidsRejected += el.BidsRejected
+	total.RatingSum += el.RatingSum
+	total.RatingCount += el.RatingCount
+}
+
+func reputationStats(el models.ReputationStat) ReputationStats {
+	stats := ReputationStats{
+		BidsSubmitted: el.BidsSubmitted,
+		BidsApproved:  el.BidsApproved,
+		BidsRejected:  el.BidsRejected,
+	}
+	if el.RatingCount > 0 {
+		rating := float64(el.RatingSum) / float64(el.RatingCount)
+		stats.AverageRating = &rating
+	}
+	if el.BidsSubmitted > 0 {
+		stats.WinRate = float64(el.BidsApproved) / float64(el.BidsSubmitted)
+	}
+	return stats
+}
@@ -0,0 +1,74 @@
+// Package service holds the use cases of the tender service. It owns the
+// rights checks, the status rules and the other invariants of tenders and
+// bids, and reaches the data only through a Repository, so the REST
+// handlers and the gRPC services just translate their requests to its
+// calls.
+package service
+
+import (
+	"math"
+	"tender_service/internal/config"
+	"tender_service/internal/lib/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo Repository
+	// notifier wakes the event subscriptions up, they only poll without it
+	notifier Notifier
+	admins   map[string]struct{}
+	// feedbackEditWindow is how long a feedback message stays editable
+	feedbackEditWindow time.Duration
+}
+
+func New(repo Repository, notifier Notifier, cfg *config.Config) *Service {
+	s := &Service{
+		repo:               repo,
+		notifier:           notifier,
+		admins:             make(map[string]struct{}, len(cfg.Admins)),
+		feedbackEditWindow: cfg.FeedbackEditWindow,
+	}
+	for _, el := range cfg.Admins {
+		s.admins[el] = struct{}{}
+	}
+	return s
+}
+
+// withRepo returns a copy of the service working through repo, which lets
+// the use cases run inside a transaction.
+func (s *Service) withRepo(repo Repository) *Service {
+	txs := *s
+	txs.repo = repo
+	return &txs
+}
+
+// Actor is the employee performing a use case and the organization they act
+// on behalf of, which is only needed from those responsible for several.
+type Actor struct {
+	Username       string
+	OrganizationID uuid.UUID
+}
+
+// Page is a window of a list.
+type Page struct {
+	Limit  uint
+	Offset uint
+}
+
+// unpaged is the page of the lookups that need the whole list, a zero
+// Limit selects nothing.
+var unpaged = Page{Limit: math.MaxInt32}
+
+// check validates the struct tags of a use case input, the failed fields
+// are ErrIncorrectValue.
+func check(input any) error {
+	return response.Validate(input)
+}
+
+// invalid reports an input breaking an invariant, it is ErrIncorrectValue
+// for the callers.
+func invalid(msg string) error {
+	return response.Invalid(msg)
+}
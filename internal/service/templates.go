@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewTemplate is a tender template saved for an organization the creator
+// may create tenders for.
+type NewTemplate struct {
+	Name            string                   `validate:"required,max=100"`
+	Description     string                   `validate:"required,max=500"`
+	ServiceType     models.TenderServiceType `validate:"required"`
+	OrganizationID  uuid.UUID                `validate:"required"`
+	CreatorUsername string                   `validate:"required"`
+	Categories      []uuid.UUID
+	// Visibility is public when unset
+	Visibility models.TenderVisibility
+	SealedBids bool
+}
+
+// TenderCopy is what a tender issued again from another tender or a
+// template differs in, a zero Name keeps the original one.
+type TenderCopy struct {
+	Name               string `validate:"max=100"`
+	SubmissionDeadline *time.Time
+}
+
+// SaveTemplate saves a template of the organization's tenders.
+func (s *Service) SaveTemplate(ctx context.Context, in NewTemplate) (Template, error) {
+	if in.Visibility == "" {
+		in.Visibility = models.PublicVisibility
+	}
+
+	if !models.ValidateTenderVisibility(in.Visibility) {
+		return Template{}, invalid("incorrect visibility")
+	}
+
+	if err := check(in); err != nil {
+		return Template{}, err
+	}
+
+	user, err := s.repo.GetUser(ctx, in.CreatorUsername)
+	if err != nil {
+		return Template{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, in.OrganizationID, policy.CreateTender)
+	if err != nil {
+		return Template{}, err
+	}
+
+	categoryIDs, err := s.tenderCategories(ctx, in.ServiceType, in.Categories)
+	if err != nil {
+		return Template{}, err
+	}
+
+	template := models.TenderTemplate{
+		OrganizationID: in.OrganizationID,
+		Name:           in.Name,
+		Description:    in.Description,
+		ServiceType:    in.ServiceType,
+		Criteria: models.TemplateCriteria{
+			Categories: categoryIDs[1:],
+			Visibility: in.Visibility,
+			SealedBids: in.SealedBids,
+		},
+		EmployeeUsername: user.Username,
+	}
+
+	err = s.repo.CreateTemplate(ctx, &template)
+	if err != nil {
+		return Template{}, err
+	}
+	return templateOf(&template), nil
+}
+
+// Templates returns the templates of the organization the user acts on
+// behalf of.
+func (s *Service) Templates(ctx context.Context, actor Actor) ([]Template, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := s.actingOrganization(ctx, user.ID, actor.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := s.repo.OrganizationTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Template, 0, len(templates))
+	for _, el := range templates {
+		res = append(res, templateOf(&el))
+	}
+	return res, nil
+}
+
+// DeleteTemplate deletes a template, the tenders issued from it are kept.
+func (s *Service) DeleteTemplate(ctx context.Context, actor Actor, templateID uuid.UUID) error {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return err
+	}
+
+	template, err := s.repo.GetTemplate(ctx, templateID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkPermission(ctx, user.ID, template.OrganizationID, policy.CreateTender)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteTemplate(ctx, template.ID)
+}
+
+// CloneTender creates a new tender of the same organization from a tender
+// with its categories and lots. The auction, bids and history are not
+// copied.
+func (s *Service) CloneTender(ctx context.Context, actor Actor, tenderID uuid.UUID, in TenderCopy) (Tender, error) {
+	if err := check(in); err != nil {
+		return Tender{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	extraIDs, err := s.repo.ExtraTenderCategories(ctx, tender.ID, string(tender.ServiceType))
+	if err != nil {
+		return Tender{}, err
+	}
+
+	lots, err := s.repo.TenderLots(ctx, tender.ID)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	return s.issueTender(ctx, NewTender{
+		Name:               copyName(tender.Name, in),
+		Description:        tender.Description,
+		ServiceType:        tender.ServiceType,
+		OrganizationID:     tender.OrganizationID,
+		CreatorUsername:    actor.Username,
+		Categories:         extraIDs,
+		Visibility:         tender.Visibility,
+		SealedBids:         tender.SealedBids,
+		SubmissionDeadline: in.SubmissionDeadline,
+	}, lots)
+}
+
+// TenderFromTemplate creates a new tender of the template's organization.
+func (s *Service) TenderFromTemplate(ctx context.Context, actor Actor, templateID uuid.UUID, in TenderCopy) (Tender, error) {
+	if err := check(in); err != nil {
+		return Tender{}, err
+	}
+
+	template, err := s.repo.GetTemplate(ctx, templateID)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	return s.issueTender(ctx, NewTender{
+		Name:               copyName(template.Name, in),
+		Description:        template.Description,
+		ServiceType:        template.ServiceType,
+		OrganizationID:     template.OrganizationID,
+		CreatorUsername:    actor.Username,
+		Categories:         template.Criteria.Categories,
+		Visibility:         template.Criteria.Visibility,
+		SealedBids:         template.Criteria.SealedBids,
+		SubmissionDeadline: in.SubmissionDeadline,
+	}, nil)
+}
+
+// issueTender creates the tender with the rights checks of CreateTender
+// together with open copies of lots.
+func (s *Service) issueTender(ctx context.Context, in NewTender, lots []models.TenderLot) (Tender, error) {
+	var res Tender
+	err := s.repo.WithinTransaction(ctx, func(repo Repository) error {
+		tender, err := s.withRepo(repo).CreateTender(ctx, in)
+		if err != nil {
+			return err
+		}
+
+		for _, el := range lots {
+			lot := models.TenderLot{
+				TenderID:    tender.ID,
+				Name:        el.Name,
+				Description: el.Description,
+				Quantity:    el.Quantity,
+				Winners:     el.Winners,
+				Status:      models.LotOpen,
+			}
+
+			err = repo.CreateTenderLot(ctx, &lot)
+			if err != nil {
+				return err
+			}
+		}
+
+		res = tender
+		return nil
+	})
+
+	if err != nil {
+		return Tender{}, err
+	}
+	return res, nil
+}
+
+func copyName(name string, in TenderCopy) string {
+	if in.Name != "" {
+		return in.Name
+	}
+	return name
+}
+
+func templateOf(el *models.TenderTemplate) Template {
+	return Template{
+		ID:             el.ID,
+		OrganizationID: el.OrganizationID,
+		Name:           el.Name,
+		Description:    el.Description,
+		ServiceType:    el.ServiceType,
+		Categories:     el.Criteria.Categories,
+		Visibility:     el.Criteria.Visibility,
+		SealedBids:     el.Criteria.SealedBids,
+		CreatedAt:      el.CreatedAt,
+	}
+}
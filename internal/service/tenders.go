@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/policy"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NewTender struct {
+	Name            string                   `validate:"required,max=100"`
+	Description     string                   `validate:"required,max=500"`
+	ServiceType     models.TenderServiceType `validate:"required"`
+	OrganizationID  uuid.UUID                `validate:"required"`
+	CreatorUsername string                   `validate:"required"`
+	// Categories are linked besides the category of the service type
+	Categories []uuid.UUID
+	// Visibility is public when unset
+	Visibility         models.TenderVisibility
+	SealedBids         bool
+	SubmissionDeadline *time.Time
+}
+
+// TenderPatch is a change of a tender, fields left zero are kept.
+type TenderPatch struct {
+	TenderID    uuid.UUID
+	Name        string `validate:"max=100"`
+	Description string `validate:"max=500"`
+	ServiceType models.TenderServiceType
+	Status      models.TenderStatus
+	// Categories replace the extra categories when not nil
+	Categories         []uuid.UUID
+	Visibility         models.TenderVisibility
+	SealedBids         *bool
+	SubmissionDeadline *time.Time
+}
+
+type TendersQuery struct {
+	// Username is empty for anonymous viewers
+	Username string
+	// ServiceTypes keep the tenders of the categories with these names and
+	// their subcategories
+	ServiceTypes []string
+	Page         Page
+}
+
+// CreateTender saves a new tender of an organization the creator may create
+// tenders for.
+func (s *Service) CreateTender(ctx context.Context, in NewTender) (Tender, error) {
+	if in.Visibility == "" {
+		in.Visibility = models.PublicVisibility
+	}
+
+	if err := checkTender(in.Visibility, in.SealedBids, in.SubmissionDeadline); err != nil {
+		return Tender{}, err
+	}
+
+	if err := check(in); err != nil {
+		return Tender{}, err
+	}
+
+	user, err := s.repo.GetUser(ctx, in.CreatorUsername)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, in.OrganizationID, policy.CreateTender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	categoryIDs, err := s.tenderCategories(ctx, in.ServiceType, in.Categories)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	tender := models.Tender{
+		Name:               in.Name,
+		Description:        in.Description,
+		ServiceType:        in.ServiceType,
+		Status:             models.TenderCreated,
+		Visibility:         in.Visibility,
+		SealedBids:         in.SealedBids,
+		SubmissionDeadline: in.SubmissionDeadline,
+		EmployeeUsername:   user.Username,
+		OrganizationID:     in.OrganizationID,
+	}
+
+	err = s.repo.CreateTender(ctx, &tender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.repo.SetTenderCategories(ctx, tender.ID, categoryIDs)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	return tenderOf(&tender, categoryIDs), nil
+}
+
+// TenderStatus is only told to the creator of the tender.
+func (s *Service) TenderStatus(ctx context.Context, actor Actor, tenderID uuid.UUID) (models.TenderStatus, error) {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return "", err
+	}
+
+	tender, err := s.visibleTender(ctx, tenderID, user)
+	if err != nil {
+		return "", err
+	}
+
+	if tender.EmployeeUsername != user.Username {
+		return "", response.ErrNoRights
+	}
+	return tender.Status, nil
+}
+
+func (s *Service) SetTenderStatus(ctx context.Context, actor Actor, tenderID uuid.UUID, status models.TenderStatus) (Tender, error) {
+	if !models.ValidateTenderStatus(status) {
+		return Tender{}, invalid("incorrect tender status")
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.TenderStatusPermission(status))
+	if err != nil {
+		return Tender{}, err
+	}
+
+	previous := tender.Status
+	tender.Status = status
+
+	err = s.repo.UpdateTender(ctx, tender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	if tender.Status != previous {
+		err = s.record(ctx, models.TenderStatusEvent, tender, nil, string(tender.Status))
+		if err != nil {
+			return Tender{}, err
+		}
+	}
+	return tenderOf(tender, nil), nil
+}
+
+// EditTender changes the tender as the next version, a status change also
+// needs the right to set that status.
+func (s *Service) EditTender(ctx context.Context, actor Actor, patch TenderPatch) (Tender, error) {
+	if patch.Status != "" && !models.ValidateTenderStatus(patch.Status) {
+		return Tender{}, invalid("incorrect tender status")
+	}
+
+	if patch.Visibility != "" && !models.ValidateTenderVisibility(patch.Visibility) {
+		return Tender{}, invalid("incorrect visibility")
+	}
+
+	if err := check(patch); err != nil {
+		return Tender{}, err
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, patch.TenderID)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.EditTender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	if patch.Status != "" && patch.Status != tender.Status {
+		err = s.checkPermission(ctx, user.ID, tender.OrganizationID, policy.TenderStatusPermission(patch.Status))
+		if err != nil {
+			return Tender{}, err
+		}
+	}
+
+	extraIDs := patch.Categories
+	if extraIDs == nil {
+		extraIDs, err = s.repo.ExtraTenderCategories(ctx, tender.ID, string(tender.ServiceType))
+		if err != nil {
+			return Tender{}, err
+		}
+	}
+
+	previous := tender.Status
+	patchTender(tender, patch)
+
+	if err := checkTender(tender.Visibility, tender.SealedBids, tender.SubmissionDeadline); err != nil {
+		return Tender{}, err
+	}
+
+	if tender.Auction && tender.ServiceType != models.Delivery {
+		return Tender{}, invalid("only delivery tenders can have an auction")
+	}
+
+	categoryIDs, err := s.tenderCategories(ctx, tender.ServiceType, extraIDs)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.repo.UpdateTender(ctx, tender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.repo.SetTenderCategories(ctx, tender.ID, categoryIDs)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	if tender.Status != previous {
+		err = s.record(ctx, models.TenderStatusEvent, tender, nil, string(tender.Status))
+		if err != nil {
+			return Tender{}, err
+		}
+	}
+
+	return tenderOf(tender, categoryIDs), nil
+}
+
+// Tenders passes the published tenders the viewer may see to fn.
+func (s *Service) Tenders(ctx context.Context, query TendersQuery, fn func(el Tender) error) error {
+	var user *models.Employee
+	if query.Username != "" {
+		usr, err := s.repo.GetUser(ctx, query.Username)
+		if err != nil {
+			return err
+		}
+		user = usr
+	}
+
+	viewer, err := s.viewer(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	filter := TenderFilter{Viewer: viewer, Status: models.TenderPublished, Page: query.Page}
+
+	if len(query.ServiceTypes) > 0 {
+		var rootIDs []uuid.UUID
+		for _, el := range query.ServiceTypes {
+			category, err := s.repo.GetCategoryByName(ctx, el)
+			if err != nil {
+				return response.Referenced(err)
+			}
+			rootIDs = append(rootIDs, category.ID)
+		}
+
+		filter.CategoryIDs, err = s.repo.CategoryDescendants(ctx, rootIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.repo.EachTender(ctx, filter, func(el models.Tender) error {
+		return fn(tenderOf(&el, nil))
+	})
+}
+
+// MyTenders passes the tenders created by the user to fn.
+func (s *Service) MyTenders(ctx context.Context, userName string, page Page, fn func(el Tender) error) error {
+	_, err := s.repo.GetUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	filter := TenderFilter{Creator: userName, Page: page}
+
+	return s.repo.EachTender(ctx, filter, func(el models.Tender) error {
+		return fn(tenderOf(&el, nil))
+	})
+}
+
+// RollbackTender saves the contents of an earlier version as the next one.
+func (s *Service) RollbackTender(ctx context.Context, actor Actor, tenderID uuid.UUID, version uint) (Tender, error) {
+	if version == 0 {
+		return Tender{}, invalid("version must be positive")
+	}
+
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	tenderVersion, err := s.repo.GetTenderVersion(ctx, tenderID, version)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.checkPermission(ctx, user.ID, tenderVersion.OrganizationID, policy.RollbackTender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderVersion.TenderID)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	extraIDs, err := s.repo.ExtraTenderCategories(ctx, tender.ID, string(tender.ServiceType))
+	if err != nil {
+		return Tender{}, err
+	}
+
+	tender.Name = tenderVersion.Name
+	tender.Description = tenderVersion.Description
+	tender.ServiceType = tenderVersion.ServiceType
+	previous := tender.Status
+	tender.Status = tenderVersion.Status
+
+	categoryIDs, err := s.tenderCategories(ctx, tender.ServiceType, extraIDs)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.repo.UpdateTender(ctx, tender)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	err = s.repo.SetTenderCategories(ctx, tender.ID, categoryIDs)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	if tender.Status != previous {
+		err = s.record(ctx, models.TenderStatusEvent, tender, nil, string(tender.Status))
+		if err != nil {
+			return Tender{}, err
+		}
+	}
+
+	return tenderOf(tender, categoryIDs), nil
+}
+
+// TenderVersions passes the tender's versions to fn, oldest first. The
+// history includes unpublished states, so only the members of the tender's
+// organization may read it.
+func (s *Service) TenderVersions(ctx context.Context, actor Actor, tenderID uuid.UUID, page Page, fn func(el TenderVersion) error) error {
+	user, err := s.repo.GetUser(ctx, actor.Username)
+	if err != nil {
+		return err
+	}
+
+	tender, err := s.repo.GetTender(ctx, tenderID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkResponsible(ctx, user.ID, tender.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.EachTenderVersion(ctx, tender.ID, page, func(el models.TenderVersion) error {
+		return fn(TenderVersion{
+			Version:     el.Version,
+			CreatedAt:   el.CreatedAt,
+			Name:        el.Name,
+			Description: el.Description,
+			ServiceType: el.ServiceType,
+			Status:      el.Status,
+		})
+	})
+}
+
+// tenderCategories resolves the service type and the extra categories of a
+// tender, the service type category goes first.
+func (s *Service) tenderCategories(ctx context.Context, serviceType models.TenderServiceType, categoryIDs []uuid.UUID) ([]uuid.UUID, error) {
+	primary, err := s.repo.GetCategoryByName(ctx, string(serviceType))
+	if err != nil {
+		return nil, response.Referenced(err)
+	}
+
+	ids := []uuid.UUID{primary.ID}
+	seen := map[uuid.UUID]struct{}{primary.ID: {}}
+
+	for _, el := range categoryIDs {
+		if _, ok := seen[el]; ok {
+			continue
+		}
+		if _, err := s.repo.GetCategory(ctx, el); err != nil {
+			return nil, response.Referenced(err)
+		}
+		seen[el] = struct{}{}
+		ids = append(ids, el)
+	}
+	return ids, nil
+}
+
+// checkTender checks the invariants between the visibility and bidding
+// settings of a tender.
+func checkTender(visibility models.TenderVisibility, sealedBids bool, deadline *time.Time) error {
+	if !models.ValidateTenderVisibility(visibility) {
+		return invalid("incorrect visibility")
+	}
+
+	if sealedBids && deadline == nil {
+		return invalid("sealed bids require submission deadline")
+	}
+	return nil
+}
+
+func patchTender(tender *models.Tender, patch TenderPatch) {
+	if patch.Description != "" {
+		tender.Description = patch.Description
+	}
+
+	if patch.Name != "" {
+		tender.Name = patch.Name
+	}
+
+	if patch.ServiceType != "" {
+		tender.ServiceType = patch.ServiceType
+	}
+
+	if patch.Status != "" {
+		tender.Status = patch.Status
+	}
+
+	if patch.Visibility != "" {
+		tender.Visibility = patch.Visibility
+	}
+
+	if patch.SealedBids != nil {
+		tender.SealedBids = *patch.SealedBids
+	}
+
+	if patch.SubmissionDeadline != nil {
+		tender.SubmissionDeadline = patch.SubmissionDeadline
+	}
+}
@@ -0,0 +1,396 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The methods in this file serve the operators' tenderctl and skip the
+// rights checks the API does.
+
+// SeedEmployee creates the employee or, when the username is taken, updates
+// their name. employee.ID is set to the stored one.
+func (s *Storage) SeedEmployee(ctx context.Context, employee *models.Employee) error {
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}},
+		DoUpdates: clause.AssignmentColumns([]string{"first_name", "last_name", "updated_at"}),
+	}).Create(employee)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+
+	stored, err := s.GetUser(ctx, employee.Username)
+	if err != nil {
+		return err
+	}
+	employee.ID = stored.ID
+	return nil
+}
+
+// SeedOrganization creates the organization or, when one with the same
+// name exists, updates its description and type. organization.ID is set to
+// the stored one.
+func (s *Storage) SeedOrganization(ctx context.Context, organization *models.Organization) error {
+	if !models.ValidateOrganizationType(organization.Type) {
+		return fmt.Errorf("unknown organization type %q", organization.Type)
+	}
+
+	var stored models.Organization
+	result := s.db.WithContext(ctx).Where("name = ?", organization.Name).First(&stored)
+	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+		return dbError(result.Error)
+	}
+
+	if result.Error == gorm.ErrRecordNotFound {
+		result = s.db.WithContext(ctx).Create(organization)
+	} else {
+		organization.ID = stored.ID
+		result = s.db.WithContext(ctx).Model(&stored).Updates(map[string]any{
+			"description": organization.Description,
+			"type":        organization.Type,
+		})
+	}
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// AssignResponsible makes the employee a responsible of the organization
+// with the role, or changes the role of an existing one.
+func (s *Storage) AssignResponsible(ctx context.Context, orgID uuid.UUID, username string, role models.OrganizationRole) error {
+	if !models.ValidateOrganizationRole(role) {
+		return fmt.Errorf("unknown role %q", role)
+	}
+
+	user, err := s.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	result := s.db.WithContext(ctx).Model(&models.Organization{}).Where("id = ?", orgID).Count(&count)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	if count == 0 {
+		return response.ErrOrganizationNotExists
+	}
+
+	var member models.OrganizationResponsible
+	result = s.db.WithContext(ctx).Where("organization_id = ? AND user_id = ?", orgID, user.ID).First(&member)
+	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+		return dbError(result.Error)
+	}
+
+	if result.Error == gorm.ErrRecordNotFound {
+		result = s.db.WithContext(ctx).Create(&models.OrganizationResponsible{OrganizationID: orgID, EmployeeID: user.ID, Role: role})
+	} else {
+		result = s.db.WithContext(ctx).Model(&member).Update("role", role)
+	}
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// ListFilter narrows ListTenders and ListBids. Zero fields don't filter.
+type ListFilter struct {
+	TenderID       uuid.UUID
+	OrganizationID uuid.UUID
+	Username       string
+	Status         string
+	// Deleted includes the soft-deleted rows
+	Deleted bool
+	Limit   int
+}
+
+func listQuery(query *gorm.DB, filter ListFilter) *gorm.DB {
+	if filter.Deleted {
+		query = query.Unscoped()
+	}
+	if filter.OrganizationID != uuid.Nil {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if filter.Username != "" {
+		query = query.Where("employee_username = ?", filter.Username)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	return query.Order("created_at DESC")
+}
+
+func (s *Storage) ListTenders(ctx context.Context, filter ListFilter) ([]models.Tender, error) {
+	var tenders []models.Tender
+	result := listQuery(s.db.WithContext(ctx).Model(&models.Tender{}), filter).Find(&tenders)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return tenders, nil
+}
+
+func (s *Storage) ListBids(ctx context.Context, filter ListFilter) ([]models.Bid, error) {
+	query := listQuery(s.db.WithContext(ctx).Model(&models.Bid{}), filter)
+	if filter.TenderID != uuid.Nil {
+		query = query.Where("tender_id = ?", filter.TenderID)
+	}
+
+	var bids []models.Bid
+	result := query.Find(&bids)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return bids, nil
+}
+
+// TenderDetails is a tender with its history.
+type TenderDetails struct {
+	Tender   models.Tender
+	Versions []models.TenderVersion
+	Audit    []models.StatusAudit
+}
+
+// BidDetails is a bid with its history.
+type BidDetails struct {
+	Bid      models.Bid
+	Versions []models.BidVersion
+	Audit    []models.StatusAudit
+}
+
+// InspectTender returns the tender, deleted or not, with all its versions
+// and forced status changes.
+func (s *Storage) InspectTender(ctx context.Context, tenderID uuid.UUID) (TenderDetails, error) {
+	var details TenderDetails
+	result := s.db.WithContext(ctx).Unscoped().Where("id = ?", tenderID).First(&details.Tender)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return TenderDetails{}, response.ErrTenderNotExists
+		}
+		return TenderDetails{}, dbError(result.Error)
+	}
+
+	result = s.db.WithContext(ctx).Unscoped().Where("tender_id = ?", tenderID).Order("version").Find(&details.Versions)
+	if result.Error != nil {
+		return TenderDetails{}, dbError(result.Error)
+	}
+
+	result = s.db.WithContext(ctx).Where("entity_id = ?", tenderID).Order("created_at").Find(&details.Audit)
+	if result.Error != nil {
+		return TenderDetails{}, dbError(result.Error)
+	}
+	return details, nil
+}
+
+// InspectBid returns the bid, deleted or not, with all its versions and
+// forced status changes.
+func (s *Storage) InspectBid(ctx context.Context, bidID uuid.UUID) (BidDetails, error) {
+	var details BidDetails
+	result := s.db.WithContext(ctx).Unscoped().Where("id = ?", bidID).First(&details.Bid)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return BidDetails{}, response.ErrBidNotExists
+		}
+		return BidDetails{}, dbError(result.Error)
+	}
+
+	result = s.db.WithContext(ctx).Unscoped().Where("bid_id = ?", bidID).Order("version").Find(&details.Versions)
+	if result.Error != nil {
+		return BidDetails{}, dbError(result.Error)
+	}
+
+	result = s.db.WithContext(ctx).Where("entity_id = ?", bidID).Order("created_at").Find(&details.Audit)
+	if result.Error != nil {
+		return BidDetails{}, dbError(result.Error)
+	}
+	return details, nil
+}
+
+// ForceTenderStatus sets the tender's status regardless of rights and the
+// usual transitions, as a new version, and records who did it and why.
+func (s *Storage) ForceTenderStatus(ctx context.Context, tenderID uuid.UUID, status models.TenderStatus, actor string, reason string) (*models.Tender, error) {
+	if !models.ValidateTenderStatus(status) {
+		return nil, fmt.Errorf("unknown tender status %q", status)
+	}
+	if reason == "" || actor == "" {
+		return nil, errors.New("reason and actor must be not empty")
+	}
+
+	var tender *models.Tender
+	err := s.Transaction(ctx, func(tx *Storage) error {
+		var err error
+		tender, err = tx.GetTender(ctx, tenderID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.audit(ctx, "tender", tender.ID, string(tender.Status), string(status), actor, reason)
+		if err != nil {
+			return err
+		}
+
+		tender.Status = status
+		return tx.UpdateTender(ctx, tender)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+// ForceBidStatus sets the bid's status, decisions included, regardless of
+// rights and the usual transitions, as a new version, and records who did
+// it and why. Decisions forced this way don't count towards reputation.
+func (s *Storage) ForceBidStatus(ctx context.Context, bidID uuid.UUID, status models.BidStatus, actor string, reason string) (*models.Bid, error) {
+	if !models.ValidateBidStatus(status) && status != models.BidApproved && status != models.BidRejected {
+		return nil, fmt.Errorf("unknown bid status %q", status)
+	}
+	if reason == "" || actor == "" {
+		return nil, errors.New("reason and actor must be not empty")
+	}
+
+	var bid *models.Bid
+	err := s.Transaction(ctx, func(tx *Storage) error {
+		var err error
+		bid, err = tx.GetBid(ctx, bidID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.audit(ctx, "bid", bid.ID, string(bid.Status), string(status), actor, reason)
+		if err != nil {
+			return err
+		}
+
+		bid.Status = status
+		return tx.UpdateBid(ctx, bid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+func (s *Storage) audit(ctx context.Context, entityType string, entityID uuid.UUID, from string, to string, actor string, reason string) error {
+	result := s.db.WithContext(ctx).Create(&models.StatusAudit{
+		EntityType: entityType,
+		EntityID:   entityID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		Actor:      actor,
+	})
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// PurgeTestData removes for good the organizations whose names and the
+// employees whose usernames start with prefix, together with everything
+// they created: tenders, bids, versions, feedback and memberships. It
+// returns the number of removed rows per table; with dryRun nothing is
+// removed.
+func (s *Storage) PurgeTestData(ctx context.Context, prefix string, dryRun bool) (map[string]int64, error) {
+	if prefix == "" {
+		return nil, errors.New("prefix must be not empty")
+	}
+	pattern := likePrefix(prefix)
+	purged := make(map[string]int64)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var orgIDs []uuid.UUID
+		result := tx.Model(&models.Organization{}).Where("name LIKE ?", pattern).Pluck("id", &orgIDs)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+
+		var usernames []string
+		result = tx.Model(&models.Employee{}).Where("username LIKE ?", pattern).Pluck("username", &usernames)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+
+		orgIDs = append(orgIDs, uuid.Nil)
+		usernames = append(usernames, "")
+
+		var tenderIDs []uuid.UUID
+		result = tx.Unscoped().Model(&models.Tender{}).
+			Where("organization_id IN ? OR employee_username IN ?", orgIDs, usernames).
+			Pluck("id", &tenderIDs)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+		tenderIDs = append(tenderIDs, uuid.Nil)
+
+		var bidIDs []uuid.UUID
+		result = tx.Unscoped().Model(&models.Bid{}).
+			Where("tender_id IN ? OR organization_id IN ? OR employee_username IN ?", tenderIDs, orgIDs, usernames).
+			Pluck("id", &bidIDs)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+		bidIDs = append(bidIDs, uuid.Nil)
+
+		// children go first as not all foreign keys cascade
+		steps := []struct {
+			table string
+			model any
+			where string
+			args  []any
+		}{
+			{"bid_feedbacks", &models.BidFeedback{}, "bid_id IN ? OR organization_id IN ? OR employee_username IN ?", []any{bidIDs, orgIDs, usernames}},
+			{"bid_versions", &models.BidVersion{}, "bid_id IN ? OR tender_id IN ? OR organization_id IN ?", []any{bidIDs, tenderIDs, orgIDs}},
+			{"bids", &models.Bid{}, "id IN ?", []any{bidIDs}},
+			{"tender_versions", &models.TenderVersion{}, "tender_id IN ? OR organization_id IN ?", []any{tenderIDs, orgIDs}},
+			{"tenders", &models.Tender{}, "id IN ?", []any{tenderIDs}},
+			{"status_audit", &models.StatusAudit{}, "entity_id IN ? OR entity_id IN ?", []any{tenderIDs, bidIDs}},
+			{"reputation_stats", &models.ReputationStat{}, "organization_id IN ? OR employee_username IN ?", []any{orgIDs, usernames}},
+			{"organization_responsible", &models.OrganizationResponsible{}, "organization_id IN ? OR user_id IN (?)", []any{orgIDs,
+				tx.Model(&models.Employee{}).Select("id").Where("username IN ?", usernames)}},
+			{"organization", &models.Organization{}, "id IN ?", []any{orgIDs}},
+			{"employee", &models.Employee{}, "username IN ?", []any{usernames}},
+		}
+
+		for _, el := range steps {
+			result = tx.Unscoped().Where(el.where, el.args...).Delete(el.model)
+			if result.Error != nil {
+				return dbError(result.Error)
+			}
+			purged[el.table] = result.RowsAffected
+		}
+
+		if dryRun {
+			return errRolledBack
+		}
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errRolledBack) {
+		return nil, err
+	}
+	return purged, nil
+}
+
+// likePrefix returns a LIKE pattern matching the strings starting with
+// prefix.
+func likePrefix(prefix string) string {
+	var pattern []rune
+	for _, r := range prefix {
+		if r == '%' || r == '_' || r == '\\' {
+			pattern = append(pattern, '\\')
+		}
+		pattern = append(pattern, r)
+	}
+	return string(pattern) + "%"
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) GetAuction(ctx context.Context, tenderID uuid.UUID) (*models.TenderAuction, error) {
+	return s.auction(s.db.WithContext(ctx), tenderID)
+}
+
+// LockAuction returns the auction locked until the end of the transaction.
+func (s *Storage) LockAuction(ctx context.Context, tenderID uuid.UUID) (*models.TenderAuction, error) {
+	return s.auction(s.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), tenderID)
+}
+
+func (s *Storage) auction(query *gorm.DB, tenderID uuid.UUID) (*models.TenderAuction, error) {
+	var auction models.TenderAuction
+	result := query.Where("tender_id = ?", tenderID).First(&auction)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrAuctionNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &auction, nil
+}
+
+// SaveAuction creates the auction of the tender or replaces its settings.
+func (s *Storage) SaveAuction(ctx context.Context, auction *models.TenderAuction) error {
+	result := s.db.WithContext(ctx).Save(auction)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) CreateOffer(ctx context.Context, offer *models.AuctionOffer) error {
+	result := s.db.WithContext(ctx).Create(offer)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) AuctionOffers(ctx context.Context, tenderID uuid.UUID) ([]models.AuctionOffer, error) {
+	var offers []models.AuctionOffer
+	result := s.db.WithContext(ctx).Where("tender_id = ?", tenderID).Order("created_at, id").Find(&offers)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return offers, nil
+}
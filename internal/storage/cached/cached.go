@@ -0,0 +1,278 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"tender_service/internal/lib/cache"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxListLimit is the largest page of public tenders kept in the cache,
+// bigger ones always go to the database.
+const maxListLimit = 100
+
+// generationKey holds the token the keys of the cached tender lists are
+// made of. Replacing it drops all of them at once.
+const generationKey = "tenders:generation"
+
+// Repository serves the user, organization and membership lookups and the
+// lists of public tenders from the cache, and drops them from it when they
+// change. Inside a transaction it reads through to the database and drops
+// what changed once the transaction is committed. A failing cache is
+// logged and bypassed.
+//
+// tenderctl seeds, assigns and purges users, organizations and members
+// straight in the database, out of reach of the invalidation, so their
+// lookups are kept only for the short lookupTTL.
+type Repository struct {
+	service.Repository
+	cache     cache.Cache
+	ttl       time.Duration
+	lookupTTL time.Duration
+
+	// pending collects the keys changed inside a transaction
+	pending *[]string
+}
+
+var _ service.Repository = (*Repository)(nil)
+
+func New(repo service.Repository, c cache.Cache, ttl time.Duration, lookupTTL time.Duration) *Repository {
+	return &Repository{Repository: repo, cache: c, ttl: ttl, lookupTTL: lookupTTL}
+}
+
+func (r *Repository) WithinTransaction(ctx context.Context, fn func(repo service.Repository) error) error {
+	if r.pending != nil {
+		return r.Repository.WithinTransaction(ctx, func(repo service.Repository) error {
+			return fn(&Repository{Repository: repo, cache: r.cache, ttl: r.ttl, lookupTTL: r.lookupTTL, pending: r.pending})
+		})
+	}
+
+	var pending []string
+	err := r.Repository.WithinTransaction(ctx, func(repo service.Repository) error {
+		return fn(&Repository{Repository: repo, cache: r.cache, ttl: r.ttl, lookupTTL: r.lookupTTL, pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, pending...)
+	return nil
+}
+
+func (r *Repository) GetUser(ctx context.Context, userName string) (*models.Employee, error) {
+	return lookup(ctx, r, "user:name:"+userName, r.lookupTTL, func() (*models.Employee, error) {
+		return r.Repository.GetUser(ctx, userName)
+	})
+}
+
+func (r *Repository) GetUserById(ctx context.Context, userID uuid.UUID) (*models.Employee, error) {
+	return lookup(ctx, r, "user:id:"+userID.String(), r.lookupTTL, func() (*models.Employee, error) {
+		return r.Repository.GetUserById(ctx, userID)
+	})
+}
+
+func (r *Repository) GetOrganization(ctx context.Context, orgID uuid.UUID) (*models.Organization, error) {
+	return lookup(ctx, r, "org:"+orgID.String(), r.lookupTTL, func() (*models.Organization, error) {
+		return r.Repository.GetOrganization(ctx, orgID)
+	})
+}
+
+func (r *Repository) UserOrganizations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return lookup(ctx, r, userOrganizationsKey(userID), r.lookupTTL, func() ([]uuid.UUID, error) {
+		return r.Repository.UserOrganizations(ctx, userID)
+	})
+}
+
+func (r *Repository) MemberRole(ctx context.Context, userID uuid.UUID, orgID uuid.UUID) (models.OrganizationRole, error) {
+	return lookup(ctx, r, memberKey(orgID, userID), r.lookupTTL, func() (models.OrganizationRole, error) {
+		return r.Repository.MemberRole(ctx, userID, orgID)
+	})
+}
+
+func (r *Repository) UpdateMemberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role models.OrganizationRole) error {
+	if err := r.Repository.UpdateMemberRole(ctx, orgID, userID, role); err != nil {
+		return err
+	}
+	r.invalidate(ctx, memberKey(orgID, userID), userOrganizationsKey(userID))
+	return nil
+}
+
+// EachTender serves the first pages of public tenders from the cache, the
+// lists of the signed in users differ too much to be worth keeping.
+func (r *Repository) EachTender(ctx context.Context, filter service.TenderFilter, fn func(el models.Tender) error) error {
+	if r.pending != nil || filter.Viewer == nil || filter.Viewer.UserID != uuid.Nil || filter.Page.Limit > maxListLimit {
+		return r.Repository.EachTender(ctx, filter, fn)
+	}
+
+	generation, ok := r.generation(ctx)
+	if !ok {
+		return r.Repository.EachTender(ctx, filter, fn)
+	}
+
+	tenders, err := lookup(ctx, r, listKey(generation, filter), r.ttl, func() ([]models.Tender, error) {
+		var tenders []models.Tender
+		err := r.Repository.EachTender(ctx, filter, func(el models.Tender) error {
+			tenders = append(tenders, el)
+			return nil
+		})
+		return tenders, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, el := range tenders {
+		if err := fn(el); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) CreateTender(ctx context.Context, tender *models.Tender) error {
+	if err := r.Repository.CreateTender(ctx, tender); err != nil {
+		return err
+	}
+	r.invalidate(ctx, generationKey)
+	return nil
+}
+
+func (r *Repository) UpdateTender(ctx context.Context, tender *models.Tender) error {
+	if err := r.Repository.UpdateTender(ctx, tender); err != nil {
+		return err
+	}
+	r.invalidate(ctx, generationKey)
+	return nil
+}
+
+func (r *Repository) DeleteTender(ctx context.Context, tenderID uuid.UUID, at time.Time) (int64, error) {
+	n, err := r.Repository.DeleteTender(ctx, tenderID, at)
+	if err != nil {
+		return n, err
+	}
+	r.invalidate(ctx, generationKey)
+	return n, nil
+}
+
+func (r *Repository) RestoreTender(ctx context.Context, tender *models.Tender) error {
+	if err := r.Repository.RestoreTender(ctx, tender); err != nil {
+		return err
+	}
+	r.invalidate(ctx, generationKey)
+	return nil
+}
+
+func (r *Repository) SetTenderCategories(ctx context.Context, tenderID uuid.UUID, categoryIDs []uuid.UUID) error {
+	if err := r.Repository.SetTenderCategories(ctx, tenderID, categoryIDs); err != nil {
+		return err
+	}
+	r.invalidate(ctx, generationKey)
+	return nil
+}
+
+// generation returns the token of the current tender lists, starting a new
+// one when there is none.
+func (r *Repository) generation(ctx context.Context) (string, bool) {
+	value, found, err := r.cache.Get(ctx, generationKey)
+	if err != nil {
+		logError("failed to read cache", generationKey, err)
+		return "", false
+	}
+	if found {
+		return string(value), true
+	}
+
+	generation := uuid.NewString()
+	if err := r.cache.Set(ctx, generationKey, []byte(generation), 0); err != nil {
+		logError("failed to write cache", generationKey, err)
+		return "", false
+	}
+	return generation, true
+}
+
+// invalidate drops the keys from the cache, or queues them until the
+// commit inside a transaction.
+func (r *Repository) invalidate(ctx context.Context, keys ...string) {
+	if r.pending != nil {
+		*r.pending = append(*r.pending, keys...)
+		return
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	// the change is already made, dropping the stale values must not
+	// depend on the request still running
+	if err := r.cache.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+		logError("failed to invalidate cache", strings.Join(keys, ","), err)
+	}
+}
+
+// lookup returns the value kept under key, loading and keeping it for ttl
+// on a miss. Errors are not kept, and inside a transaction the cache is not used
+// at all.
+func lookup[T any](ctx context.Context, r *Repository, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if r.pending != nil {
+		return load()
+	}
+
+	value, found, err := r.cache.Get(ctx, key)
+	if err != nil {
+		logError("failed to read cache", key, err)
+	} else if found {
+		var cached T
+		if err := json.Unmarshal(value, &cached); err == nil {
+			return cached, nil
+		}
+		logError("failed to decode cached value", key, err)
+	}
+
+	loaded, err := load()
+	if err != nil {
+		return loaded, err
+	}
+
+	value, err = json.Marshal(loaded)
+	if err != nil {
+		logError("failed to encode cached value", key, err)
+		return loaded, nil
+	}
+	if err := r.cache.Set(ctx, key, value, ttl); err != nil {
+		logError("failed to write cache", key, err)
+	}
+	return loaded, nil
+}
+
+func logError(msg string, key string, err error) {
+	slog.Error(msg, slog.String("key", key), slog.String("error", err.Error()))
+}
+
+func userOrganizationsKey(userID uuid.UUID) string {
+	return "user:orgs:" + userID.String()
+}
+
+func memberKey(orgID uuid.UUID, userID uuid.UUID) string {
+	return "member:" + orgID.String() + ":" + userID.String()
+}
+
+// listKey tells the lists of public tenders apart, a nil CategoryIDs
+// selects any category while an empty one selects none.
+func listKey(generation string, filter service.TenderFilter) string {
+	categories := "*"
+	if filter.CategoryIDs != nil {
+		ids := make([]string, len(filter.CategoryIDs))
+		for i, el := range filter.CategoryIDs {
+			ids[i] = el.String()
+		}
+		categories = strings.Join(ids, ",")
+	}
+	return fmt.Sprintf("tenders:%s:%s:%s:%s:%d:%d", generation, filter.Status, filter.Creator,
+		categories, filter.Page.Limit, filter.Page.Offset)
+}
@@ -0,0 +1,201 @@
+package cached
+
+import (
+	"context"
+	"tender_service/internal/lib/cache"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeRepository counts the lookups reaching the database, the methods the
+// tests don't use are left to the nil embedded interface.
+type fakeRepository struct {
+	service.Repository
+	tenders []models.Tender
+	role    models.OrganizationRole
+	calls   map[string]int
+}
+
+func (r *fakeRepository) WithinTransaction(ctx context.Context, fn func(repo service.Repository) error) error {
+	return fn(r)
+}
+
+func (r *fakeRepository) MemberRole(context.Context, uuid.UUID, uuid.UUID) (models.OrganizationRole, error) {
+	r.calls["MemberRole"]++
+	return r.role, nil
+}
+
+func (r *fakeRepository) UpdateMemberRole(_ context.Context, _ uuid.UUID, _ uuid.UUID, role models.OrganizationRole) error {
+	r.role = role
+	return nil
+}
+
+func (r *fakeRepository) EachTender(_ context.Context, _ service.TenderFilter, fn func(el models.Tender) error) error {
+	r.calls["EachTender"]++
+	for _, el := range r.tenders {
+		if err := fn(el); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) CreateTender(_ context.Context, tender *models.Tender) error {
+	r.tenders = append(r.tenders, *tender)
+	return nil
+}
+
+func names(t *testing.T, repo service.Repository, filter service.TenderFilter) []string {
+	t.Helper()
+	var names []string
+	err := repo.EachTender(context.Background(), filter, func(el models.Tender) error {
+		names = append(names, el.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return names
+}
+
+func TestEachTender(t *testing.T) {
+	anonymous := service.TenderFilter{Viewer: &service.Viewer{}, Page: service.Page{Limit: 5}}
+
+	tests := []struct {
+		name   string
+		filter service.TenderFilter
+		// loads is how many of two lists reach the database
+		loads int
+	}{
+		{name: "caches anonymous lists", filter: anonymous, loads: 1},
+		{name: "reads lists of users through", filter: service.TenderFilter{Viewer: &service.Viewer{UserID: uuid.New()}, Page: anonymous.Page}, loads: 2},
+		{name: "reads unfiltered lists through", filter: service.TenderFilter{Page: anonymous.Page}, loads: 2},
+		{name: "reads large pages through", filter: service.TenderFilter{Viewer: &service.Viewer{}, Page: service.Page{Limit: maxListLimit + 1}}, loads: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeRepository{tenders: []models.Tender{{Name: "a"}}, calls: map[string]int{}}
+			repo := New(db, cache.NewLRU(100), time.Minute, time.Minute)
+
+			names(t, repo, tt.filter)
+			if got := names(t, repo, tt.filter); len(got) != 1 || got[0] != "a" {
+				t.Errorf("tenders %v, want [a]", got)
+			}
+			if db.calls["EachTender"] != tt.loads {
+				t.Errorf("%d loads, want %d", db.calls["EachTender"], tt.loads)
+			}
+		})
+	}
+}
+
+func TestEachTenderInvalidation(t *testing.T) {
+	anonymous := service.TenderFilter{Viewer: &service.Viewer{}, Page: service.Page{Limit: 5}}
+
+	for _, inTransaction := range []bool{false, true} {
+		db := &fakeRepository{tenders: []models.Tender{{Name: "a"}}, calls: map[string]int{}}
+		repo := New(db, cache.NewLRU(100), time.Minute, time.Minute)
+		names(t, repo, anonymous)
+
+		create := func(repo service.Repository) error {
+			return repo.CreateTender(context.Background(), &models.Tender{Name: "b"})
+		}
+		var err error
+		if inTransaction {
+			err = repo.WithinTransaction(context.Background(), create)
+		} else {
+			err = create(repo)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := names(t, repo, anonymous); len(got) != 2 {
+			t.Errorf("in transaction %v: tenders %v after create, want [a b]", inTransaction, got)
+		}
+	}
+}
+
+func TestMemberRole(t *testing.T) {
+	userID, orgID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name string
+		// change changes the role to viewer between the two lookups
+		change func(t *testing.T, db *fakeRepository, repo *Repository)
+		ttl    time.Duration
+		want   models.OrganizationRole
+		loads  int
+	}{
+		{
+			name: "keeps the role",
+			change: func(t *testing.T, db *fakeRepository, repo *Repository) {
+				// tenderctl changes the role straight in the database
+				db.role = models.ViewerRole
+			},
+			want:  models.AdminRole,
+			loads: 1,
+		},
+		{
+			name: "drops the role when it is updated",
+			change: func(t *testing.T, db *fakeRepository, repo *Repository) {
+				if err := repo.UpdateMemberRole(context.Background(), orgID, userID, models.ViewerRole); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:  models.ViewerRole,
+			loads: 2,
+		},
+		{
+			name: "drops the role updated in a transaction",
+			change: func(t *testing.T, db *fakeRepository, repo *Repository) {
+				err := repo.WithinTransaction(context.Background(), func(repo service.Repository) error {
+					return repo.UpdateMemberRole(context.Background(), orgID, userID, models.ViewerRole)
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:  models.ViewerRole,
+			loads: 2,
+		},
+		{
+			name: "expires the role after the lookup ttl",
+			change: func(t *testing.T, db *fakeRepository, repo *Repository) {
+				db.role = models.ViewerRole
+				time.Sleep(20 * time.Millisecond)
+			},
+			ttl:   10 * time.Millisecond,
+			want:  models.ViewerRole,
+			loads: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeRepository{role: models.AdminRole, calls: map[string]int{}}
+			ttl := tt.ttl
+			if ttl == 0 {
+				ttl = time.Minute
+			}
+			repo := New(db, cache.NewLRU(100), time.Minute, ttl)
+			ctx := context.Background()
+
+			if role, _ := repo.MemberRole(ctx, userID, orgID); role != models.AdminRole {
+				t.Fatalf("role %q, want %q", role, models.AdminRole)
+			}
+			tt.change(t, db, repo)
+
+			if role, _ := repo.MemberRole(ctx, userID, orgID); role != tt.want {
+				t.Errorf("role %q, want %q", role, tt.want)
+			}
+			if db.calls["MemberRole"] != tt.loads {
+				t.Errorf("%d loads, want %d", db.calls["MemberRole"], tt.loads)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) GetCategory(ctx context.Context, categoryID uuid.UUID) (*models.TenderCategory, error) {
+	if categoryID == uuid.Nil {
+		return &models.TenderCategory{}, response.ErrCategoryNotExists
+	}
+	var category models.TenderCategory
+	query := s.db.WithContext(ctx).Model(&models.TenderCategory{})
+	result := query.Where("id = ?", categoryID).First(&category)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return &models.TenderCategory{}, response.ErrCategoryNotExists
+		}
+		return &models.TenderCategory{}, dbError(result.Error)
+	}
+	return &category, nil
+}
+
+func (s *Storage) GetCategoryByName(ctx context.Context, name string) (*models.TenderCategory, error) {
+	if name == "" {
+		return &models.TenderCategory{}, response.ErrCategoryNotExists
+	}
+	var category models.TenderCategory
+	query := s.db.WithContext(ctx).Model(&models.TenderCategory{})
+	result := query.Where("name = ?", name).First(&category)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return &models.TenderCategory{}, response.ErrCategoryNotExists
+		}
+		return &models.TenderCategory{}, dbError(result.Error)
+	}
+	return &category, nil
+}
+
+func (s *Storage) Categories(ctx context.Context, parentID uuid.UUID) ([]models.TenderCategory, error) {
+	var categories []models.TenderCategory
+	query := s.db.WithContext(ctx).Model(&models.TenderCategory{})
+
+	if parentID != uuid.Nil {
+		query = query.Where("parent_id = ?", parentID)
+	}
+
+	result := query.Order("name").Find(&categories)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return categories, nil
+}
+
+// CategoryDescendants returns the given categories together with all of
+// their subcategories.
+func (s *Storage) CategoryDescendants(ctx context.Context, categoryIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	result := s.db.WithContext(ctx).Raw(`
+			WITH RECURSIVE tree AS (
+				SELECT id FROM tender_categories WHERE id IN ?
+				UNION
+				SELECT c.id FROM tender_categories c JOIN tree ON c.parent_id = tree.id
+			)
+			SELECT id FROM tree
+	`, categoryIDs).Scan(&ids)
+
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return ids, nil
+}
+
+func (s *Storage) CreateCategory(ctx context.Context, category *models.TenderCategory) error {
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(category)
+
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return response.ErrIncorrectValue
+	}
+	return nil
+}
+
+func (s *Storage) SaveCategory(ctx context.Context, category *models.TenderCategory) error {
+	result := s.db.WithContext(ctx).Save(category)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) DeleteCategory(ctx context.Context, category *models.TenderCategory) error {
+	result := s.db.WithContext(ctx).Delete(category)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) CategoryUsage(ctx context.Context, categoryID uuid.UUID) (int64, error) {
+	var children, links int64
+	result := s.db.WithContext(ctx).Model(&models.TenderCategory{}).Where("parent_id = ?", categoryID).Count(&children)
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+
+	result = s.db.WithContext(ctx).Model(&models.TenderCategoryLink{}).Where("category_id = ?", categoryID).Count(&links)
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+	return children + links, nil
+}
+
+func (s *Storage) SetTenderCategories(ctx context.Context, tenderID uuid.UUID, categoryIDs []uuid.UUID) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tender_id = ?", tenderID).Delete(&models.TenderCategoryLink{}).Error; err != nil {
+			return dbError(err)
+		}
+
+		for _, el := range categoryIDs {
+			if err := tx.Create(&models.TenderCategoryLink{TenderID: tenderID, CategoryID: el}).Error; err != nil {
+				return dbError(err)
+			}
+		}
+		return nil
+	})
+}
+
+// ExtraTenderCategories returns the categories linked to the tender besides
+// the one of its service type.
+func (s *Storage) ExtraTenderCategories(ctx context.Context, tenderID uuid.UUID, serviceType string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	primary := s.db.WithContext(ctx).Model(&models.TenderCategory{}).Select("id").Where("name = ?", serviceType)
+	query := s.db.WithContext(ctx).Model(&models.TenderCategoryLink{})
+	result := query.Where("tender_id = ? AND category_id NOT IN (?)", tenderID, primary).Pluck("category_id", &ids)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return ids, nil
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) GetDeletedTender(ctx context.Context, tenderID uuid.UUID) (*models.Tender, error) {
+	var tender models.Tender
+	query := s.db.WithContext(ctx).Unscoped().Model(&models.Tender{})
+	result := query.Where("id = ? AND deleted_at IS NOT NULL", tenderID).First(&tender)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrTenderNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &tender, nil
+}
+
+// DeleteTender soft-deletes the tender together with its bids. The bids get
+// the same deletion time as the tender so that RestoreTender brings back
+// exactly them and not the ones deleted on their own.
+func (s *Storage) DeleteTender(ctx context.Context, tenderID uuid.UUID, at time.Time) (int64, error) {
+	var bids int64
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Bid{}).Where("tender_id = ?", tenderID).Update("deleted_at", at)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+		bids = result.RowsAffected
+
+		result = tx.Model(&models.Tender{}).Where("id = ?", tenderID).Update("deleted_at", at)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return bids, nil
+}
+
+func (s *Storage) RestoreTender(ctx context.Context, tender *models.Tender) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.Bid{}).
+			Where("tender_id = ? AND deleted_at = ?", tender.ID, tender.DeletedAt.Time).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+
+		result = tx.Unscoped().Model(&models.Tender{}).Where("id = ?", tender.ID).Update("deleted_at", nil)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+		return nil
+	})
+}
+
+func (s *Storage) GetDeletedBid(ctx context.Context, bidID uuid.UUID) (*models.Bid, error) {
+	var bid models.Bid
+	query := s.db.WithContext(ctx).Unscoped().Model(&models.Bid{})
+	result := query.Where("id = ? AND deleted_at IS NOT NULL", bidID).First(&bid)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrBidNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &bid, nil
+}
+
+func (s *Storage) DeleteBid(ctx context.Context, bidID uuid.UUID, at time.Time) error {
+	result := s.db.WithContext(ctx).Model(&models.Bid{}).Where("id = ?", bidID).Update("deleted_at", at)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) RestoreBid(ctx context.Context, bidID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Unscoped().Model(&models.Bid{}).Where("id = ?", bidID).Update("deleted_at", nil)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// DeletedTenders returns the deleted tenders, the latest deleted first.
+func (s *Storage) DeletedTenders(ctx context.Context, page service.Page) ([]models.Tender, error) {
+	var tenders []models.Tender
+	query := s.db.WithContext(ctx).Unscoped().Model(&models.Tender{}).Where("deleted_at IS NOT NULL")
+	query = query.Order("deleted_at DESC").Limit(int(page.Limit)).Offset(int(page.Offset))
+
+	result := query.Find(&tenders)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return tenders, nil
+}
+
+// DeletedBids returns the deleted bids, the latest deleted first.
+func (s *Storage) DeletedBids(ctx context.Context, page service.Page) ([]models.Bid, error) {
+	var bids []models.Bid
+	query := s.db.WithContext(ctx).Unscoped().Model(&models.Bid{}).Where("deleted_at IS NOT NULL")
+	query = query.Order("deleted_at DESC").Limit(int(page.Limit)).Offset(int(page.Offset))
+
+	result := query.Find(&bids)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return bids, nil
+}
+
+// PurgeDeleted removes tenders and bids soft-deleted before the given time
+// for good, along with their version history and feedback. It returns the
+// number of removed tenders and bids.
+func (s *Storage) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tenderIDs []uuid.UUID
+		result := tx.Unscoped().Model(&models.Tender{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Pluck("id", &tenderIDs)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+
+		var bidIDs []uuid.UUID
+		result = tx.Unscoped().Model(&models.Bid{}).
+			Where("(deleted_at IS NOT NULL AND deleted_at < ?) OR tender_id IN ?", before, append(tenderIDs, uuid.Nil)).
+			Pluck("id", &bidIDs)
+		if result.Error != nil {
+			return dbError(result.Error)
+		}
+
+		if len(tenderIDs) == 0 && len(bidIDs) == 0 {
+			return nil
+		}
+		bidIDs = append(bidIDs, uuid.Nil)
+		tenderIDs = append(tenderIDs, uuid.Nil)
+
+		// children go first as the foreign keys don't cascade
+		steps := []struct {
+			model any
+			where string
+			args  []any
+			count bool
+		}{
+			{&models.BidFeedback{}, "bid_id IN ?", []any{bidIDs}, false},
+			{&models.BidVersion{}, "bid_id IN ? OR tender_id IN ?", []any{bidIDs, tenderIDs}, false},
+			{&models.Bid{}, "id IN ?", []any{bidIDs}, true},
+			{&models.TenderVersion{}, "tender_id IN ?", []any{tenderIDs}, false},
+			{&models.Tender{}, "id IN ?", []any{tenderIDs}, true},
+		}
+
+		for _, el := range steps {
+			result = tx.Unscoped().Where(el.where, el.args...).Delete(el.model)
+			if result.Error != nil {
+				return dbError(result.Error)
+			}
+			if el.count {
+				purged += result.RowsAffected
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) GetUser(ctx context.Context, userName string) (*models.Employee, error) {
+	if userName == "" {
+		return &models.Employee{}, response.ErrUserNotExists
+	}
+	var user models.Employee
+	query := s.db.WithContext(ctx).Model(&models.Employee{})
+	result := query.Where("username = ?", userName).First(&user)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return &models.Employee{}, response.ErrUserNotExists
+		}
+		return &models.Employee{}, dbError(result.Error)
+	}
+	return &user, nil
+}
+
+func (s *Storage) GetUserById(ctx context.Context, userID uuid.UUID) (*models.Employee, error) {
+	if userID == uuid.Nil {
+		return &models.Employee{}, response.ErrUserNotExists
+	}
+	var user models.Employee
+	query := s.db.WithContext(ctx).Model(&models.Employee{})
+	result := query.Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return &models.Employee{}, response.ErrUserNotExists
+		}
+		return &models.Employee{}, dbError(result.Error)
+	}
+	return &user, nil
+}
+
+func (s *Storage) Employees(ctx context.Context, userNames []string) ([]models.Employee, error) {
+	var employees []models.Employee
+	result := s.db.WithContext(ctx).Model(&models.Employee{}).Where("username IN ?", append(userNames, "")).Find(&employees)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return employees, nil
+}
+
+func (s *Storage) Memberships(ctx context.Context, userID uuid.UUID) ([]models.OrganizationResponsible, error) {
+	var members []models.OrganizationResponsible
+	query := s.db.WithContext(ctx).Model(&models.OrganizationResponsible{}).Preload("Organization")
+	result := query.Where("user_id = ?", userID).Find(&members)
+
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return members, nil
+}
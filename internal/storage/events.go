@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// EventsChannel is the channel the IDs of new events are announced on, so
+// the streams of every replica learn about them.
+const EventsChannel = "tender_events"
+
+// CreateEvent saves the event under the next ID of event_sequence and
+// announces it on EventsChannel. The sequence row stays locked until the
+// transaction commits, so the IDs become visible in increasing order and a
+// reader never skips an event committed later under a smaller ID.
+func (s *Storage) CreateEvent(ctx context.Context, event *models.Event) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Raw("UPDATE event_sequence SET value = value + 1 WHERE id = 1 RETURNING value").Scan(&event.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Create(event)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Exec("SELECT pg_notify(?, ?)", EventsChannel, event.ID).Error
+	})
+	if err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+// Events returns up to limit events following afterID, oldest first.
+func (s *Storage) Events(ctx context.Context, afterID uint64, limit int) ([]models.Event, error) {
+	var events []models.Event
+	result := s.db.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&events)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return events, nil
+}
+
+// LastEventID returns the ID of the latest event, 0 when there are none.
+func (s *Storage) LastEventID(ctx context.Context) (uint64, error) {
+	var id uint64
+	result := s.db.WithContext(ctx).Raw("SELECT value FROM event_sequence WHERE id = 1").Scan(&id)
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+	return id, nil
+}
+
+// PurgeEvents removes the events created before the given time and returns
+// how many were removed.
+func (s *Storage) PurgeEvents(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.Event{})
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ListenEvents calls fn once it listens on EventsChannel and then on every
+// event announced there, until ctx is done or the connection fails. Events
+// announced while it was not listening are not replayed, so fn should make
+// the readers look for new events in the table rather than trust the
+// announcements alone.
+func ListenEvents(ctx context.Context, dsn string, fn func()) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+EventsChannel)
+	if err != nil {
+		return err
+	}
+
+	fn()
+	for {
+		_, err = conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		fn()
+	}
+}
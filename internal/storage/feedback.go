@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) GetBidMessage(ctx context.Context, bidID uuid.UUID, messageID uuid.UUID) (*models.BidFeedback, error) {
+	var message models.BidFeedback
+	query := s.db.WithContext(ctx).Model(&models.BidFeedback{})
+	result := query.Where("id = ? AND bid_id = ?", messageID, bidID).First(&message)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrMessageNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &message, nil
+}
+
+func (s *Storage) BidMessages(ctx context.Context, bidIDs []uuid.UUID) ([]models.BidFeedback, error) {
+	var messages []models.BidFeedback
+	query := s.db.WithContext(ctx).Model(&models.BidFeedback{})
+	result := query.Where("bid_id IN ?", append(bidIDs, uuid.Nil)).Order("created_at").Find(&messages)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return messages, nil
+}
+
+func (s *Storage) EachReview(ctx context.Context, filter service.BidFilter, fn func(el models.BidFeedback) error) error {
+	query := s.db.WithContext(ctx).Model(&models.BidFeedback{})
+	query = query.Where("bid_id IN (?)", s.bids(ctx, filter).Select("id")).Order("created_at")
+
+	return eachRow(query, fn)
+}
+
+func (s *Storage) CreateBidMessage(ctx context.Context, message *models.BidFeedback) error {
+	result := s.db.WithContext(ctx).Create(message)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) SaveBidMessage(ctx context.Context, message *models.BidFeedback) error {
+	result := s.db.WithContext(ctx).Save(message)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	result := s.db.WithContext(ctx).Where("key = ? AND expires_at < ?", key.Key, time.Now()).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return false, dbError(result.Error)
+	}
+
+	result = s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, dbError(result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (s *Storage) GetIdempotencyKey(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	query := s.db.WithContext(ctx).Model(&models.IdempotencyKey{})
+	result := query.Where("key = ?", key).First(&record)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return &models.IdempotencyKey{}, response.ErrIdempotencyKeyNotExists
+		}
+		return &models.IdempotencyKey{}, dbError(result.Error)
+	}
+	return &record, nil
+}
+
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	result := s.db.WithContext(ctx).Model(&models.IdempotencyKey{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"completed":    true,
+		"status_code":  statusCode,
+		"content_type": contentType,
+		"body":         body,
+	})
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	result := s.db.WithContext(ctx).Where("key = ? AND completed = ?", key, false).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -2,23 +2,29 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"tender_service/internal/config"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
 )
 
+// Storage is the gorm implementation of service.Repository, plus what the
+// operators' tenderctl and the middlewares need.
 type Storage struct {
 	db *gorm.DB
 }
 
+var _ service.Repository = (*Storage)(nil)
+
 func New(cancel context.CancelFunc, s *Storage, cfg *config.Config) error {
 	const op = "storage.postgres.New"
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s", cfg.DB.Host, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.Port)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(cfg.DB.DSN()), &gorm.Config{})
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -34,6 +40,38 @@ func New(cancel context.CancelFunc, s *Storage, cfg *config.Config) error {
 	return nil
 }
 
+// SetLogLevel changes how much of the SQL is logged, all of it by default.
+func (s *Storage) SetLogLevel(level logger.LogLevel) {
+	s.db.Logger = s.db.Logger.LogMode(level)
+}
+
+// errRolledBack makes a transaction roll back after a dry run without
+// failing the operation itself.
+var errRolledBack = errors.New("rolled back")
+
+// Transaction runs fn with a storage working inside a single transaction,
+// committed when fn returns no error.
+func (s *Storage) Transaction(ctx context.Context, fn func(tx *Storage) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(s.withDB(tx))
+	})
+}
+
+// WithinTransaction is Transaction for the use cases.
+func (s *Storage) WithinTransaction(ctx context.Context, fn func(repo service.Repository) error) error {
+	return s.Transaction(ctx, func(tx *Storage) error {
+		return fn(tx)
+	})
+}
+
+// withDB returns a copy of the storage working through db, which lets the
+// methods run inside a transaction.
+func (s *Storage) withDB(db *gorm.DB) *Storage {
+	txs := *s
+	txs.db = db
+	return &txs
+}
+
 func Exec(db *gorm.DB) {
 
 	db.Exec(`
@@ -97,7 +135,7 @@ func Exec(db *gorm.DB) {
 				tender_id uuid,
 				name character varying(100) COLLATE pg_catalog."default" NOT NULL,
 				description character varying(500) COLLATE pg_catalog."default",
-				service_type tender_service_type,
+				service_type character varying(100) COLLATE pg_catalog."default",
 				status tender_status NOT NULL,
 				employee_username text COLLATE pg_catalog."default" NOT NULL,
 				organization_id uuid NOT NULL,
@@ -123,7 +161,7 @@ func Exec(db *gorm.DB) {
 				deleted_at timestamp with time zone,
 				name character varying(100) COLLATE pg_catalog."default" NOT NULL,
 				description character varying(500) COLLATE pg_catalog."default",
-				service_type tender_service_type,
+				service_type character varying(100) COLLATE pg_catalog."default",
 				status tender_status NOT NULL,
 				employee_username text COLLATE pg_catalog."default" NOT NULL,
 				organization_id uuid NOT NULL,
@@ -166,6 +204,17 @@ func Exec(db *gorm.DB) {
 			    ON bid_feedbacks USING btree
 				(deleted_at ASC NULLS LAST)
 
+`)
+
+	db.Exec(`
+			ALTER TABLE bid_feedbacks ADD COLUMN IF NOT EXISTS parent_id uuid
+				CONSTRAINT fk_bid_feedbacks_parent REFERENCES bid_feedbacks (id) ON DELETE CASCADE;
+			ALTER TABLE bid_feedbacks ADD COLUMN IF NOT EXISTS rating smallint
+				CONSTRAINT bid_feedbacks_rating_check CHECK (rating BETWEEN 1 AND 5);
+
+			CREATE INDEX IF NOT EXISTS idx_bid_feedbacks_bid_id
+				ON bid_feedbacks USING btree
+				(bid_id ASC NULLS LAST);
 `)
 
 	db.Exec(`
@@ -235,6 +284,286 @@ func Exec(db *gorm.DB) {
 				(deleted_at ASC NULLS LAST)
 `)
 
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS idempotency_keys
+			(
+				key character varying(255) COLLATE pg_catalog."default" NOT NULL,
+				method character varying(10) COLLATE pg_catalog."default" NOT NULL,
+				path text COLLATE pg_catalog."default" NOT NULL,
+				fingerprint character(64) COLLATE pg_catalog."default" NOT NULL,
+				completed boolean NOT NULL DEFAULT false,
+				status_code integer,
+				content_type text COLLATE pg_catalog."default",
+				body bytea,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				expires_at timestamp with time zone NOT NULL,
+				CONSTRAINT idempotency_keys_pkey PRIMARY KEY (key)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at
+				ON idempotency_keys USING btree
+				(expires_at ASC NULLS LAST)
+`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS tender_categories
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				name character varying(100) COLLATE pg_catalog."default" NOT NULL,
+				parent_id uuid,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT tender_categories_pkey PRIMARY KEY (id),
+				CONSTRAINT tender_categories_name_key UNIQUE (name),
+				CONSTRAINT fk_tender_categories_parent FOREIGN KEY (parent_id)
+					REFERENCES tender_categories (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE RESTRICT
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_tender_categories_parent_id
+				ON tender_categories USING btree
+				(parent_id ASC NULLS LAST);
+
+			CREATE TABLE IF NOT EXISTS tender_category_links
+			(
+				tender_id uuid NOT NULL,
+				category_id uuid NOT NULL,
+				CONSTRAINT tender_category_links_pkey PRIMARY KEY (tender_id, category_id),
+				CONSTRAINT fk_tender_category_links_tender FOREIGN KEY (tender_id)
+					REFERENCES tenders (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE,
+				CONSTRAINT fk_tender_category_links_category FOREIGN KEY (category_id)
+					REFERENCES tender_categories (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE RESTRICT
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_tender_category_links_category_id
+				ON tender_category_links USING btree
+				(category_id ASC NULLS LAST);
+`)
+
+	// service types used to be the tender_service_type enum
+	db.Exec(`
+			ALTER TABLE tenders ALTER COLUMN service_type TYPE character varying(100) USING service_type::text;
+			ALTER TABLE tender_versions ALTER COLUMN service_type TYPE character varying(100) USING service_type::text;
+
+			INSERT INTO tender_categories (name) VALUES ('Construction'), ('Delivery'), ('Manufacture')
+				ON CONFLICT (name) DO NOTHING;
+
+			INSERT INTO tender_category_links (tender_id, category_id)
+				SELECT t.id, c.id FROM tenders t JOIN tender_categories c ON c.name = t.service_type
+				ON CONFLICT DO NOTHING;
+`)
+
+	db.Exec(`
+			DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'tender_visibility') THEN
+								CREATE TYPE tender_visibility AS ENUM (
+									'Public',
+									'Organizations',
+									'Invitation'
+									);
+				END IF;
+			END $$;
+
+			ALTER TABLE tenders ADD COLUMN IF NOT EXISTS visibility tender_visibility NOT NULL DEFAULT 'Public';
+			ALTER TABLE tenders ADD COLUMN IF NOT EXISTS sealed_bids boolean NOT NULL DEFAULT false;
+			ALTER TABLE tenders ADD COLUMN IF NOT EXISTS submission_deadline timestamp with time zone;
+`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS tender_invitations
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				tender_id uuid NOT NULL,
+				organization_id uuid,
+				employee_id uuid,
+				created_by text COLLATE pg_catalog."default" NOT NULL,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT tender_invitations_pkey PRIMARY KEY (id),
+				CONSTRAINT fk_tender_invitations_tender FOREIGN KEY (tender_id)
+					REFERENCES tenders (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE,
+				CONSTRAINT fk_tender_invitations_organization FOREIGN KEY (organization_id)
+					REFERENCES organization (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE,
+				CONSTRAINT fk_tender_invitations_employee FOREIGN KEY (employee_id)
+					REFERENCES employee (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE,
+				CONSTRAINT tender_invitations_target_check CHECK (num_nonnulls(organization_id, employee_id) = 1)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_tender_invitations_tender_id
+				ON tender_invitations USING btree
+				(tender_id ASC NULLS LAST);
+`)
+
+	db.Exec(`
+			DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'organization_role') THEN
+								CREATE TYPE organization_role AS ENUM (
+									'viewer',
+									'editor',
+									'approver',
+									'admin'
+									);
+				END IF;
+			END $$;
+
+			ALTER TABLE organization_responsible ADD COLUMN IF NOT EXISTS role organization_role NOT NULL DEFAULT 'admin';
+`)
+
+	db.Exec(`
+			ALTER TABLE bids ADD COLUMN IF NOT EXISTS submitted_at timestamp with time zone;
+
+			CREATE TABLE IF NOT EXISTS reputation_stats
+			(
+				organization_id uuid NOT NULL,
+				employee_username text COLLATE pg_catalog."default" NOT NULL DEFAULT '',
+				service_type character varying(100) COLLATE pg_catalog."default" NOT NULL,
+				bids_submitted bigint NOT NULL DEFAULT 0,
+				bids_approved bigint NOT NULL DEFAULT 0,
+				bids_rejected bigint NOT NULL DEFAULT 0,
+				rating_sum bigint NOT NULL DEFAULT 0,
+				rating_count bigint NOT NULL DEFAULT 0,
+				updated_at timestamp with time zone,
+				CONSTRAINT reputation_stats_pkey PRIMARY KEY (organization_id, employee_username, service_type),
+				CONSTRAINT fk_reputation_stats_organization FOREIGN KEY (organization_id)
+					REFERENCES organization (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE
+			);
+`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS status_audit
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				entity_type character varying(20) COLLATE pg_catalog."default" NOT NULL,
+				entity_id uuid NOT NULL,
+				from_status text COLLATE pg_catalog."default" NOT NULL,
+				to_status text COLLATE pg_catalog."default" NOT NULL,
+				reason text COLLATE pg_catalog."default" NOT NULL,
+				actor text COLLATE pg_catalog."default" NOT NULL,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT status_audit_pkey PRIMARY KEY (id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_status_audit_entity_id
+				ON status_audit USING btree
+				(entity_id ASC NULLS LAST);
+`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS event_sequence
+			(
+				id smallint NOT NULL,
+				value bigint NOT NULL,
+				CONSTRAINT event_sequence_pkey PRIMARY KEY (id)
+			);
+
+			INSERT INTO event_sequence (id, value) VALUES (1, 0) ON CONFLICT DO NOTHING;
+
+			CREATE TABLE IF NOT EXISTS events
+			(
+				id bigint NOT NULL,
+				type character varying(50) COLLATE pg_catalog."default" NOT NULL,
+				tender_id uuid NOT NULL,
+				organization_id uuid NOT NULL,
+				bid_id uuid,
+				bid_organization_id uuid,
+				status text COLLATE pg_catalog."default",
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT events_pkey PRIMARY KEY (id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_events_created_at
+				ON events USING btree
+				(created_at ASC NULLS LAST);
+`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS notification_settings
+			(
+				employee_id uuid NOT NULL,
+				email character varying(255) COLLATE pg_catalog."default",
+				locale character varying(10) COLLATE pg_catalog."default",
+				new_bid boolean NOT NULL DEFAULT true,
+				decision boolean NOT NULL DEFAULT true,
+				feedback boolean NOT NULL DEFAULT true,
+				tender_closing boolean NOT NULL DEFAULT true,
+				updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT notification_settings_pkey PRIMARY KEY (employee_id),
+				CONSTRAINT notification_settings_employee_id_fkey FOREIGN KEY (employee_id)
+					REFERENCES employee (id) MATCH SIMPLE
+					ON UPDATE CASCADE
+					ON DELETE CASCADE
+			);
+
+			CREATE TABLE IF NOT EXISTS notifications
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				kind character varying(50) COLLATE pg_catalog."default" NOT NULL,
+				employee_username character varying(50) COLLATE pg_catalog."default" NOT NULL,
+				email character varying(255) COLLATE pg_catalog."default" NOT NULL,
+				locale character varying(10) COLLATE pg_catalog."default",
+				data jsonb,
+				dedupe_key character varying(255) COLLATE pg_catalog."default",
+				attempts integer NOT NULL DEFAULT 0,
+				next_attempt_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				last_error text COLLATE pg_catalog."default",
+				sent_at timestamp with time zone,
+				failed_at timestamp with time zone,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT notifications_pkey PRIMARY KEY (id),
+				CONSTRAINT notifications_dedupe_key_key UNIQUE (dedupe_key)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_notifications_pending
+				ON notifications USING btree
+				(next_attempt_at ASC NULLS LAST)
+				WHERE sent_at IS NULL AND failed_at IS NULL;
+`)
+
+	// Bids and feedback existing before reputation_stats are counted once,
+	// later changes update the counters as they happen.
+	db.Exec(`
+			DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM reputation_stats) AND NOT EXISTS (SELECT 1 FROM bids WHERE submitted_at IS NOT NULL) THEN
+					UPDATE bids SET submitted_at = created_at
+						WHERE status IN ('Published', 'Approved', 'Rejected');
+
+					INSERT INTO reputation_stats (organization_id, employee_username, service_type,
+							bids_submitted, bids_approved, bids_rejected, rating_sum, rating_count, updated_at)
+						SELECT b.organization_id, e.username, t.service_type,
+							count(*) FILTER (WHERE b.submitted_at IS NOT NULL),
+							count(*) FILTER (WHERE b.status = 'Approved'),
+							count(*) FILTER (WHERE b.status = 'Rejected'),
+							coalesce(sum(f.rating_sum), 0),
+							coalesce(sum(f.rating_count), 0),
+							now()
+						FROM bids b
+						JOIN tenders t ON t.id = b.tender_id
+						CROSS JOIN LATERAL (VALUES (''), (b.employee_username)) AS e(username)
+						LEFT JOIN (
+							SELECT bid_id, sum(rating) AS rating_sum, count(rating) AS rating_count
+							FROM bid_feedbacks WHERE deleted_at IS NULL GROUP BY bid_id
+						) f ON f.bid_id = b.id
+						WHERE b.deleted_at IS NULL
+						GROUP BY b.organization_id, e.username, t.service_type;
+				END IF;
+			END $$;
+`)
+
 	db.Exec(`
 			DO $$
 			BEGIN
@@ -283,12 +612,154 @@ func Exec(db *gorm.DB) {
 									'Published',
 									'Canceled',
 									'Approved',
-									'Rejected'
+									'Rejected',
+									'Withdrawn'
 									);
 				END IF;
 			END $$;
 	`)
 
+	// Adding an enum value can't share a transaction with other statements
+	// on older servers.
+	db.Exec(`ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'Withdrawn';`)
+
+	db.Exec(`
+			ALTER TABLE bids ADD COLUMN IF NOT EXISTS withdrawal_reason text;
+			ALTER TABLE bid_versions ADD COLUMN IF NOT EXISTS withdrawal_reason text;
+	`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS tender_lots
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				tender_id uuid NOT NULL,
+				name character varying(100) COLLATE pg_catalog."default" NOT NULL,
+				description character varying(500) COLLATE pg_catalog."default",
+				quantity integer NOT NULL DEFAULT 1,
+				winners integer NOT NULL DEFAULT 1,
+				status character varying(20) COLLATE pg_catalog."default" NOT NULL DEFAULT 'Open',
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT tender_lots_pkey PRIMARY KEY (id),
+				CONSTRAINT fk_tender_lots_tender FOREIGN KEY (tender_id)
+					REFERENCES tenders (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE,
+				CONSTRAINT tender_lots_quantity_check CHECK (quantity > 0),
+				CONSTRAINT tender_lots_winners_check CHECK (winners > 0)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_tender_lots_tender_id
+				ON tender_lots USING btree
+				(tender_id ASC NULLS LAST);
+
+			ALTER TABLE bids ADD COLUMN IF NOT EXISTS lot_id uuid REFERENCES tender_lots (id) ON DELETE SET NULL;
+			ALTER TABLE bid_versions ADD COLUMN IF NOT EXISTS lot_id uuid REFERENCES tender_lots (id) ON DELETE SET NULL;
+
+			CREATE INDEX IF NOT EXISTS idx_bids_lot_id
+				ON bids USING btree
+				(lot_id ASC NULLS LAST);
+	`)
+
+	// durations are kept in nanoseconds, the way time.Duration is
+	db.Exec(`
+			ALTER TABLE tenders ADD COLUMN IF NOT EXISTS auction boolean NOT NULL DEFAULT false;
+
+			CREATE TABLE IF NOT EXISTS tender_auctions
+			(
+				tender_id uuid NOT NULL,
+				start_price numeric(15,2) NOT NULL,
+				min_decrement numeric(15,2) NOT NULL,
+				duration bigint NOT NULL,
+				extension bigint NOT NULL DEFAULT 0,
+				extended_until timestamp with time zone,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT tender_auctions_pkey PRIMARY KEY (tender_id),
+				CONSTRAINT fk_tender_auctions_tender FOREIGN KEY (tender_id)
+					REFERENCES tenders (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE
+			);
+
+			CREATE TABLE IF NOT EXISTS auction_offers
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				tender_id uuid NOT NULL,
+				bid_id uuid NOT NULL,
+				employee_username text COLLATE pg_catalog."default" NOT NULL,
+				price numeric(15,2) NOT NULL,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT auction_offers_pkey PRIMARY KEY (id),
+				CONSTRAINT fk_auction_offers_tender FOREIGN KEY (tender_id)
+					REFERENCES tender_auctions (tender_id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE,
+				CONSTRAINT fk_auction_offers_bid FOREIGN KEY (bid_id)
+					REFERENCES bids (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_auction_offers_tender_id
+				ON auction_offers USING btree
+				(tender_id ASC NULLS LAST, created_at ASC NULLS LAST);
+	`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS tender_questions
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				tender_id uuid NOT NULL,
+				question character varying(1000) COLLATE pg_catalog."default" NOT NULL,
+				employee_username text COLLATE pg_catalog."default" NOT NULL,
+				organization_id uuid NOT NULL,
+				answer character varying(1000) COLLATE pg_catalog."default",
+				public boolean NOT NULL DEFAULT false,
+				answered_by text COLLATE pg_catalog."default",
+				answered_at timestamp with time zone,
+				tender_version bigint,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT tender_questions_pkey PRIMARY KEY (id),
+				CONSTRAINT fk_tender_questions_tender FOREIGN KEY (tender_id)
+					REFERENCES tenders (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE,
+				CONSTRAINT fk_tender_questions_organization FOREIGN KEY (organization_id)
+					REFERENCES organization (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_tender_questions_tender_id
+				ON tender_questions USING btree
+				(tender_id ASC NULLS LAST, created_at ASC NULLS LAST);
+	`)
+
+	db.Exec(`
+			CREATE TABLE IF NOT EXISTS tender_templates
+			(
+				id uuid NOT NULL DEFAULT uuid_generate_v4(),
+				organization_id uuid NOT NULL,
+				name character varying(100) COLLATE pg_catalog."default" NOT NULL,
+				description character varying(500) COLLATE pg_catalog."default",
+				service_type character varying(100) COLLATE pg_catalog."default",
+				criteria jsonb,
+				employee_username text COLLATE pg_catalog."default" NOT NULL,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT tender_templates_pkey PRIMARY KEY (id),
+				CONSTRAINT fk_tender_templates_organization FOREIGN KEY (organization_id)
+					REFERENCES organization (id) MATCH SIMPLE
+					ON UPDATE NO ACTION
+					ON DELETE CASCADE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_tender_templates_organization_id
+				ON tender_templates USING btree
+				(organization_id ASC NULLS LAST, name ASC NULLS LAST);
+	`)
+
 	db.Exec(`
 			DO $$
 			BEGIN
@@ -396,3 +867,41 @@ func Exec(db *gorm.DB) {
 	;
 `)
 }
+
+// eachRow runs the query and passes the rows to fn as they are read, so a
+// result set never has to fit in memory.
+func eachRow[T any](query *gorm.DB, fn func(el T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var el T
+		err = query.ScanRows(rows, &el)
+		if err != nil {
+			return dbError(err)
+		}
+
+		err = fn(el)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return dbError(err)
+	}
+	return nil
+}
+
+func dbError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return response.ErrRequestCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return response.ErrDeadlineExceeded
+	}
+	return response.ErrInternalError
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) FindInvitation(ctx context.Context, tenderID uuid.UUID, orgID *uuid.UUID, employeeID *uuid.UUID) (*models.TenderInvitation, error) {
+	query := s.db.WithContext(ctx).Model(&models.TenderInvitation{}).Where("tender_id = ?", tenderID)
+	if orgID != nil {
+		query = query.Where("organization_id = ?", *orgID)
+	} else {
+		query = query.Where("employee_id = ?", employeeID)
+	}
+
+	var invitation models.TenderInvitation
+	result := query.First(&invitation)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrInvitationNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &invitation, nil
+}
+
+func (s *Storage) CreateInvitation(ctx context.Context, invitation *models.TenderInvitation) error {
+	result := s.db.WithContext(ctx).Create(invitation)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) Invitations(ctx context.Context, tenderID uuid.UUID) ([]service.Invitation, error) {
+	var invitations []struct {
+		models.TenderInvitation
+		Username string
+	}
+	result := s.db.WithContext(ctx).Table("tender_invitations").
+		Select("tender_invitations.*, employee.username").
+		Joins("LEFT JOIN employee ON employee.id = tender_invitations.employee_id").
+		Where("tender_invitations.tender_id = ?", tenderID).
+		Order("tender_invitations.created_at").
+		Scan(&invitations)
+
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+
+	res := make([]service.Invitation, 0, len(invitations))
+	for _, el := range invitations {
+		res = append(res, service.Invitation{
+			ID:               el.ID,
+			TenderID:         el.TenderID,
+			OrganizationID:   el.OrganizationID,
+			EmployeeUsername: el.Username,
+			CreatedAt:        el.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (s *Storage) Invited(ctx context.Context, tenderID uuid.UUID, userID uuid.UUID, orgIDs []uuid.UUID) (bool, error) {
+	var count int64
+	query := s.db.WithContext(ctx).Model(&models.TenderInvitation{}).Where("tender_id = ?", tenderID)
+	if len(orgIDs) > 0 {
+		query = query.Where("employee_id = ? OR organization_id IN ?", userID, orgIDs)
+	} else {
+		query = query.Where("employee_id = ?", userID)
+	}
+	result := query.Count(&count)
+	if result.Error != nil {
+		return false, dbError(result.Error)
+	}
+	return count > 0, nil
+}
+
+func (s *Storage) DeleteInvitation(ctx context.Context, tenderID uuid.UUID, invitationID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND tender_id = ?", invitationID, tenderID).Delete(&models.TenderInvitation{})
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return response.ErrInvitationNotExists
+	}
+	return nil
+}
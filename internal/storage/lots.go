@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) GetTenderLot(ctx context.Context, tenderID uuid.UUID, lotID uuid.UUID) (*models.TenderLot, error) {
+	var lot models.TenderLot
+	result := s.db.WithContext(ctx).Where("id = ? AND tender_id = ?", lotID, tenderID).First(&lot)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrLotNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &lot, nil
+}
+
+func (s *Storage) TenderLots(ctx context.Context, tenderID uuid.UUID) ([]models.TenderLot, error) {
+	return s.tenderLots(s.db.WithContext(ctx), tenderID)
+}
+
+// LockTenderLots returns the lots of the tender locked until the end of the
+// transaction.
+func (s *Storage) LockTenderLots(ctx context.Context, tenderID uuid.UUID) ([]models.TenderLot, error) {
+	return s.tenderLots(s.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), tenderID)
+}
+
+func (s *Storage) tenderLots(query *gorm.DB, tenderID uuid.UUID) ([]models.TenderLot, error) {
+	var lots []models.TenderLot
+	result := query.Where("tender_id = ?", tenderID).Order("created_at, id").Find(&lots)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return lots, nil
+}
+
+func (s *Storage) CreateTenderLot(ctx context.Context, lot *models.TenderLot) error {
+	result := s.db.WithContext(ctx).Create(lot)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) SaveTenderLot(ctx context.Context, lot *models.TenderLot) error {
+	result := s.db.WithContext(ctx).Save(lot)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) LotWinners(ctx context.Context, lotID uuid.UUID) (int64, error) {
+	var count int64
+	result := s.db.WithContext(ctx).Model(&models.Bid{}).
+		Where("lot_id = ? AND status = ?", lotID, models.BidApproved).
+		Count(&count)
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+	return count, nil
+}
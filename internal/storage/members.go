@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) GetOrganization(ctx context.Context, orgID uuid.UUID) (*models.Organization, error) {
+	if orgID == uuid.Nil {
+		return &models.Organization{}, response.ErrOrganizationNotExists
+	}
+	var organization models.Organization
+	query := s.db.WithContext(ctx).Model(&models.Organization{})
+	result := query.Where("id = ?", orgID).First(&organization)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return &models.Organization{}, response.ErrOrganizationNotExists
+		}
+		return &models.Organization{}, dbError(result.Error)
+	}
+	return &organization, nil
+}
+
+func (s *Storage) Organizations(ctx context.Context, orgIDs []uuid.UUID) ([]models.Organization, error) {
+	var organizations []models.Organization
+	result := s.db.WithContext(ctx).Model(&models.Organization{}).Where("id IN ?", append(orgIDs, uuid.Nil)).Find(&organizations)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return organizations, nil
+}
+
+func (s *Storage) UserOrganizations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := s.db.WithContext(ctx).Model(&models.OrganizationResponsible{})
+	result := query.Where("user_id = ?", userID).Pluck("organization_id", &ids)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return ids, nil
+}
+
+// MemberRole returns the role of the user in the organization, or
+// ErrMemberNotExists when the user is not its member.
+func (s *Storage) MemberRole(ctx context.Context, userID uuid.UUID, orgID uuid.UUID) (models.OrganizationRole, error) {
+	var member models.OrganizationResponsible
+	query := s.db.WithContext(ctx).Model(&models.OrganizationResponsible{})
+	result := query.Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return "", response.ErrMemberNotExists
+		}
+		return "", dbError(result.Error)
+	}
+	return member.Role, nil
+}
+
+func (s *Storage) Members(ctx context.Context, orgID uuid.UUID) ([]models.OrganizationResponsible, error) {
+	var members []models.OrganizationResponsible
+	query := s.db.WithContext(ctx).Model(&models.OrganizationResponsible{}).Preload("Employee")
+	result := query.Where("organization_id = ?", orgID).Find(&members)
+
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return members, nil
+}
+
+// LockAdmins locks the admins of the organization until the end of the
+// transaction, so that two demotions can't leave it without any.
+func (s *Storage) LockAdmins(ctx context.Context, orgID uuid.UUID) (int, error) {
+	var admins []models.OrganizationResponsible
+	result := s.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ? AND role = ?", orgID, models.AdminRole).
+		Find(&admins)
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+	return len(admins), nil
+}
+
+func (s *Storage) UpdateMemberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role models.OrganizationRole) error {
+	result := s.db.WithContext(ctx).Model(&models.OrganizationResponsible{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return response.ErrMemberNotExists
+	}
+	return nil
+}
@@ -12,6 +12,11 @@ type TenderStatus string
 type OrganizationType string
 type BidStatus string
 type BidAuthorType string
+type TenderVisibility string
+type OrganizationRole string
+type EventType string
+type NotificationKind string
+type LotStatus string
 
 const (
 	Construction TenderServiceType = "Construction"
@@ -25,6 +30,25 @@ const (
 	TenderClosed    TenderStatus = "Closed"
 )
 
+const (
+	LotOpen      LotStatus = "Open"
+	LotDecided   LotStatus = "Decided"
+	LotCancelled LotStatus = "Cancelled"
+)
+
+const (
+	PublicVisibility        TenderVisibility = "Public"
+	OrganizationsVisibility TenderVisibility = "Organizations"
+	InvitationVisibility    TenderVisibility = "Invitation"
+)
+
+const (
+	ViewerRole   OrganizationRole = "viewer"
+	EditorRole   OrganizationRole = "editor"
+	ApproverRole OrganizationRole = "approver"
+	AdminRole    OrganizationRole = "admin"
+)
+
 const (
 	IEOrganization  OrganizationType = "IE"
 	LLCOrganization OrganizationType = "LLC"
@@ -37,6 +61,9 @@ const (
 	BidCanceled  BidStatus = "Canceled"
 	BidApproved  BidStatus = "Approved"
 	BidRejected  BidStatus = "Rejected"
+	// BidWithdrawn is a published bid pulled back by its side, it may be
+	// resubmitted while the tender accepts bids
+	BidWithdrawn BidStatus = "Withdrawn"
 )
 
 const (
@@ -44,18 +71,139 @@ const (
 	BidAuthorUser         BidAuthorType = "User"
 )
 
+const (
+	NewBidNotification        NotificationKind = "new_bid"
+	DecisionNotification      NotificationKind = "decision"
+	FeedbackNotification      NotificationKind = "feedback"
+	TenderClosingNotification NotificationKind = "tender_closing"
+)
+
+const (
+	TenderStatusEvent EventType = "tender.status"
+	BidCreatedEvent   EventType = "bid.created"
+	BidStatusEvent    EventType = "bid.status"
+	BidDecisionEvent  EventType = "bid.decision"
+	BidFeedbackEvent  EventType = "bid.feedback"
+	// AuctionOfferEvent tells the auction participants the ranking changed
+	AuctionOfferEvent EventType = "auction.offer"
+)
+
 type Tender struct {
 	gorm.Model
+	ID          uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4()"`
+	Name        string            `gorm:"type:varchar(100);not null"`
+	Description string            `gorm:"type:varchar(500)"`
+	ServiceType TenderServiceType `gorm:"type:varchar(100)"`
+	Status      TenderStatus      `gorm:"type:tender_status;not null"`
+	Visibility  TenderVisibility  `gorm:"type:tender_visibility;not null;default:Public"`
+	// SealedBids hides bid contents from the tender owner until SubmissionDeadline
+	SealedBids         bool `gorm:"not null;default:false"`
+	SubmissionDeadline *time.Time
+	EmployeeUsername   string    `gorm:"not null"`
+	OrganizationID     uuid.UUID `gorm:"not null"`
+	Organization       Organization
+	Version            uint      `gorm:"not null;default:1"`
+	CreatedAt          time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	// Auction is set when bidders compete on price in a TenderAuction
+	// round after SubmissionDeadline
+	Auction bool `gorm:"not null;default:false"`
+}
+
+// TenderLot is a part of a tender decided on its own. Bids on a tender
+// with lots are each made for one lot, and the tender closes once all of
+// its lots are decided or cancelled.
+type TenderLot struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
+	TenderID    uuid.UUID `gorm:"type:uuid;not null"`
+	Name        string    `gorm:"type:varchar(100);not null"`
+	Description string    `gorm:"type:varchar(500)"`
+	Quantity    int       `gorm:"not null;default:1"`
+	// Winners is how many bids may be approved in the lot, it is decided
+	// once that many are
+	Winners   int       `gorm:"not null;default:1"`
+	Status    LotStatus `gorm:"type:varchar(20);not null;default:Open"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TenderAuction is the reverse auction round of a tender. It starts when
+// the submission closes and lasts Duration; an offer made within the last
+// Extension of the round moves its end to Extension after the offer.
+type TenderAuction struct {
+	TenderID     uuid.UUID     `gorm:"type:uuid;primaryKey"`
+	StartPrice   float64       `gorm:"type:numeric(15,2);not null"`
+	MinDecrement float64       `gorm:"type:numeric(15,2);not null"`
+	Duration     time.Duration `gorm:"not null"`
+	Extension    time.Duration `gorm:"not null;default:0"`
+	// ExtendedUntil is the end of the round moved by late offers
+	ExtendedUntil *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// AuctionOffer is a price a bid offers in the auction round of its
+// tender, the latest offer of a bid is the one it is ranked by.
+type AuctionOffer struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
+	TenderID         uuid.UUID `gorm:"type:uuid;not null"`
+	BidID            uuid.UUID `gorm:"type:uuid;not null"`
+	EmployeeUsername string    `gorm:"not null"`
+	Price            float64   `gorm:"type:numeric(15,2);not null"`
+	CreatedAt        time.Time
+}
+
+// TemplateCriteria are the settings a template passes on to its tenders
+// besides the service type.
+type TemplateCriteria struct {
+	// Categories are linked besides the category of the service type
+	Categories []uuid.UUID      `json:"categories,omitempty"`
+	Visibility TenderVisibility `json:"visibility"`
+	SealedBids bool             `json:"sealedBids"`
+}
+
+// TenderTemplate is a tender an organization saved to issue again.
+type TenderTemplate struct {
 	ID               uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4()"`
+	OrganizationID   uuid.UUID         `gorm:"type:uuid;not null"`
 	Name             string            `gorm:"type:varchar(100);not null"`
 	Description      string            `gorm:"type:varchar(500)"`
-	ServiceType      TenderServiceType `gorm:"type:tender_service_type"`
-	Status           TenderStatus      `gorm:"type:tender_status;not null"`
+	ServiceType      TenderServiceType `gorm:"type:varchar(100)"`
+	Criteria         TemplateCriteria  `gorm:"type:jsonb;serializer:json"`
 	EmployeeUsername string            `gorm:"not null"`
-	OrganizationID   uuid.UUID         `gorm:"not null"`
-	Organization     Organization
-	Version          uint      `gorm:"not null;default:1"`
-	CreatedAt        time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// TenderQuestion is a clarification a bidder asks about a tender. A public
+// answer is shown to everyone who may see the tender without telling who
+// asked, a private one only to the asker's organization.
+type TenderQuestion struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
+	TenderID uuid.UUID `gorm:"type:uuid;not null"`
+	Question string    `gorm:"type:varchar(1000);not null"`
+
+	EmployeeUsername string    `gorm:"not null"`
+	OrganizationID   uuid.UUID `gorm:"type:uuid;not null"`
+
+	Answer     string `gorm:"type:varchar(1000)"`
+	Public     bool   `gorm:"not null;default:false"`
+	AnsweredBy string
+	AnsweredAt *time.Time
+	// TenderVersion is the version of the tender the answer amended
+	TenderVersion *uint
+
+	CreatedAt time.Time
+}
+
+// TenderInvitation grants access to an invitation-only tender either to a
+// whole organization or to a single employee.
+type TenderInvitation struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()"`
+	TenderID       uuid.UUID  `gorm:"type:uuid;not null"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid"`
+	EmployeeID     *uuid.UUID `gorm:"type:uuid"`
+	CreatedBy      string     `gorm:"not null"`
+	CreatedAt      time.Time
 }
 
 type TenderVersion struct {
@@ -64,7 +212,7 @@ type TenderVersion struct {
 	TenderID         uuid.UUID         `gorm:"type:uuid;"`
 	Name             string            `gorm:"type:varchar(100);not null"`
 	Description      string            `gorm:"type:varchar(500)"`
-	ServiceType      TenderServiceType `gorm:"type:tender_service_type"`
+	ServiceType      TenderServiceType `gorm:"type:varchar(100)"`
 	Status           TenderStatus      `gorm:"type:tender_status;not null"`
 	EmployeeUsername string            `gorm:"not null"`
 	OrganizationID   uuid.UUID         `gorm:"not null"`
@@ -81,6 +229,9 @@ type Bid struct {
 	Status      BidStatus `gorm:"type:bid_status;not null"`
 	TenderID    uuid.UUID
 	Tender      Tender
+	// LotID is the lot of the tender the bid is made for, nil on tenders
+	// without lots
+	LotID *uuid.UUID `gorm:"type:uuid"`
 
 	EmployeeUsername string    `gorm:"not null"`
 	OrganizationID   uuid.UUID `gorm:"not null"`
@@ -89,6 +240,11 @@ type Bid struct {
 	AuthorType BidAuthorType `gorm:"type:bid_author_type;not null"`
 	Version    uint32        `gorm:"default:1"`
 	CreatedAt  time.Time
+	// SubmittedAt is when the bid was published for the first time
+	SubmittedAt *time.Time
+	// WithdrawalReason is why the bid was withdrawn, kept until it is
+	// resubmitted
+	WithdrawalReason string
 }
 
 type BidVersion struct {
@@ -100,6 +256,9 @@ type BidVersion struct {
 
 	TenderID uuid.UUID
 	Tender   Tender
+	// LotID is the lot the bid was made for in this version, nil on
+	// tenders without lots and on versions saved before lots were kept
+	LotID *uuid.UUID `gorm:"type:uuid"`
 
 	BidID uuid.UUID
 	Bid   Bid
@@ -111,12 +270,17 @@ type BidVersion struct {
 	AuthorType BidAuthorType `gorm:"type:bid_author_type;not null"`
 	Version    uint32        `gorm:"default:1"`
 	CreatedAt  time.Time
+	// WithdrawalReason is set on the versions of withdrawn bids
+	WithdrawalReason string
 }
 
 type BidFeedback struct {
 	gorm.Model
 	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
 	Feedback string    `gorm:"type:varchar(1000);not null"`
+	// ParentID is the message this one replies to
+	ParentID *uuid.UUID `gorm:"type:uuid"`
+	Rating   *int       `gorm:"type:smallint"`
 
 	BidID uuid.UUID `gorm:"not null"`
 	Bid   Bid
@@ -151,6 +315,144 @@ type OrganizationResponsible struct {
 	Organization   Organization `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	EmployeeID     uuid.UUID    `gorm:"column:user_id"`
 	Employee       Employee     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// Role defaults to admin so that memberships created before roles keep
+	// their full rights
+	Role OrganizationRole `gorm:"type:organization_role;not null;default:admin"`
+}
+
+// ReputationStat holds the running counters of a bidder organization, or of
+// one of its employees, on tenders of a service type. EmployeeUsername is
+// empty for the organization's own counters.
+type ReputationStat struct {
+	OrganizationID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	EmployeeUsername string    `gorm:"primaryKey"`
+	ServiceType      string    `gorm:"type:varchar(100);primaryKey"`
+	BidsSubmitted    int64     `gorm:"not null;default:0"`
+	BidsApproved     int64     `gorm:"not null;default:0"`
+	BidsRejected     int64     `gorm:"not null;default:0"`
+	RatingSum        int64     `gorm:"not null;default:0"`
+	RatingCount      int64     `gorm:"not null;default:0"`
+	UpdatedAt        time.Time
+}
+
+// StatusAudit records a status change forced by an operator past the
+// usual rights and transition checks.
+type StatusAudit struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
+	EntityType string    `gorm:"type:varchar(20);not null"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null"`
+	FromStatus string    `gorm:"not null"`
+	ToStatus   string    `gorm:"not null"`
+	Reason     string    `gorm:"not null"`
+	Actor      string    `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+// Event is a change of a tender or of one of its bids pushed to the event
+// stream subscribers. IDs come from event_sequence in commit order, so a
+// subscriber that has seen an ID has seen every event before it.
+type Event struct {
+	ID   uint64    `gorm:"primaryKey;autoIncrement:false"`
+	Type EventType `gorm:"type:varchar(50);not null"`
+
+	TenderID uuid.UUID `gorm:"type:uuid;not null"`
+	// OrganizationID is the organization of the tender
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null"`
+
+	BidID             *uuid.UUID `gorm:"type:uuid"`
+	BidOrganizationID *uuid.UUID `gorm:"type:uuid"`
+
+	// Status is the new status of the tender or the bid, empty for feedback
+	Status    string
+	CreatedAt time.Time
+}
+
+type TenderCategory struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()"`
+	Name      string     `gorm:"type:varchar(100);unique;not null"`
+	ParentID  *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type TenderCategoryLink struct {
+	TenderID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CategoryID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+type IdempotencyKey struct {
+	Key         string `gorm:"type:varchar(255);primaryKey"`
+	Method      string `gorm:"type:varchar(10);not null"`
+	Path        string `gorm:"not null"`
+	Fingerprint string `gorm:"type:char(64);not null"`
+	Completed   bool   `gorm:"not null;default:false"`
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"not null"`
+}
+
+// NotificationSettings is where and which emails an employee wants. Without
+// a row or an email they get none.
+type NotificationSettings struct {
+	EmployeeID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Email      string    `gorm:"type:varchar(255)"`
+	// Locale is the language of the emails, the default one when empty
+	Locale string `gorm:"type:varchar(10)"`
+
+	NewBid        bool `gorm:"not null;default:true"`
+	Decision      bool `gorm:"not null;default:true"`
+	Feedback      bool `gorm:"not null;default:true"`
+	TenderClosing bool `gorm:"not null;default:true"`
+
+	UpdatedAt time.Time
+}
+
+// Wants tells whether the employee wants the emails of the kind.
+func (s *NotificationSettings) Wants(kind NotificationKind) bool {
+	switch kind {
+	case NewBidNotification:
+		return s.NewBid
+	case DecisionNotification:
+		return s.Decision
+	case FeedbackNotification:
+		return s.Feedback
+	case TenderClosingNotification:
+		return s.TenderClosing
+	}
+	return false
+}
+
+// Notification is an email waiting in the queue to be rendered and sent.
+// Sent ones have SentAt set, the ones given up on FailedAt.
+type Notification struct {
+	ID               uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4()"`
+	Kind             NotificationKind `gorm:"type:varchar(50);not null"`
+	EmployeeUsername string           `gorm:"not null"`
+	Email            string           `gorm:"type:varchar(255);not null"`
+	Locale           string           `gorm:"type:varchar(10)"`
+	Data             NotificationData `gorm:"type:jsonb;serializer:json"`
+	// DedupeKey keeps an email from being queued twice when it is set
+	DedupeKey *string `gorm:"type:varchar(255);unique"`
+
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	SentAt        *time.Time
+	FailedAt      *time.Time
+	CreatedAt     time.Time
+}
+
+// NotificationData is what the templates of the emails are rendered with.
+type NotificationData struct {
+	TenderID   uuid.UUID  `json:"tenderId"`
+	TenderName string     `json:"tenderName"`
+	BidID      *uuid.UUID `json:"bidId,omitempty"`
+	BidName    string     `json:"bidName,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
 }
 
 type Tabler interface {
@@ -167,3 +469,7 @@ func (Employee) TableName() string {
 func (OrganizationResponsible) TableName() string {
 	return "organization_responsible"
 }
+
+func (StatusAudit) TableName() string {
+	return "status_audit"
+}
@@ -1,7 +1,7 @@
 package models
 
-func ValidateTenderServiceType(t TenderServiceType) bool {
-	values := []TenderServiceType{Construction, Delivery, Manufacture}
+func ValidateTenderStatus(t TenderStatus) bool {
+	values := []TenderStatus{TenderCreated, TenderPublished, TenderClosed}
 
 	for _, el := range values {
 		if el == t {
@@ -11,8 +11,19 @@ func ValidateTenderServiceType(t TenderServiceType) bool {
 	return false
 }
 
-func ValidateTenderStatus(t TenderStatus) bool {
-	values := []TenderStatus{TenderCreated, TenderPublished, TenderClosed}
+func ValidateTenderVisibility(t TenderVisibility) bool {
+	values := []TenderVisibility{PublicVisibility, OrganizationsVisibility, InvitationVisibility}
+
+	for _, el := range values {
+		if el == t {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidateOrganizationRole(t OrganizationRole) bool {
+	values := []OrganizationRole{ViewerRole, EditorRole, ApproverRole, AdminRole}
 
 	for _, el := range values {
 		if el == t {
@@ -65,3 +76,16 @@ func ValidateBidAuthorType(t BidAuthorType) bool {
 	}
 	return false
 }
+
+// ValidateLotDecision tells whether a lot may be moved to t, open lots
+// only come out of creation.
+func ValidateLotDecision(t LotStatus) bool {
+	values := []LotStatus{LotDecided, LotCancelled}
+
+	for _, el := range values {
+		if el == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) GetNotificationSettings(ctx context.Context, employeeID uuid.UUID) (*models.NotificationSettings, error) {
+	var settings models.NotificationSettings
+	result := s.db.WithContext(ctx).Where("employee_id = ?", employeeID).First(&settings)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, response.ErrNotificationSettingsNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &settings, nil
+}
+
+func (s *Storage) SaveNotificationSettings(ctx context.Context, settings *models.NotificationSettings) error {
+	settings.UpdatedAt = time.Now()
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "employee_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "locale", "new_bid", "decision", "feedback", "tender_closing", "updated_at"}),
+	}).Create(settings)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// EnqueueNotification queues the email to be sent right away, unless one
+// with the same dedupe key has been queued before.
+func (s *Storage) EnqueueNotification(ctx context.Context, notification *models.Notification) error {
+	notification.NextAttemptAt = time.Now()
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// ClosingTenders returns the published tenders whose submission deadline
+// falls between from and to.
+func (s *Storage) ClosingTenders(ctx context.Context, from time.Time, to time.Time) ([]models.Tender, error) {
+	var tenders []models.Tender
+	result := s.db.WithContext(ctx).
+		Where("status = ? AND submission_deadline > ? AND submission_deadline <= ?", models.TenderPublished, from, to).
+		Order("submission_deadline").
+		Find(&tenders)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return tenders, nil
+}
+
+// ClaimNotifications takes up to limit emails due to be sent and counts the
+// attempt. They are not handed out again for lease, which keeps the
+// replicas from sending the same email while one of them is at it.
+func (s *Storage) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error) {
+	var notifications []models.Notification
+	result := s.db.WithContext(ctx).Raw(`
+		UPDATE notifications
+		SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE sent_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, time.Now().Add(lease), time.Now(), limit).Scan(&notifications)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return notifications, nil
+}
+
+func (s *Storage) CompleteNotification(ctx context.Context, id uuid.UUID) error {
+	result := s.db.WithContext(ctx).Model(&models.Notification{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sent_at":    time.Now(),
+		"last_error": "",
+	})
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// RetryNotification records why the email was not sent and when to try it
+// again, or gives up on it when next is nil.
+func (s *Storage) RetryNotification(ctx context.Context, id uuid.UUID, next *time.Time, reason string) error {
+	updates := map[string]interface{}{"last_error": reason}
+	if next != nil {
+		updates["next_attempt_at"] = *next
+	} else {
+		updates["failed_at"] = time.Now()
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.Notification{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// PurgeNotifications removes the emails sent or given up on before the
+// given time and returns how many were removed.
+func (s *Storage) PurgeNotifications(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("sent_at < ? OR failed_at < ?", before, before).
+		Delete(&models.Notification{})
+	if result.Error != nil {
+		return 0, dbError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) GetQuestion(ctx context.Context, tenderID uuid.UUID, questionID uuid.UUID) (*models.TenderQuestion, error) {
+	var question models.TenderQuestion
+	result := s.db.WithContext(ctx).Where("id = ? AND tender_id = ?", questionID, tenderID).First(&question)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrQuestionNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &question, nil
+}
+
+func (s *Storage) TenderQuestions(ctx context.Context, tenderID uuid.UUID) ([]models.TenderQuestion, error) {
+	var questions []models.TenderQuestion
+	result := s.db.WithContext(ctx).Where("tender_id = ?", tenderID).Order("created_at, id").Find(&questions)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return questions, nil
+}
+
+func (s *Storage) CreateQuestion(ctx context.Context, question *models.TenderQuestion) error {
+	result := s.db.WithContext(ctx).Create(question)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) SaveQuestion(ctx context.Context, question *models.TenderQuestion) error {
+	result := s.db.WithContext(ctx).Save(question)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
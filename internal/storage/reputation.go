@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordReputation adds delta to the counters of the bid's organization and
+// author on the service type of the bid's tender.
+func (s *Storage) RecordReputation(ctx context.Context, bid *models.Bid, delta service.ReputationDelta) error {
+	var tender models.Tender
+	result := s.db.WithContext(ctx).Unscoped().Model(&models.Tender{}).Where("id = ?", bid.TenderID).First(&tender)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+
+	now := time.Now()
+	var rows []models.ReputationStat
+	for _, username := range []string{"", bid.EmployeeUsername} {
+		rows = append(rows, models.ReputationStat{
+			OrganizationID:   bid.OrganizationID,
+			EmployeeUsername: username,
+			ServiceType:      string(tender.ServiceType),
+			BidsSubmitted:    delta.Submitted,
+			BidsApproved:     delta.Approved,
+			BidsRejected:     delta.Rejected,
+			RatingSum:        delta.RatingSum,
+			RatingCount:      delta.RatingCount,
+			UpdatedAt:        now,
+		})
+	}
+
+	result = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "organization_id"}, {Name: "employee_username"}, {Name: "service_type"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"bids_submitted": gorm.Expr("reputation_stats.bids_submitted + EXCLUDED.bids_submitted"),
+			"bids_approved":  gorm.Expr("reputation_stats.bids_approved + EXCLUDED.bids_approved"),
+			"bids_rejected":  gorm.Expr("reputation_stats.bids_rejected + EXCLUDED.bids_rejected"),
+			"rating_sum":     gorm.Expr("reputation_stats.rating_sum + EXCLUDED.rating_sum"),
+			"rating_count":   gorm.Expr("reputation_stats.rating_count + EXCLUDED.rating_count"),
+			"updated_at":     gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(&rows)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+// ReputationStats returns the counters of the organization, the ones of the
+// organization as a whole, with an empty username, included.
+func (s *Storage) ReputationStats(ctx context.Context, orgID uuid.UUID) ([]models.ReputationStat, error) {
+	var rows []models.ReputationStat
+	query := s.db.WithContext(ctx).Model(&models.ReputationStat{}).Where("organization_id = ?", orgID)
+	result := query.Order("employee_username, service_type").Find(&rows)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return rows, nil
+}
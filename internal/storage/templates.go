@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) GetTemplate(ctx context.Context, templateID uuid.UUID) (*models.TenderTemplate, error) {
+	var template models.TenderTemplate
+	result := s.db.WithContext(ctx).Where("id = ?", templateID).First(&template)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrTemplateNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &template, nil
+}
+
+func (s *Storage) OrganizationTemplates(ctx context.Context, orgID uuid.UUID) ([]models.TenderTemplate, error) {
+	var templates []models.TenderTemplate
+	result := s.db.WithContext(ctx).Where("organization_id = ?", orgID).Order("name, id").Find(&templates)
+	if result.Error != nil {
+		return nil, dbError(result.Error)
+	}
+	return templates, nil
+}
+
+func (s *Storage) CreateTemplate(ctx context.Context, template *models.TenderTemplate) error {
+	result := s.db.WithContext(ctx).Create(template)
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+	return nil
+}
+
+func (s *Storage) DeleteTemplate(ctx context.Context, templateID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Where("id = ?", templateID).Delete(&models.TenderTemplate{})
+	if result.Error != nil {
+		return dbError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return response.ErrTemplateNotExists
+	}
+	return nil
+}
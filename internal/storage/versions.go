@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"tender_service/internal/lib/response"
+	"tender_service/internal/service"
+	"tender_service/internal/storage/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) GetTenderVersion(ctx context.Context, tenderID uuid.UUID, version uint) (*models.TenderVersion, error) {
+	var tenderVersion models.TenderVersion
+
+	query := s.db.WithContext(ctx).Model(&models.TenderVersion{})
+	query = query.Where("version = ? AND tender_id = ? ", version, tenderID)
+	result := query.First(&tenderVersion)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrTenderNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &tenderVersion, nil
+}
+
+// EachTenderVersion passes the tender's versions to fn, oldest first.
+func (s *Storage) EachTenderVersion(ctx context.Context, tenderID uuid.UUID, page service.Page, fn func(el models.TenderVersion) error) error {
+	query := s.db.WithContext(ctx).Model(&models.TenderVersion{})
+	query = query.Where("tender_id = ?", tenderID).Order("version").Limit(int(page.Limit)).Offset(int(page.Offset))
+
+	return eachRow(query, fn)
+}
+
+func (s *Storage) GetBidVersion(ctx context.Context, bidID uuid.UUID, version uint) (*models.BidVersion, error) {
+	var bidVersion models.BidVersion
+
+	query := s.db.WithContext(ctx).Model(&models.BidVersion{})
+	query = query.Where("version = ? AND bid_id = ? ", version, bidID)
+	result := query.First(&bidVersion)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, response.ErrBidNotExists
+		}
+		return nil, dbError(result.Error)
+	}
+	return &bidVersion, nil
+}
+
+// EachBidVersion passes the bid's versions to fn, oldest first.
+func (s *Storage) EachBidVersion(ctx context.Context, bidID uuid.UUID, page service.Page, fn func(el models.BidVersion) error) error {
+	query := s.db.WithContext(ctx).Model(&models.BidVersion{})
+	query = query.Where("bid_id = ?", bidID).Order("version").Limit(int(page.Limit)).Offset(int(page.Offset))
+
+	return eachRow(query, fn)
+}